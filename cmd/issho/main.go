@@ -3,13 +3,16 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"os"
 	"os/signal"
 	"syscall"
 
 	"golang.org/x/sync/errgroup"
 
+	"github.com/jljl1337/issho/internal/cli"
 	"github.com/jljl1337/issho/internal/env"
 	"github.com/jljl1337/issho/internal/log"
 	"github.com/jljl1337/issho/internal/server"
@@ -18,6 +21,15 @@ import (
 func main() {
 	env.MustSetConstants()
 
+	// Run a command instead of the server if given, e.g. issho export
+	if len(os.Args) > 1 {
+		if err := cli.Run(os.Args[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, "Error: "+err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
 	log.SetCustomLogger()
 
 	// Start the server with graceful shutdown
@@ -0,0 +1,70 @@
+// Package cli implements the commands of the issho binary besides serving.
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/jljl1337/issho/internal/db"
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/payment"
+	"github.com/jljl1337/issho/internal/repository"
+	"github.com/jljl1337/issho/internal/service"
+	"github.com/jljl1337/issho/internal/storage"
+)
+
+// Run runs the command named by the first argument with the rest of the
+// arguments.
+func Run(args []string) error {
+	switch args[0] {
+	case "export":
+		return runExport(args[1:])
+	case "import":
+		return runImport(args[1:])
+	case "sync-products":
+		return runSyncProducts(args[1:])
+	default:
+		return fmt.Errorf("unknown command %q, expected export, import or sync-products", args[0])
+	}
+}
+
+// newEndpointService connects to and migrates the database, returning the
+// service on top of it. The database must be closed by the caller.
+func newEndpointService() (*sqlx.DB, *service.EndpointService, error) {
+	dbInstance, err := db.NewDB(env.DBType, env.SQLiteDbPath, env.SQLiteDbBusyTimeout, env.PostgresURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	if err := db.Migrate(dbInstance); err != nil {
+		dbInstance.Close()
+		return nil, nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	paymentProvider := payment.NewPaymentProvider(env.PaymentProvider, dbInstance)
+
+	storageProvider := storage.NewStorage(env.StorageProvider)
+
+	return dbInstance, service.NewEndpointService(dbInstance, paymentProvider, storageProvider), nil
+}
+
+// getUser returns the user with the username, on behalf of whom the command
+// is run.
+func getUser(ctx context.Context, dbInstance *sqlx.DB, username string) (*repository.User, error) {
+	if username == "" {
+		return nil, fmt.Errorf("-user is required")
+	}
+
+	userList, err := repository.New(dbInstance).GetUserByUsername(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if len(userList) == 0 {
+		return nil, fmt.Errorf("user %q not found", username)
+	}
+
+	return &userList[0], nil
+}
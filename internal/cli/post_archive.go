@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/jljl1337/issho/internal/service"
+)
+
+// authorMapFlag collects repeated name=username flags.
+type authorMapFlag map[string]string
+
+func (f authorMapFlag) String() string {
+	return fmt.Sprint(map[string]string(f))
+}
+
+func (f authorMapFlag) Set(value string) error {
+	i := strings.LastIndex(value, "=")
+	if i <= 0 || i == len(value)-1 {
+		return fmt.Errorf("expected name=username")
+	}
+
+	f[value[:i]] = value[i+1:]
+	return nil
+}
+
+func runExport(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	username := flags.String("user", "", "username of the owner exporting the posts")
+	output := flags.String("output", "posts.zip", "path of the ZIP archive to write")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	dbInstance, endpointService, err := newEndpointService()
+	if err != nil {
+		return err
+	}
+	defer dbInstance.Close()
+
+	user, err := getUser(ctx, dbInstance, *username)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(*output)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer file.Close()
+
+	err = endpointService.ExportPosts(ctx, service.ExportPostsParams{
+		User: *user,
+	}, file)
+	if err != nil {
+		return fmt.Errorf("failed to export posts: %w", err)
+	}
+
+	return file.Close()
+}
+
+func runImport(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	username := flags.String("user", "", "username of the owner importing the posts")
+	dryRun := flags.Bool("dry-run", false, "report the posts to import without importing them")
+	authorMap := authorMapFlag{}
+	flags.Var(authorMap, "author", "map an author in the front matter to a username, as name=username (repeatable)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: issho import [flags] <ZIP archive or directory>")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("a ZIP archive or directory is required")
+	}
+
+	source := flags.Arg(0)
+
+	info, err := os.Stat(source)
+	if err != nil {
+		return fmt.Errorf("failed to read source: %w", err)
+	}
+
+	var files fs.FS
+	if info.IsDir() {
+		files = os.DirFS(source)
+	} else {
+		archive, err := zip.OpenReader(source)
+		if err != nil {
+			return fmt.Errorf("failed to open ZIP archive: %w", err)
+		}
+		defer archive.Close()
+
+		files = archive
+	}
+
+	ctx := context.Background()
+
+	dbInstance, endpointService, err := newEndpointService()
+	if err != nil {
+		return err
+	}
+	defer dbInstance.Close()
+
+	user, err := getUser(ctx, dbInstance, *username)
+	if err != nil {
+		return err
+	}
+
+	result, err := endpointService.ImportPosts(ctx, service.ImportPostsParams{
+		User:      *user,
+		Files:     files,
+		AuthorMap: authorMap,
+		DryRun:    *dryRun,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to import posts: %w", err)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+)
+
+// runSyncProducts creates the payment provider products of products created
+// before products were synced with the provider, attaching their prices.
+func runSyncProducts(args []string) error {
+	flags := flag.NewFlagSet("sync-products", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	dbInstance, endpointService, err := newEndpointService()
+	if err != nil {
+		return err
+	}
+	defer dbInstance.Close()
+
+	count, err := endpointService.SyncProducts(ctx)
+	fmt.Printf("synced %d products with the payment provider\n", count)
+	if err != nil {
+		return fmt.Errorf("failed to sync products: %w", err)
+	}
+
+	return nil
+}
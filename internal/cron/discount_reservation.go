@@ -0,0 +1,28 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jljl1337/issho/internal/service"
+)
+
+// NewDiscountReservationTask returns a task that releases the reservations of
+// discounts of which the checkouts have expired without being paid.
+func NewDiscountReservationTask(endpointService *service.EndpointService) func() {
+	return func() {
+		slog.Info("Starting discount reservation release")
+
+		start := time.Now()
+
+		count, err := endpointService.ReleaseExpiredDiscountReservations(context.Background())
+		if err != nil {
+			slog.Error("Failed to release expired discount reservations: " + err.Error())
+			return
+		}
+
+		slog.Info(fmt.Sprintf("Discount reservation release completed in %s, %d reservations released", time.Since(start).String(), count))
+	}
+}
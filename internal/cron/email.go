@@ -28,6 +28,7 @@ func NewEmailTask(dbInstance *sqlx.DB, emailClient *email.EmailClient) func(cont
 				pendingEmailTasks, err := queries.GetQueueTask(ctx, repository.GetQueueTaskParams{
 					Lane:   env.QueueTaskLaneEmail,
 					Status: env.QueueTaskStatusPending,
+					Now:    generator.NowISO8601(),
 				})
 				if err != nil {
 					slog.Error("Failed to fetch pending emails: " + err.Error())
@@ -64,6 +65,11 @@ func NewEmailTask(dbInstance *sqlx.DB, emailClient *email.EmailClient) func(cont
 				ccList := splitStringToSlice(email.CcAddress, ";")
 				bccList := splitStringToSlice(email.BccAddress, ";")
 
+				listUnsubscribeURL := ""
+				if email.ListUnsubscribeURL != nil {
+					listUnsubscribeURL = *email.ListUnsubscribeURL
+				}
+
 				sendEmailSuccess := false
 
 				err = emailClient.SendEmail(
@@ -73,6 +79,7 @@ func NewEmailTask(dbInstance *sqlx.DB, emailClient *email.EmailClient) func(cont
 					bccList,
 					email.Subject,
 					email.Body,
+					listUnsubscribeURL,
 				)
 				if err != nil {
 					slog.Error("Failed to send email for email ID " + email.ID + ": " + err.Error())
@@ -0,0 +1,142 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/generator"
+	"github.com/jljl1337/issho/internal/repository"
+)
+
+// NewNewsletterTask returns a task that sends the posts published since the
+// last run to the confirmed newsletter subscribers, by queueing one email per
+// subscriber. Posts published longer than the lookback period ago are never
+// sent, so that enabling the newsletter does not send every existing post. A
+// post failing to be sent does not hold back the others.
+func NewNewsletterTask(dbInstance *sqlx.DB) func() {
+	return func() {
+		ctx := context.Background()
+		queries := repository.New(dbInstance)
+
+		posts, err := queries.GetNewsletterPendingPostList(ctx, repository.GetNewsletterPendingPostListParams{
+			PublishedAfter: generator.DurationFromNowISO8601(-time.Duration(env.NewsletterLookbackHours) * time.Hour),
+			Now:            generator.NowISO8601(),
+		})
+		if err != nil {
+			slog.Error("Failed to get posts pending for newsletter: " + err.Error())
+			return
+		}
+
+		for _, post := range posts {
+			start := time.Now()
+
+			count, err := sendNewsletterIssue(ctx, dbInstance, post)
+			if err != nil {
+				slog.Error("Failed to send newsletter for post ID " + post.ID + ": " + err.Error())
+				continue
+			}
+
+			slog.Info(fmt.Sprintf("Newsletter for post ID %s queued in %s, %d emails queued", post.ID, time.Since(start).String(), count))
+		}
+	}
+}
+
+// sendNewsletterIssue queues the newsletter emails of the post and records the
+// post as sent in one transaction, so that it is sent exactly once.
+func sendNewsletterIssue(ctx context.Context, dbInstance *sqlx.DB, post repository.Post) (int64, error) {
+	tx, err := dbInstance.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	queries := repository.New(tx)
+
+	now := generator.NowISO8601()
+	postURL := env.SiteURL + "/posts/" + post.ID
+
+	var count int64
+	cursorID := ""
+
+	for {
+		recipients, err := queries.GetNewsletterRecipientList(ctx, repository.GetNewsletterRecipientListParams{
+			Status:   env.NewsletterSubscriptionStatusConfirmed,
+			CursorID: cursorID,
+			PageSize: env.PageSizeMax,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to get newsletter recipients: %w", err)
+		}
+
+		if len(recipients) == 0 {
+			break
+		}
+
+		for _, recipient := range recipients {
+			unsubscribeURL := env.SiteURL + "/api/newsletter/unsubscribe?token=" + url.QueryEscape(recipient.Token)
+
+			email, err := queries.CreateEmail(ctx, repository.Email{
+				ID:          generator.NewULID(),
+				Type:        env.EmailTypeNewsletterPost,
+				ToAddress:   recipient.Email,
+				CcAddress:   "",
+				BccAddress:  "",
+				FromAddress: env.EmailFromAddress,
+				Subject:     post.Title,
+				Body: fmt.Sprintf(
+					"<h1>%s</h1><p>%s</p><p><a href=\"%s\">Read the post</a></p><hr><p><a href=\"%s\">Unsubscribe</a></p>",
+					html.EscapeString(post.Title),
+					html.EscapeString(post.Description),
+					html.EscapeString(postURL),
+					html.EscapeString(unsubscribeURL),
+				),
+				Status:             env.EmailStatusPending,
+				CreatedAt:          now,
+				UpdatedAt:          now,
+				ListUnsubscribeURL: &unsubscribeURL,
+			})
+			if err != nil {
+				return 0, fmt.Errorf("failed to create email: %w", err)
+			}
+
+			err = queries.CreateQueueTask(ctx, repository.QueueTask{
+				ID:        generator.NewULID(),
+				Lane:      env.QueueTaskLaneEmail,
+				Payload:   email.ID,
+				Status:    env.QueueTaskStatusPending,
+				CreatedAt: now,
+				UpdatedAt: now,
+			})
+			if err != nil {
+				return 0, fmt.Errorf("failed to create queue task: %w", err)
+			}
+
+			count++
+		}
+
+		cursorID = recipients[len(recipients)-1].ID
+	}
+
+	err = queries.CreateNewsletterIssue(ctx, repository.NewsletterIssue{
+		PostID:         post.ID,
+		RecipientCount: count,
+		CreatedAt:      now,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create newsletter issue: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return count, nil
+}
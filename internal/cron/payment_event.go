@@ -0,0 +1,118 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/generator"
+	"github.com/jljl1337/issho/internal/repository"
+	"github.com/jljl1337/issho/internal/service"
+)
+
+// paymentEventRetryDelay is the delay before the first retry of a payment
+// event, which doubles with each attempt.
+const paymentEventRetryDelay = 30 * time.Second
+
+// NewPaymentEventTask returns a task that processes the payment events in the
+// queue one at a time. A failed event is retried with exponential backoff,
+// and marked as failed after the maximum number of attempts.
+func NewPaymentEventTask(dbInstance *sqlx.DB, endpointService *service.EndpointService) func(context.Context) {
+	return func(ctx context.Context) {
+		queries := repository.New(dbInstance)
+
+		for {
+			select {
+			case <-ctx.Done():
+				slog.Info("Exiting payment event task")
+				return
+			default:
+				pendingTasks, err := queries.GetQueueTask(ctx, repository.GetQueueTaskParams{
+					Lane:   env.QueueTaskLanePaymentEvent,
+					Status: env.QueueTaskStatusPending,
+					Now:    generator.NowISO8601(),
+				})
+				if err != nil {
+					slog.Error("Failed to fetch pending payment events: " + err.Error())
+					continue
+				}
+
+				if len(pendingTasks) == 0 {
+					timer := time.NewTimer(10 * time.Second)
+					select {
+					case <-ctx.Done():
+						slog.Info("Exiting payment event task")
+						timer.Stop()
+						return
+					case <-timer.C:
+						continue
+					}
+				}
+
+				pendingTask := pendingTasks[0]
+
+				processErr := endpointService.ProcessPaymentEvent(ctx, pendingTask.Payload)
+
+				now := generator.NowISO8601()
+
+				if processErr == nil {
+					err = queries.UpdateQueueTaskStatusByID(ctx, repository.UpdateQueueTaskStatusByIDParams{
+						ID:        pendingTask.ID,
+						Status:    env.QueueTaskStatusSucceeded,
+						UpdatedAt: now,
+					})
+					if err != nil {
+						slog.Error("Failed to update payment event task status for event ID " + pendingTask.Payload + ": " + err.Error())
+						continue
+					}
+
+					slog.Info("Payment event processed for event ID " + pendingTask.Payload)
+					continue
+				}
+
+				attemptCount := pendingTask.AttemptCount + 1
+				errorMessage := processErr.Error()
+
+				slog.Error(fmt.Sprintf("Failed to process payment event ID %s, attempt %d: %s", pendingTask.Payload, attemptCount, errorMessage))
+
+				eventStatus := env.PaymentEventStatusPending
+				if attemptCount >= env.PaymentEventAttemptMax {
+					eventStatus = env.PaymentEventStatusFailed
+
+					err = queries.UpdateQueueTaskStatusByID(ctx, repository.UpdateQueueTaskStatusByIDParams{
+						ID:        pendingTask.ID,
+						Status:    env.QueueTaskStatusFailed,
+						UpdatedAt: now,
+					})
+				} else {
+					runAt := generator.DurationFromNowISO8601(paymentEventRetryDelay << (attemptCount - 1))
+
+					err = queries.UpdateQueueTaskRetryByID(ctx, repository.UpdateQueueTaskRetryByIDParams{
+						ID:           pendingTask.ID,
+						AttemptCount: attemptCount,
+						RunAt:        &runAt,
+						UpdatedAt:    now,
+					})
+				}
+				if err != nil {
+					slog.Error("Failed to update payment event task for event ID " + pendingTask.Payload + ": " + err.Error())
+					continue
+				}
+
+				err = queries.UpdatePaymentEventStatusByID(ctx, repository.UpdatePaymentEventStatusByIDParams{
+					ID:        pendingTask.Payload,
+					Status:    eventStatus,
+					Error:     &errorMessage,
+					UpdatedAt: now,
+				})
+				if err != nil {
+					slog.Error("Failed to update payment event status for event ID " + pendingTask.Payload + ": " + err.Error())
+				}
+			}
+		}
+	}
+}
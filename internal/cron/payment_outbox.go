@@ -0,0 +1,115 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/generator"
+	"github.com/jljl1337/issho/internal/repository"
+	"github.com/jljl1337/issho/internal/service"
+)
+
+// paymentOutboxRetryDelay is the delay before the first retry of a payment
+// outbox, which doubles with each attempt.
+const paymentOutboxRetryDelay = 30 * time.Second
+
+// NewPaymentOutboxTask returns a task that makes the changes recorded in the
+// payment outbox at the payment provider, one at a time and in the order they
+// were made locally. A failed change is retried with exponential backoff, and
+// marked as failed after the maximum number of attempts.
+func NewPaymentOutboxTask(dbInstance *sqlx.DB, endpointService *service.EndpointService) func(context.Context) {
+	return func(ctx context.Context) {
+		queries := repository.New(dbInstance)
+
+		for {
+			select {
+			case <-ctx.Done():
+				slog.Info("Exiting payment outbox task")
+				return
+			default:
+				pendingTasks, err := queries.GetQueueTask(ctx, repository.GetQueueTaskParams{
+					Lane:   env.QueueTaskLanePaymentOutbox,
+					Status: env.QueueTaskStatusPending,
+					Now:    generator.NowISO8601(),
+				})
+				if err != nil {
+					slog.Error("Failed to fetch pending payment outboxes: " + err.Error())
+					continue
+				}
+
+				if len(pendingTasks) == 0 {
+					timer := time.NewTimer(10 * time.Second)
+					select {
+					case <-ctx.Done():
+						slog.Info("Exiting payment outbox task")
+						timer.Stop()
+						return
+					case <-timer.C:
+						continue
+					}
+				}
+
+				pendingTask := pendingTasks[0]
+
+				processErr := endpointService.ProcessPaymentOutbox(ctx, pendingTask.Payload)
+
+				now := generator.NowISO8601()
+
+				if processErr == nil {
+					err = queries.UpdateQueueTaskStatusByID(ctx, repository.UpdateQueueTaskStatusByIDParams{
+						ID:        pendingTask.ID,
+						Status:    env.QueueTaskStatusSucceeded,
+						UpdatedAt: now,
+					})
+					if err != nil {
+						slog.Error("Failed to update payment outbox task status for outbox ID " + pendingTask.Payload + ": " + err.Error())
+						continue
+					}
+
+					slog.Info("Payment outbox processed for outbox ID " + pendingTask.Payload)
+					continue
+				}
+
+				attemptCount := pendingTask.AttemptCount + 1
+				errorMessage := processErr.Error()
+
+				slog.Error(fmt.Sprintf("Failed to process payment outbox ID %s, attempt %d: %s", pendingTask.Payload, attemptCount, errorMessage))
+
+				if attemptCount < env.PaymentOutboxAttemptMax {
+					runAt := generator.DurationFromNowISO8601(paymentOutboxRetryDelay << (attemptCount - 1))
+
+					err = queries.UpdateQueueTaskRetryByID(ctx, repository.UpdateQueueTaskRetryByIDParams{
+						ID:           pendingTask.ID,
+						AttemptCount: attemptCount,
+						RunAt:        &runAt,
+						UpdatedAt:    now,
+					})
+					if err != nil {
+						slog.Error("Failed to update payment outbox task for outbox ID " + pendingTask.Payload + ": " + err.Error())
+					}
+					continue
+				}
+
+				err = queries.UpdateQueueTaskStatusByID(ctx, repository.UpdateQueueTaskStatusByIDParams{
+					ID:        pendingTask.ID,
+					Status:    env.QueueTaskStatusFailed,
+					UpdatedAt: now,
+				})
+				if err != nil {
+					slog.Error("Failed to update payment outbox task for outbox ID " + pendingTask.Payload + ": " + err.Error())
+					continue
+				}
+
+				err = endpointService.FailPaymentOutbox(ctx, pendingTask.Payload, errorMessage)
+				if err != nil {
+					slog.Error("Failed to update payment outbox status for outbox ID " + pendingTask.Payload + ": " + err.Error())
+				}
+			}
+		}
+	}
+}
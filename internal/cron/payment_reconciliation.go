@@ -0,0 +1,28 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jljl1337/issho/internal/service"
+)
+
+// NewPaymentReconciliationTask returns a task that reconciles the payment
+// provider with the database, of which the issues are reported to the owner.
+func NewPaymentReconciliationTask(endpointService *service.EndpointService) func() {
+	return func() {
+		slog.Info("Starting payment reconciliation")
+
+		start := time.Now()
+
+		count, err := endpointService.ReconcilePayments(context.Background())
+		if err != nil {
+			slog.Error("Failed to reconcile payments: " + err.Error())
+			return
+		}
+
+		slog.Info(fmt.Sprintf("Payment reconciliation completed in %s, %d issues found", time.Since(start).String(), count))
+	}
+}
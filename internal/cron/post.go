@@ -0,0 +1,80 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/generator"
+	"github.com/jljl1337/issho/internal/repository"
+	"github.com/jljl1337/issho/internal/storage"
+)
+
+// NewPostPurgeTask returns a task that permanently deletes the posts that have
+// been in the trash for longer than the retention period, together with the
+// stored objects of their attachments.
+func NewPostPurgeTask(dbInstance *sqlx.DB, storageProvider storage.Storage) func() {
+	return func() {
+		slog.Info("Starting post trash purge")
+
+		start := time.Now()
+
+		ctx := context.Background()
+		queries := repository.New(dbInstance)
+		deletedBefore := generator.DurationFromNowISO8601(-time.Duration(env.PostTrashRetentionDays) * 24 * time.Hour)
+
+		count := 0
+
+		for {
+			posts, err := queries.GetPurgeablePostList(ctx, repository.GetPurgeablePostListParams{
+				DeletedBefore: deletedBefore,
+				PageSize:      env.PageSizeMax,
+			})
+			if err != nil {
+				slog.Error("Failed to get purgeable posts: " + err.Error())
+				return
+			}
+
+			if len(posts) == 0 {
+				break
+			}
+
+			for _, post := range posts {
+				// The attachment rows are deleted with the post, but not the
+				// stored objects
+				attachments, err := queries.GetAttachmentListByPostID(ctx, post.ID)
+				if err != nil {
+					slog.Error("Failed to get attachments for post ID " + post.ID + ": " + err.Error())
+					return
+				}
+
+				err = queries.DeletePostByID(ctx, post.ID)
+				if err != nil {
+					slog.Error("Failed to delete post ID " + post.ID + ": " + err.Error())
+					return
+				}
+
+				for _, attachment := range attachments {
+					keys := []string{attachment.StorageKey}
+					if attachment.ThumbnailStorageKey != nil {
+						keys = append(keys, *attachment.ThumbnailStorageKey)
+					}
+
+					for _, key := range keys {
+						if err := storageProvider.Delete(ctx, key); err != nil {
+							slog.Error("Failed to delete attachment object " + key + ": " + err.Error())
+						}
+					}
+				}
+
+				count++
+			}
+		}
+
+		slog.Info(fmt.Sprintf("Post trash purge completed in %s, %d posts deleted", time.Since(start).String(), count))
+	}
+}
@@ -0,0 +1,58 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/jljl1337/issho/internal/generator"
+	"github.com/jljl1337/issho/internal/repository"
+)
+
+// NewPostViewRollupTask returns a task that adds the post views before today
+// to the daily view counts, and deletes them. The views of today are kept to
+// count each visitor only once per day.
+func NewPostViewRollupTask(dbInstance *sqlx.DB) func() {
+	return func() {
+		slog.Info("Starting post view rollup")
+
+		start := time.Now()
+
+		ctx := context.Background()
+		today := generator.DaysFromTodayDate(0)
+
+		tx, err := dbInstance.BeginTxx(ctx, nil)
+		if err != nil {
+			slog.Error("Failed to begin transaction: " + err.Error())
+			return
+		}
+		defer tx.Rollback()
+
+		queries := repository.New(tx)
+
+		rows, err := queries.RollupPostView(ctx, repository.RollupPostViewParams{
+			ViewDateBefore: today,
+			Now:            generator.NowISO8601(),
+		})
+		if err != nil {
+			slog.Error("Failed to roll up post views: " + err.Error())
+			return
+		}
+
+		count, err := queries.DeletePostViewByViewDateBefore(ctx, today)
+		if err != nil {
+			slog.Error("Failed to delete rolled up post views: " + err.Error())
+			return
+		}
+
+		if err := tx.Commit(); err != nil {
+			slog.Error("Failed to commit transaction: " + err.Error())
+			return
+		}
+
+		slog.Info(fmt.Sprintf("Post view rollup completed in %s, %d views rolled up into %d daily counts", time.Since(start).String(), count, rows))
+	}
+}
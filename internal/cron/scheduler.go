@@ -14,9 +14,11 @@ import (
 	"github.com/jljl1337/issho/internal/env"
 	"github.com/jljl1337/issho/internal/generator"
 	"github.com/jljl1337/issho/internal/repository"
+	"github.com/jljl1337/issho/internal/service"
+	"github.com/jljl1337/issho/internal/storage"
 )
 
-func NewScheduler(dbInstance *sqlx.DB, emailClient *email.EmailClient) (gocron.Scheduler, error) {
+func NewScheduler(dbInstance *sqlx.DB, emailClient *email.EmailClient, storageProvider storage.Storage, endpointService *service.EndpointService) (gocron.Scheduler, error) {
 	scheduler, err := gocron.NewScheduler()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create scheduler: %w", err)
@@ -89,6 +91,91 @@ func NewScheduler(dbInstance *sqlx.DB, emailClient *email.EmailClient) (gocron.S
 		slog.Warn("Session cleanup cron job not scheduled")
 	}
 
+	// Post trash purge job
+	if env.PostTrashPurgeCronSchedule != "" {
+		_, err = scheduler.NewJob(
+			gocron.CronJob(
+				env.PostTrashPurgeCronSchedule,
+				false,
+			),
+			gocron.NewTask(NewPostPurgeTask(dbInstance, storageProvider)),
+			gocron.WithSingletonMode(gocron.LimitModeReschedule),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create post trash purge cron job: %w", err)
+		}
+	} else {
+		slog.Warn("Post trash purge cron job not scheduled")
+	}
+
+	// Post view rollup job
+	if env.PostViewRollupCronSchedule != "" {
+		_, err = scheduler.NewJob(
+			gocron.CronJob(
+				env.PostViewRollupCronSchedule,
+				false,
+			),
+			gocron.NewTask(NewPostViewRollupTask(dbInstance)),
+			gocron.WithSingletonMode(gocron.LimitModeReschedule),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create post view rollup cron job: %w", err)
+		}
+	} else {
+		slog.Warn("Post view rollup cron job not scheduled")
+	}
+
+	// Newsletter job
+	if env.NewsletterCronSchedule != "" {
+		_, err = scheduler.NewJob(
+			gocron.CronJob(
+				env.NewsletterCronSchedule,
+				false,
+			),
+			gocron.NewTask(NewNewsletterTask(dbInstance)),
+			gocron.WithSingletonMode(gocron.LimitModeReschedule),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create newsletter cron job: %w", err)
+		}
+	} else {
+		slog.Warn("Newsletter cron job not scheduled")
+	}
+
+	// Payment reconciliation job
+	if env.PaymentReconciliationCronSchedule != "" {
+		_, err = scheduler.NewJob(
+			gocron.CronJob(
+				env.PaymentReconciliationCronSchedule,
+				false,
+			),
+			gocron.NewTask(NewPaymentReconciliationTask(endpointService)),
+			gocron.WithSingletonMode(gocron.LimitModeReschedule),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create payment reconciliation cron job: %w", err)
+		}
+	} else {
+		slog.Warn("Payment reconciliation cron job not scheduled")
+	}
+
+	// Discount reservation release job
+	if env.DiscountReservationCronSchedule != "" {
+		_, err = scheduler.NewJob(
+			gocron.CronJob(
+				env.DiscountReservationCronSchedule,
+				false,
+			),
+			gocron.NewTask(NewDiscountReservationTask(endpointService)),
+			gocron.WithSingletonMode(gocron.LimitModeReschedule),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create discount reservation release cron job: %w", err)
+		}
+	} else {
+		slog.Warn("Discount reservation release cron job not scheduled")
+	}
+
 	// Email sending job
 	if env.SMTPHost != "" {
 		_, err = scheduler.NewJob(
@@ -105,5 +192,29 @@ func NewScheduler(dbInstance *sqlx.DB, emailClient *email.EmailClient) (gocron.S
 		slog.Warn("Email sending cron job not scheduled")
 	}
 
+	// Payment event processing job
+	_, err = scheduler.NewJob(
+		gocron.OneTimeJob(
+			gocron.OneTimeJobStartImmediately(),
+		),
+		gocron.NewTask(NewPaymentEventTask(dbInstance, endpointService)),
+		gocron.WithSingletonMode(gocron.LimitModeReschedule),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create payment event processing job: %w", err)
+	}
+
+	// Payment outbox processing job
+	_, err = scheduler.NewJob(
+		gocron.OneTimeJob(
+			gocron.OneTimeJobStartImmediately(),
+		),
+		gocron.NewTask(NewPaymentOutboxTask(dbInstance, endpointService)),
+		gocron.WithSingletonMode(gocron.LimitModeReschedule),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create payment outbox processing job: %w", err)
+	}
+
 	return scheduler, nil
 }
@@ -31,7 +31,9 @@ func NewEmailClient() (*EmailClient, error) {
 	}, nil
 }
 
-func (ec *EmailClient) SendEmail(ctx context.Context, toList, ccList, bccList []string, subject, body string) error {
+// SendEmail sends an HTML email. If the list unsubscribe URL is not empty, the
+// email can be unsubscribed from with one click as in RFC 8058.
+func (ec *EmailClient) SendEmail(ctx context.Context, toList, ccList, bccList []string, subject, body, listUnsubscribeURL string) error {
 	message := mail.NewMsg()
 	if err := message.From(env.EmailFromAddress); err != nil {
 		return fmt.Errorf("failed to set from address: %v", err)
@@ -52,6 +54,10 @@ func (ec *EmailClient) SendEmail(ctx context.Context, toList, ccList, bccList []
 		}
 	}
 	message.Subject(subject)
+	if listUnsubscribeURL != "" {
+		message.SetGenHeader(mail.HeaderListUnsubscribe, "<"+listUnsubscribeURL+">")
+		message.SetGenHeader(mail.HeaderListUnsubscribePost, "List-Unsubscribe=One-Click")
+	}
 	message.SetBodyString(mail.TypeTextHTML, body)
 
 	err := ec.client.DialAndSendWithContext(ctx, message)
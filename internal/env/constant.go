@@ -1,20 +1,30 @@
 package env
 
-import "net/http"
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
 
 const (
 	OwnerRole = "owner"
 	UserRole  = "user"
 
-	QueueTaskLaneEmail = "email"
+	QueueTaskLaneEmail         = "email"
+	QueueTaskLanePaymentEvent  = "payment_event"
+	QueueTaskLanePaymentOutbox = "payment_outbox"
 
 	QueueTaskStatusPending   = "pending"
 	QueueTaskStatusRunning   = "running"
 	QueueTaskStatusSucceeded = "succeeded"
 	QueueTaskStatusFailed    = "failed"
 
-	EmailTypeVerifyEmail = "verify_email"
-	EmailTypeNewEmail    = "new_email"
+	EmailTypeVerifyEmail       = "verify_email"
+	EmailTypeNewEmail          = "new_email"
+	EmailTypeNewComment        = "new_comment"
+	EmailTypeNewsletterConfirm = "newsletter_confirm"
+	EmailTypeNewsletterPost    = "newsletter_post"
 
 	EmailStatusPending = "pending"
 	EmailStatusSent    = "sent"
@@ -25,48 +35,168 @@ const (
 
 	EmailVerificationStatusPending  = "pending"
 	EmailVerificationStatusVerified = "verified"
+
+	NewsletterSubscriptionStatusPending   = "pending"
+	NewsletterSubscriptionStatusConfirmed = "confirmed"
+
+	PaymentEventStatusPending   = "pending"
+	PaymentEventStatusProcessed = "processed"
+	PaymentEventStatusIgnored   = "ignored"
+	PaymentEventStatusFailed    = "failed"
+
+	CommentStatusPending  = "pending"
+	CommentStatusApproved = "approved"
+	CommentStatusRejected = "rejected"
+	CommentStatusSpam     = "spam"
+
+	PostVisibilityPublic   = "public"
+	PostVisibilitySignedIn = "signed_in"
+	PostVisibilityPaid     = "paid"
+
+	EntitlementSourceManual       = "manual"
+	EntitlementSourceSubscription = "subscription"
+	EntitlementSourceOrder        = "order"
+
+	SubscriptionStatusActive   = "active"
+	SubscriptionStatusTrialing = "trialing"
+
+	OrderStatusPending           = "pending"
+	OrderStatusPaid              = "paid"
+	OrderStatusRefunded          = "refunded"
+	OrderStatusPartiallyRefunded = "partially_refunded"
+
+	PaymentOutboxTypeCreateCustomer = "create_customer"
+	PaymentOutboxTypeUpdateCustomer = "update_customer"
+	PaymentOutboxTypeDeleteCustomer = "delete_customer"
+	PaymentOutboxTypeUpdatePrice    = "update_price"
+
+	PaymentOutboxStatusPending   = "pending"
+	PaymentOutboxStatusSucceeded = "succeeded"
+	PaymentOutboxStatusFailed    = "failed"
+
+	PaymentSyncStatusSynced  = "synced"
+	PaymentSyncStatusPending = "pending"
+	PaymentSyncStatusFailed  = "failed"
+
+	PaymentReconciliationObjectTypeCustomer     = "customer"
+	PaymentReconciliationObjectTypeProduct      = "product"
+	PaymentReconciliationObjectTypePrice        = "price"
+	PaymentReconciliationObjectTypeDiscount     = "discount"
+	PaymentReconciliationObjectTypeSubscription = "subscription"
+	PaymentReconciliationObjectTypeOrder        = "order"
+
+	PaymentReconciliationCodeMissingAtProvider = "missing_at_provider"
+	PaymentReconciliationCodeMissingLocally    = "missing_locally"
+	PaymentReconciliationCodeNotLinked         = "not_linked"
+	PaymentReconciliationCodeMismatch          = "mismatch"
+
+	DiscountTypePercentage = "percentage"
+	DiscountTypeFixed      = "fixed"
+
+	DiscountDurationOnce      = "once"
+	DiscountDurationForever   = "forever"
+	DiscountDurationRepeating = "repeating"
+
+	DiscountRedemptionSourceSubscription = "subscription"
+	DiscountRedemptionSourceOrder        = "order"
+
+	PostCollaboratorRoleAuthor = "author"
+	PostCollaboratorRoleEditor = "editor"
+	PostCollaboratorRoleViewer = "viewer"
+
+	PostImportStatusReady    = "ready"
+	PostImportStatusCreated  = "created"
+	PostImportStatusConflict = "conflict"
+	PostImportStatusInvalid  = "invalid"
 )
 
 var (
 	Version = "dev"
 
-	DBType                           string
-	PostgresURL                      string
-	SQLiteDbPath                     string
-	SQLiteDbBusyTimeout              string
-	SQLiteBackupDbPath               string
-	SQLiteBackupCronSchedule         string
-	SessionCleanupCronSchedule       string
-	SMTPHost                         string
-	SMTPPort                         int
-	SMTPUsername                     string
-	SMTPPassword                     string
-	EmailFromAddress                 string
-	PaymentProvider                  string
-	PolarAccessToken                 string
-	PolarIsSandbox                   bool
-	LogLevel                         int
-	LogHealthCheck                   bool
-	Port                             string
-	CORSOrigins                      string
-	PasswordBcryptCost               int
-	EmailVerificationCodeLength      int
-	EmailVerificationCodeCharset     string
-	EmailVerificationCodeLifetimeMin int
-	SessionCookieName                string
-	SessionCookieHttpOnly            bool
-	SessionCookieSecure              bool
-	SessionTokenLength               int
-	SessionTokenCharset              string
-	SessionLifetimeMin               int
-	SessionRefreshThresholdMin       int
-	PreSessionLifetimeMin            int
-	CSRFTokenLength                  int
-	CSRFTokenCharset                 string
-	PageSizeMax                      int
-	PageSizeDefault                  int
-
-	SessionCookieSameSiteMode http.SameSite
+	DBType                            string
+	PostgresURL                       string
+	SQLiteDbPath                      string
+	SQLiteDbBusyTimeout               string
+	SQLiteBackupDbPath                string
+	SQLiteBackupCronSchedule          string
+	SessionCleanupCronSchedule        string
+	PostTrashPurgeCronSchedule        string
+	PostViewRollupCronSchedule        string
+	NewsletterCronSchedule            string
+	PaymentReconciliationCronSchedule string
+	DiscountReservationCronSchedule   string
+	SMTPHost                          string
+	SMTPPort                          int
+	SMTPUsername                      string
+	SMTPPassword                      string
+	EmailFromAddress                  string
+	PaymentProvider                   string
+	PolarAccessToken                  string
+	PolarIsSandbox                    bool
+	PolarWebhookSecret                string
+	StripeSecretKey                   string
+	StripeWebhookSecret               string
+	StripeAPIBaseURL                  string
+	PaymentWebhookToleranceSec        int
+	PaymentProviderTimeoutSec         int
+	PaymentProviderRetryMax           int
+	PaymentProviderRetryDelayMs       int
+	PaymentEventAttemptMax            int
+	PaymentOutboxAttemptMax           int
+	StorageProvider                   string
+	StorageLocalPath                  string
+	S3Endpoint                        string
+	S3Region                          string
+	S3Bucket                          string
+	S3AccessKeyID                     string
+	S3SecretAccessKey                 string
+	S3UsePathStyle                    bool
+	LogLevel                          int
+	LogHealthCheck                    bool
+	Port                              string
+	SiteURL                           string
+	SiteTitle                         string
+	SiteDescription                   string
+	CheckoutSuccessURL                string
+	CheckoutCancelURL                 string
+	CheckoutLifetimeMin               int
+	CORSOrigins                       string
+	PasswordBcryptCost                int
+	EmailVerificationCodeLength       int
+	EmailVerificationCodeCharset      string
+	EmailVerificationCodeLifetimeMin  int
+	NewsletterTokenLength             int
+	NewsletterTokenCharset            string
+	NewsletterLookbackHours           int
+	NewsletterConfirmResendMin        int
+	SessionCookieName                 string
+	SessionCookieHttpOnly             bool
+	SessionCookieSecure               bool
+	SessionTokenLength                int
+	SessionTokenCharset               string
+	SessionLifetimeMin                int
+	SessionRefreshThresholdMin        int
+	PreSessionLifetimeMin             int
+	CSRFTokenLength                   int
+	CSRFTokenCharset                  string
+	PageSizeMax                       int
+	PageSizeDefault                   int
+	FeedItemCount                     int
+	PostTagCountMax                   int
+	PostTagLengthMax                  int
+	PostTeaserLength                  int
+	PostTrashRetentionDays            int
+	PostStatsDaysDefault              int
+	PostStatsDaysMax                  int
+	PostImportSizeMaxBytes            int64
+	CommentEditWindowMin              int
+	CommentContentLengthMax           int
+	AttachmentSizeMaxBytes            int64
+	AttachmentThumbnailSizeMax        int
+
+	SessionCookieSameSiteMode    http.SameSite
+	AttachmentContentTypeAllowed map[string]bool
+	PostReactionTypes            []string
 )
 
 func MustSetConstants() {
@@ -79,6 +209,11 @@ func MustSetConstants() {
 	SQLiteBackupDbPath = MustGetString("SQLITE_BACKUP_DB_PATH", "data/backup/db/backup.db")
 	SQLiteBackupCronSchedule = MustGetString("SQLITE_BACKUP_CRON_SCHEDULE", "0 0 * * *")
 	SessionCleanupCronSchedule = MustGetString("SESSION_CLEANUP_CRON_SCHEDULE", "0 0 * * 0")
+	PostTrashPurgeCronSchedule = MustGetString("POST_TRASH_PURGE_CRON_SCHEDULE", "0 1 * * *")
+	PostViewRollupCronSchedule = MustGetString("POST_VIEW_ROLLUP_CRON_SCHEDULE", "5 0 * * *")
+	NewsletterCronSchedule = MustGetString("NEWSLETTER_CRON_SCHEDULE", "*/5 * * * *")
+	PaymentReconciliationCronSchedule = MustGetString("PAYMENT_RECONCILIATION_CRON_SCHEDULE", "0 2 * * *")
+	DiscountReservationCronSchedule = MustGetString("DISCOUNT_RESERVATION_CRON_SCHEDULE", "*/5 * * * *")
 	SMTPHost = MustGetString("SMTP_HOST", "")
 	SMTPPort = MustGetInt("SMTP_PORT", 587)
 	SMTPUsername = MustGetString("SMTP_USERNAME", "")
@@ -87,14 +222,42 @@ func MustSetConstants() {
 	paymentProvider := MustGetString("PAYMENT_PROVIDER", "polar")
 	PolarAccessToken = MustGetString("POLAR_ACCESS_TOKEN", "")
 	PolarIsSandbox = MustGetBool("POLAR_IS_SANDBOX", false)
+	PolarWebhookSecret = MustGetString("POLAR_WEBHOOK_SECRET", "")
+	StripeSecretKey = MustGetString("STRIPE_SECRET_KEY", "")
+	StripeWebhookSecret = MustGetString("STRIPE_WEBHOOK_SECRET", "")
+	StripeAPIBaseURL = MustGetString("STRIPE_API_BASE_URL", "https://api.stripe.com/v1")
+	PaymentWebhookToleranceSec = MustGetInt("PAYMENT_WEBHOOK_TOLERANCE_SEC", 300)
+	PaymentProviderTimeoutSec = MustGetInt("PAYMENT_PROVIDER_TIMEOUT_SEC", 30)
+	PaymentProviderRetryMax = MustGetInt("PAYMENT_PROVIDER_RETRY_MAX", 3)
+	PaymentProviderRetryDelayMs = MustGetInt("PAYMENT_PROVIDER_RETRY_DELAY_MS", 500)
+	PaymentEventAttemptMax = MustGetInt("PAYMENT_EVENT_ATTEMPT_MAX", 5)
+	PaymentOutboxAttemptMax = MustGetInt("PAYMENT_OUTBOX_ATTEMPT_MAX", 5)
+	storageProvider := MustGetString("STORAGE_PROVIDER", "local")
+	StorageLocalPath = MustGetString("STORAGE_LOCAL_PATH", "data/live/storage")
+	S3Endpoint = MustGetString("S3_ENDPOINT", "")
+	S3Region = MustGetString("S3_REGION", "us-east-1")
+	S3Bucket = MustGetString("S3_BUCKET", "")
+	S3AccessKeyID = MustGetString("S3_ACCESS_KEY_ID", "")
+	S3SecretAccessKey = MustGetString("S3_SECRET_ACCESS_KEY", "")
+	S3UsePathStyle = MustGetBool("S3_USE_PATH_STYLE", true)
 	LogLevel = MustGetInt("LOG_LEVEL", 0)
 	LogHealthCheck = MustGetBool("LOG_HEALTH_CHECK", false)
 	Port = MustGetString("PORT", "3000")
+	SiteURL = strings.TrimSuffix(MustGetString("SITE_URL", "http://localhost:3000"), "/")
+	SiteTitle = MustGetString("SITE_TITLE", "issho")
+	SiteDescription = MustGetString("SITE_DESCRIPTION", "")
+	CheckoutSuccessURL = MustGetString("CHECKOUT_SUCCESS_URL", SiteURL+"/account")
+	CheckoutCancelURL = MustGetString("CHECKOUT_CANCEL_URL", SiteURL+"/")
+	CheckoutLifetimeMin = MustGetInt("CHECKOUT_LIFETIME_MIN", 60)
 	CORSOrigins = MustGetString("CORS_ORIGINS", "*")
 	PasswordBcryptCost = MustGetInt("PASSWORD_BCRYPT_COST", 12)
 	EmailVerificationCodeLength = MustGetInt("EMAIL_VERIFICATION_CODE_LENGTH", 5)
 	EmailVerificationCodeCharset = MustGetString("EMAIL_VERIFICATION_CODE_CHARSET", "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ")
 	EmailVerificationCodeLifetimeMin = MustGetInt("EMAIL_VERIFICATION_CODE_LIFETIME_MIN", 10)
+	NewsletterTokenLength = MustGetInt("NEWSLETTER_TOKEN_LENGTH", 32)
+	NewsletterTokenCharset = MustGetString("NEWSLETTER_TOKEN_CHARSET", "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789")
+	NewsletterLookbackHours = MustGetInt("NEWSLETTER_LOOKBACK_HOURS", 24)
+	NewsletterConfirmResendMin = MustGetInt("NEWSLETTER_CONFIRM_RESEND_MIN", 10)
 	SessionCookieName = MustGetString("SESSION_COOKIE_NAME", "issho_session_token")
 	SessionCookieHttpOnly = MustGetBool("SESSION_COOKIE_HTTP_ONLY", true)
 	SessionCookieSecure = MustGetBool("SESSION_COOKIE_SECURE", false)
@@ -108,6 +271,20 @@ func MustSetConstants() {
 	CSRFTokenCharset = MustGetString("CSRF_TOKEN_CHARSET", "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789")
 	PageSizeMax = MustGetInt("PAGE_SIZE_MAX", 100)
 	PageSizeDefault = MustGetInt("PAGE_SIZE_DEFAULT", 10)
+	FeedItemCount = MustGetInt("FEED_ITEM_COUNT", 20)
+	PostTagCountMax = MustGetInt("POST_TAG_COUNT_MAX", 10)
+	PostTagLengthMax = MustGetInt("POST_TAG_LENGTH_MAX", 50)
+	PostTeaserLength = MustGetInt("POST_TEASER_LENGTH", 300)
+	PostTrashRetentionDays = MustGetInt("POST_TRASH_RETENTION_DAYS", 30)
+	PostStatsDaysDefault = MustGetInt("POST_STATS_DAYS_DEFAULT", 30)
+	PostStatsDaysMax = MustGetInt("POST_STATS_DAYS_MAX", 365)
+	PostImportSizeMaxBytes = MustGetInt64("POST_IMPORT_SIZE_MAX_BYTES", 50*1024*1024)
+	CommentEditWindowMin = MustGetInt("COMMENT_EDIT_WINDOW_MIN", 15)
+	CommentContentLengthMax = MustGetInt("COMMENT_CONTENT_LENGTH_MAX", 5000)
+	AttachmentSizeMaxBytes = MustGetInt64("ATTACHMENT_SIZE_MAX_BYTES", 10*1024*1024)
+	AttachmentThumbnailSizeMax = MustGetInt("ATTACHMENT_THUMBNAIL_SIZE_MAX", 320)
+	attachmentContentTypes := MustGetString("ATTACHMENT_CONTENT_TYPES", "image/jpeg,image/png,image/gif,image/webp,application/pdf,text/plain")
+	postReactionTypes := MustGetString("POST_REACTION_TYPES", "like,love,laugh,surprised,sad")
 
 	switch dBType {
 	case "postgres":
@@ -121,8 +298,37 @@ func MustSetConstants() {
 	switch paymentProvider {
 	case "polar":
 		PaymentProvider = "polar"
+	case "stripe":
+		PaymentProvider = "stripe"
+	case "local":
+		PaymentProvider = "local"
 	default:
-		PaymentProvider = "polar"
+		panic(fmt.Errorf("unsupported payment provider: %s", paymentProvider))
+	}
+
+	switch storageProvider {
+	case "local":
+		StorageProvider = "local"
+	case "s3":
+		StorageProvider = "s3"
+	default:
+		StorageProvider = "local"
+	}
+
+	AttachmentContentTypeAllowed = make(map[string]bool)
+	for contentType := range strings.SplitSeq(attachmentContentTypes, ",") {
+		contentType = strings.ToLower(strings.TrimSpace(contentType))
+		if contentType != "" {
+			AttachmentContentTypeAllowed[contentType] = true
+		}
+	}
+
+	PostReactionTypes = []string{}
+	for reactionType := range strings.SplitSeq(postReactionTypes, ",") {
+		reactionType = strings.TrimSpace(reactionType)
+		if reactionType != "" && !slices.Contains(PostReactionTypes, reactionType) {
+			PostReactionTypes = append(PostReactionTypes, reactionType)
+		}
 	}
 
 	switch sessionCookieSameSite {
@@ -0,0 +1,88 @@
+package feed
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Author  atomAuthor  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Link       atomLink       `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Author     *atomAuthor    `xml:"author"`
+	Categories []atomCategory `xml:"category"`
+	Summary    atomText       `xml:"summary"`
+	Content    atomText       `xml:"content"`
+}
+
+// Atom renders the feed as an Atom document. The feed title is used as the
+// feed-level author so that entries without an author remain valid.
+func Atom(feed Feed) ([]byte, error) {
+	doc := atomFeed{
+		ID:      feed.FeedURL,
+		Title:   feed.Title,
+		Updated: feed.UpdatedAt.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: feed.FeedURL, Rel: "self", Type: "application/atom+xml"},
+			{Href: feed.Link, Rel: "alternate", Type: "text/html"},
+		},
+		Author:  atomAuthor{Name: feed.Title},
+		Entries: make([]atomEntry, len(feed.Items)),
+	}
+
+	for i, item := range feed.Items {
+		entry := atomEntry{
+			ID:         item.Link,
+			Title:      item.Title,
+			Link:       atomLink{Href: item.Link, Rel: "alternate", Type: "text/html"},
+			Published:  item.PublishedAt.UTC().Format(time.RFC3339),
+			Updated:    item.UpdatedAt.UTC().Format(time.RFC3339),
+			Categories: make([]atomCategory, len(item.Tags)),
+			Summary:    atomText{Type: "text", Value: item.Description},
+			Content:    atomText{Type: "text", Value: item.Content},
+		}
+
+		if item.AuthorName != "" {
+			entry.Author = &atomAuthor{Name: item.AuthorName}
+		}
+
+		for j, tag := range item.Tags {
+			entry.Categories[j] = atomCategory{Term: tag}
+		}
+
+		doc.Entries[i] = entry
+	}
+
+	return marshalXML(doc)
+}
@@ -0,0 +1,26 @@
+package feed
+
+import "time"
+
+// Feed is a format-independent feed that can be rendered as RSS 2.0, Atom or
+// JSON Feed.
+type Feed struct {
+	Title       string
+	Description string
+	Link        string
+	FeedURL     string
+	UpdatedAt   time.Time
+	Items       []Item
+}
+
+type Item struct {
+	ID          string
+	Title       string
+	Link        string
+	Description string
+	Content     string
+	AuthorName  string
+	Tags        []string
+	PublishedAt time.Time
+	UpdatedAt   time.Time
+}
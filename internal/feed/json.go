@@ -0,0 +1,64 @@
+package feed
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Description string         `json:"description,omitempty"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonFeedItem struct {
+	ID            string           `json:"id"`
+	URL           string           `json:"url"`
+	Title         string           `json:"title"`
+	Summary       string           `json:"summary,omitempty"`
+	ContentText   string           `json:"content_text"`
+	DatePublished string           `json:"date_published"`
+	DateModified  string           `json:"date_modified"`
+	Authors       []jsonFeedAuthor `json:"authors,omitempty"`
+	Tags          []string         `json:"tags,omitempty"`
+}
+
+// JSON renders the feed as a JSON Feed 1.1 document.
+func JSON(feed Feed) ([]byte, error) {
+	doc := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       feed.Title,
+		HomePageURL: feed.Link,
+		FeedURL:     feed.FeedURL,
+		Description: feed.Description,
+		Items:       make([]jsonFeedItem, len(feed.Items)),
+	}
+
+	for i, item := range feed.Items {
+		jsonItem := jsonFeedItem{
+			ID:            item.ID,
+			URL:           item.Link,
+			Title:         item.Title,
+			Summary:       item.Description,
+			ContentText:   item.Content,
+			DatePublished: item.PublishedAt.UTC().Format(time.RFC3339),
+			DateModified:  item.UpdatedAt.UTC().Format(time.RFC3339),
+			Tags:          item.Tags,
+		}
+
+		if item.AuthorName != "" {
+			jsonItem.Authors = []jsonFeedAuthor{{Name: item.AuthorName}}
+		}
+
+		doc.Items[i] = jsonItem
+	}
+
+	return json.MarshalIndent(doc, "", "  ")
+}
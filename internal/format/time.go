@@ -8,4 +8,8 @@ func TimeToISO8601(timestamp time.Time) string {
 
 func ISO8601ToTime(timestamp string) (time.Time, error) {
 	return time.Parse("2006-01-02T15:04:05.000Z", timestamp)
+}
+
+func TimeToDate(timestamp time.Time) string {
+	return timestamp.UTC().Format("2006-01-02")
 }
\ No newline at end of file
@@ -0,0 +1,372 @@
+// Package frontmatter reads and writes Markdown documents with front matter.
+//
+// Only the subset of YAML and TOML used by static site generators such as
+// Hugo and Jekyll is supported: top level keys with scalar or list values.
+// Nested values are skipped.
+package frontmatter
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Document is a Markdown document. The values of the fields are either
+// strings or string slices.
+type Document struct {
+	Fields  map[string]any
+	Content string
+}
+
+// Field is a field of the front matter to format, where the value is either a
+// string or a string slice.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// String returns the field as a string, joining lists with commas. It returns
+// false if the field is not present.
+func (d *Document) String(key string) (string, bool) {
+	switch value := d.Fields[key].(type) {
+	case string:
+		return value, true
+	case []string:
+		return strings.Join(value, ", "), true
+	default:
+		return "", false
+	}
+}
+
+// List returns the field as a list, where a string is a single item list. It
+// returns false if the field is not present.
+func (d *Document) List(key string) ([]string, bool) {
+	switch value := d.Fields[key].(type) {
+	case string:
+		return []string{value}, true
+	case []string:
+		return value, true
+	default:
+		return nil, false
+	}
+}
+
+// Parse parses a document with YAML front matter between "---" lines, or TOML
+// front matter between "+++" lines. A document without front matter has no
+// fields.
+func Parse(data []byte) (*Document, error) {
+	text := strings.TrimPrefix(string(data), "\uFEFF")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	document := &Document{
+		Fields: make(map[string]any),
+	}
+
+	var delimiter string
+	switch {
+	case strings.HasPrefix(text, "---\n"):
+		delimiter = "---"
+	case strings.HasPrefix(text, "+++\n"):
+		delimiter = "+++"
+	default:
+		document.Content = text
+		return document, nil
+	}
+
+	rest := text[len(delimiter)+1:]
+
+	var lines []string
+	closed := false
+	for {
+		line, after, found := strings.Cut(rest, "\n")
+		if line == delimiter || (delimiter == "---" && line == "...") {
+			rest = after
+			closed = true
+			break
+		}
+
+		lines = append(lines, line)
+
+		if !found {
+			break
+		}
+		rest = after
+	}
+
+	if !closed {
+		return nil, fmt.Errorf("front matter is not closed")
+	}
+
+	var err error
+	if delimiter == "---" {
+		err = parseYAML(lines, document.Fields)
+	} else {
+		err = parseTOML(lines, document.Fields)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	document.Content = strings.TrimPrefix(rest, "\n")
+
+	return document, nil
+}
+
+func parseYAML(lines []string, fields map[string]any) error {
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		if isBlankOrComment(line) || isIndented(line) {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			return fmt.Errorf("invalid front matter line %d", i+1)
+		}
+
+		key = unquote(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		// The value is on the following indented lines
+		var block []string
+		for i+1 < len(lines) && (isIndented(lines[i+1]) || strings.HasPrefix(lines[i+1], "-") || strings.TrimSpace(lines[i+1]) == "") {
+			i++
+			block = append(block, lines[i])
+		}
+
+		switch {
+		case value == "" || strings.HasPrefix(value, "#"):
+			items := []string{}
+			for _, blockLine := range block {
+				item, isItem := strings.CutPrefix(strings.TrimSpace(blockLine), "-")
+				if !isItem {
+					continue
+				}
+				items = append(items, parseScalar(strings.TrimSpace(item)))
+			}
+			if len(items) > 0 {
+				fields[key] = items
+			}
+		case strings.HasPrefix(value, "|") || strings.HasPrefix(value, ">"):
+			fields[key] = parseBlockScalar(value, block)
+		case strings.HasPrefix(value, "["):
+			fields[key] = parseFlowList(value)
+		case strings.HasPrefix(value, "{"):
+			// Nested values are not supported
+		default:
+			fields[key] = parseScalar(value)
+		}
+	}
+
+	return nil
+}
+
+func parseTOML(lines []string, fields map[string]any) error {
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+
+		if isBlankOrComment(line) {
+			continue
+		}
+
+		// The keys after a table header are nested
+		if strings.HasPrefix(line, "[") {
+			break
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			return fmt.Errorf("invalid front matter line %d", i+1)
+		}
+
+		key = unquote(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		// Arrays can span multiple lines
+		if strings.HasPrefix(value, "[") {
+			for !strings.HasSuffix(stripComment(value), "]") && i+1 < len(lines) {
+				i++
+				value += " " + strings.TrimSpace(lines[i])
+			}
+			fields[key] = parseFlowList(value)
+			continue
+		}
+
+		fields[key] = parseScalar(value)
+	}
+
+	return nil
+}
+
+// parseBlockScalar parses a literal (|) or folded (>) block scalar from its
+// indented lines.
+func parseBlockScalar(indicator string, block []string) string {
+	indent := -1
+	var lines []string
+	for _, line := range block {
+		if strings.TrimSpace(line) == "" {
+			lines = append(lines, "")
+			continue
+		}
+
+		if indent < 0 {
+			indent = len(line) - len(strings.TrimLeft(line, " \t"))
+		}
+
+		if len(line) >= indent {
+			line = line[indent:]
+		}
+		lines = append(lines, line)
+	}
+
+	separator := "\n"
+	if strings.HasPrefix(indicator, ">") {
+		separator = " "
+	}
+
+	text := strings.Join(lines, separator)
+	if strings.HasSuffix(indicator, "-") {
+		return strings.TrimRight(text, "\n ")
+	}
+
+	return strings.TrimRight(text, "\n ") + "\n"
+}
+
+// parseFlowList parses a list in the form [a, "b", 'c'].
+func parseFlowList(value string) []string {
+	value = strings.TrimSpace(stripComment(value))
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+
+	items := []string{}
+	for _, item := range splitOutsideQuotes(value, ',') {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		items = append(items, parseScalar(item))
+	}
+
+	return items
+}
+
+// parseScalar parses a quoted or plain scalar, removing a trailing comment
+// from a plain scalar.
+func parseScalar(value string) string {
+	if strings.HasPrefix(value, `"`) || strings.HasPrefix(value, "'") {
+		parts := splitOutsideQuotes(value, '#')
+		return unquote(strings.TrimSpace(parts[0]))
+	}
+
+	return strings.TrimSpace(stripComment(value))
+}
+
+// unquote removes the quotes of a double quoted string, processing its escape
+// sequences, or of a single quoted string, where quotes are escaped by
+// doubling them.
+func unquote(value string) string {
+	if len(value) < 2 {
+		return value
+	}
+
+	switch {
+	case value[0] == '"' && value[len(value)-1] == '"':
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return value[1 : len(value)-1]
+		}
+		return unquoted
+	case value[0] == '\'' && value[len(value)-1] == '\'':
+		return strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+	default:
+		return value
+	}
+}
+
+// stripComment removes a comment, which starts with a # after a space, from a
+// plain value.
+func stripComment(value string) string {
+	if strings.HasPrefix(value, "#") {
+		return ""
+	}
+
+	if before, _, found := strings.Cut(value, " #"); found {
+		return before
+	}
+
+	return value
+}
+
+// splitOutsideQuotes splits the value by the separator, ignoring separators
+// inside quotes.
+func splitOutsideQuotes(value string, separator rune) []string {
+	var parts []string
+	var current strings.Builder
+	var quote rune
+	escaped := false
+
+	for _, r := range value {
+		switch {
+		case escaped:
+			escaped = false
+		case quote == '"' && r == '\\':
+			escaped = true
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote == 0 && (r == '"' || r == '\''):
+			quote = r
+		case quote == 0 && r == separator:
+			parts = append(parts, current.String())
+			current.Reset()
+			continue
+		}
+		current.WriteRune(r)
+	}
+
+	return append(parts, current.String())
+}
+
+func isBlankOrComment(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed == "" || strings.HasPrefix(trimmed, "#")
+}
+
+func isIndented(line string) bool {
+	return strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+}
+
+// Format formats the fields as YAML front matter followed by the content. The
+// fields are written in order, and fields with other types of values are
+// skipped.
+func Format(fields []Field, content string) []byte {
+	var buffer bytes.Buffer
+	writer := bufio.NewWriter(&buffer)
+
+	writer.WriteString("---\n")
+
+	for _, field := range fields {
+		switch value := field.Value.(type) {
+		case string:
+			fmt.Fprintf(writer, "%s: %s\n", field.Key, strconv.Quote(value))
+		case []string:
+			if len(value) == 0 {
+				fmt.Fprintf(writer, "%s: []\n", field.Key)
+				continue
+			}
+
+			fmt.Fprintf(writer, "%s:\n", field.Key)
+			for _, item := range value {
+				fmt.Fprintf(writer, "  - %s\n", strconv.Quote(item))
+			}
+		}
+	}
+
+	writer.WriteString("---\n\n")
+	writer.WriteString(content)
+
+	writer.Flush()
+
+	return buffer.Bytes()
+}
@@ -17,3 +17,9 @@ func MinutesFromNowISO8601(minutes int) string {
 func DurationFromNowISO8601(duration time.Duration) string {
 	return format.TimeToISO8601(time.Now().Add(duration))
 }
+
+// DaysFromTodayDate returns the UTC date that is the number of days from
+// today, in the format YYYY-MM-DD.
+func DaysFromTodayDate(days int) string {
+	return format.TimeToDate(time.Now().UTC().AddDate(0, 0, days))
+}
@@ -5,3 +5,10 @@ import "github.com/oklog/ulid/v2"
 func NewULID() string {
 	return ulid.Make().String()
 }
+
+// IsULID reports whether the ID is a valid ULID, e.g. when an ID is kept from
+// imported data.
+func IsULID(id string) bool {
+	_, err := ulid.ParseStrict(id)
+	return err == nil
+}
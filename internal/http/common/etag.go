@@ -0,0 +1,60 @@
+package common
+
+import (
+	"net/http"
+	"strings"
+)
+
+// ETag returns the entity tag of a resource version. The version of a
+// resource is the time it was last updated.
+func ETag(version string) string {
+	return `"` + version + `"`
+}
+
+func SetETag(w http.ResponseWriter, version string) {
+	w.Header().Set("ETag", ETag(version))
+}
+
+// IsNoneMatch reports whether the If-None-Match header of the request matches
+// the entity tag, using the weak comparison.
+func IsNoneMatch(r *http.Request, etag string) bool {
+	for candidate := range strings.SplitSeq(r.Header.Get("If-None-Match"), ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// GetIfMatchVersions returns the resource versions in the If-Match header of
+// the request. Weak entity tags are ignored, as If-Match uses the strong
+// comparison. The versions are nil if the header is "*", which matches any
+// version, and ok is false if the header is missing.
+func GetIfMatchVersions(r *http.Request) (versions []string, ok bool) {
+	ifMatch := strings.TrimSpace(r.Header.Get("If-Match"))
+	if ifMatch == "" {
+		return nil, false
+	}
+
+	if ifMatch == "*" {
+		return nil, true
+	}
+
+	versions = []string{}
+	for candidate := range strings.SplitSeq(ifMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if len(candidate) < 2 || !strings.HasPrefix(candidate, `"`) || !strings.HasSuffix(candidate, `"`) {
+			continue
+		}
+		versions = append(versions, candidate[1:len(candidate)-1])
+	}
+
+	return versions, true
+}
+
+// WriteIfMatchRequiredResponse writes the response for a request that updates
+// a resource without the If-Match header.
+func WriteIfMatchRequiredResponse(w http.ResponseWriter) {
+	WriteMessageResponse(w, "If-Match header is required", http.StatusPreconditionRequired)
+}
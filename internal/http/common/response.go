@@ -11,37 +11,63 @@ import (
 )
 
 var genericErrorMap = map[service.ErrorCode]service.ErrorCode{
-	service.ErrCodeBadRequest:    service.ErrCodeBadRequest,
-	service.ErrCodeUnauthorized:  service.ErrCodeUnauthorized,
-	service.ErrCodeForbidden:     service.ErrCodeForbidden,
-	service.ErrCodeNotFound:      service.ErrCodeNotFound,
-	service.ErrCodeConflict:      service.ErrCodeConflict,
-	service.ErrCodeUnprocessable: service.ErrCodeUnprocessable,
-	service.ErrCodeInternal:      service.ErrCodeInternal,
-
-	service.ErrCodeVerificationFailed: service.ErrCodeUnprocessable,
-	service.ErrCodeUsernameTaken:      service.ErrCodeConflict,
-	service.ErrCodeEmailTaken:         service.ErrCodeConflict,
-	service.ErrCodeInvalidCredentials: service.ErrCodeUnauthorized,
-	service.ErrCodeUpdatePublishedAt:  service.ErrCodeUnprocessable,
+	service.ErrCodeBadRequest:         service.ErrCodeBadRequest,
+	service.ErrCodeUnauthorized:       service.ErrCodeUnauthorized,
+	service.ErrCodeForbidden:          service.ErrCodeForbidden,
+	service.ErrCodeNotFound:           service.ErrCodeNotFound,
+	service.ErrCodeConflict:           service.ErrCodeConflict,
+	service.ErrCodeUnprocessable:      service.ErrCodeUnprocessable,
+	service.ErrCodePreconditionFailed: service.ErrCodePreconditionFailed,
+	service.ErrCodeInternal:           service.ErrCodeInternal,
+	service.ErrCodeServiceUnavailable: service.ErrCodeServiceUnavailable,
+
+	service.ErrCodeVerificationFailed:              service.ErrCodeUnprocessable,
+	service.ErrCodeUsernameTaken:                   service.ErrCodeConflict,
+	service.ErrCodeEmailTaken:                      service.ErrCodeConflict,
+	service.ErrCodeInvalidCredentials:              service.ErrCodeUnauthorized,
+	service.ErrCodeUpdatePublishedAt:               service.ErrCodeUnprocessable,
+	service.ErrCodeCommentEditWindowExpired:        service.ErrCodeUnprocessable,
+	service.ErrCodeAttachmentTooLarge:              service.ErrCodeUnprocessable,
+	service.ErrCodeAttachmentContentTypeNotAllowed: service.ErrCodeUnprocessable,
+	service.ErrCodeSlugTaken:                       service.ErrCodeConflict,
+	service.ErrCodeInvalidWebhookSignature:         service.ErrCodeUnauthorized,
+	service.ErrCodePaymentProviderUnavailable:      service.ErrCodeServiceUnavailable,
+	service.ErrCodePaymentProviderRejected:         service.ErrCodeUnprocessable,
+	service.ErrCodeDiscountCodeTaken:               service.ErrCodeConflict,
+	service.ErrCodeDiscountExpired:                 service.ErrCodeUnprocessable,
+	service.ErrCodeDiscountExhausted:               service.ErrCodeUnprocessable,
+	service.ErrCodeDiscountIneligible:              service.ErrCodeUnprocessable,
 }
 
 var HTTPStatusMap = map[service.ErrorCode]int{
-	service.ErrCodeBadRequest:    http.StatusBadRequest,
-	service.ErrCodeUnauthorized:  http.StatusUnauthorized,
-	service.ErrCodeForbidden:     http.StatusForbidden,
-	service.ErrCodeNotFound:      http.StatusNotFound,
-	service.ErrCodeConflict:      http.StatusConflict,
-	service.ErrCodeUnprocessable: http.StatusUnprocessableEntity,
-	service.ErrCodeInternal:      http.StatusInternalServerError,
+	service.ErrCodeBadRequest:         http.StatusBadRequest,
+	service.ErrCodeUnauthorized:       http.StatusUnauthorized,
+	service.ErrCodeForbidden:          http.StatusForbidden,
+	service.ErrCodeNotFound:           http.StatusNotFound,
+	service.ErrCodeConflict:           http.StatusConflict,
+	service.ErrCodeUnprocessable:      http.StatusUnprocessableEntity,
+	service.ErrCodePreconditionFailed: http.StatusPreconditionFailed,
+	service.ErrCodeInternal:           http.StatusInternalServerError,
+	service.ErrCodeServiceUnavailable: http.StatusServiceUnavailable,
 }
 
 var jsonCodeMap = map[service.ErrorCode]string{
-	service.ErrCodeVerificationFailed: "verificationFailed",
-	service.ErrCodeUsernameTaken:      "usernameTaken",
-	service.ErrCodeEmailTaken:         "emailTaken",
-	service.ErrCodeInvalidCredentials: "invalidCredentials",
-	service.ErrCodeUpdatePublishedAt:  "updatePublishedAt",
+	service.ErrCodeVerificationFailed:              "verificationFailed",
+	service.ErrCodeUsernameTaken:                   "usernameTaken",
+	service.ErrCodeEmailTaken:                      "emailTaken",
+	service.ErrCodeInvalidCredentials:              "invalidCredentials",
+	service.ErrCodeUpdatePublishedAt:               "updatePublishedAt",
+	service.ErrCodeCommentEditWindowExpired:        "commentEditWindowExpired",
+	service.ErrCodeAttachmentTooLarge:              "attachmentTooLarge",
+	service.ErrCodeAttachmentContentTypeNotAllowed: "attachmentContentTypeNotAllowed",
+	service.ErrCodeSlugTaken:                       "slugTaken",
+	service.ErrCodeInvalidWebhookSignature:         "invalidWebhookSignature",
+	service.ErrCodePaymentProviderUnavailable:      "paymentProviderUnavailable",
+	service.ErrCodePaymentProviderRejected:         "paymentProviderRejected",
+	service.ErrCodeDiscountCodeTaken:               "discountCodeTaken",
+	service.ErrCodeDiscountExpired:                 "discountExpired",
+	service.ErrCodeDiscountExhausted:               "discountExhausted",
+	service.ErrCodeDiscountIneligible:              "discountIneligible",
 }
 
 func WriteErrorResponse(w http.ResponseWriter, err error) {
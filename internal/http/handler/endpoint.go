@@ -21,7 +21,23 @@ func (h *EndpointHandler) RegisterRoutes(mux *http.ServeMux) {
 	h.registerVersionRoutes(mux)
 	h.registerAuthRoutes(mux)
 	h.registerUserRoutes(mux)
+	h.registerNewsletterRoutes(mux)
 	h.registerPostRoutes(mux)
+	h.registerPostTranslationRoutes(mux)
+	h.registerPostCollaboratorRoutes(mux)
+	h.registerPostStatsRoutes(mux)
+	h.registerPostArchiveRoutes(mux)
+	h.registerBookmarkRoutes(mux)
+	h.registerPostReactionRoutes(mux)
+	h.registerSeriesRoutes(mux)
+	h.registerCommentRoutes(mux)
+	h.registerAttachmentRoutes(mux)
 	h.registerProductRoutes(mux)
 	h.registerPriceRoutes(mux)
+	h.registerDiscountRoutes(mux)
+	h.registerEntitlementRoutes(mux)
+	h.registerPurchaseRoutes(mux)
+	h.registerPaymentWebhookRoutes(mux)
+	h.registerLocalPaymentRoutes(mux)
+	h.registerPaymentReconciliationRoutes(mux)
 }
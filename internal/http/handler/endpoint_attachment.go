@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/http/common"
+	"github.com/jljl1337/issho/internal/http/middleware"
+	"github.com/jljl1337/issho/internal/service"
+)
+
+// multipartOverheadBytes is the allowance for the multipart boundaries and
+// headers on top of the attachment size.
+const multipartOverheadBytes = 64 * 1024
+
+func (h *EndpointHandler) registerAttachmentRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /posts/{id}/attachments", h.CreateAttachment)
+	mux.HandleFunc("GET /posts/{id}/attachments", h.GetAttachmentListByPostID)
+	mux.HandleFunc("GET /attachments/{id}", h.GetAttachmentContent)
+	mux.HandleFunc("DELETE /attachments/{id}", h.DeleteAttachmentByID)
+}
+
+func (h *EndpointHandler) CreateAttachment(w http.ResponseWriter, r *http.Request) {
+	postID := r.PathValue("id")
+	if postID == "" {
+		common.WriteMessageResponse(w, "Post ID is required", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, env.AttachmentSizeMaxBytes+multipartOverheadBytes)
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		common.WriteMessageResponse(w, "Request body must be multipart/form-data", http.StatusBadRequest)
+		return
+	}
+
+	// Read the first part named "file", one byte over the limit so that the
+	// service can tell the attachment is too large
+	var fileName string
+	var data []byte
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			writeMultipartError(w, err)
+			return
+		}
+
+		if part.FormName() != "file" {
+			part.Close()
+			continue
+		}
+
+		fileName = part.FileName()
+		data, err = io.ReadAll(io.LimitReader(part, env.AttachmentSizeMaxBytes+1))
+		part.Close()
+		if err != nil {
+			writeMultipartError(w, err)
+			return
+		}
+		break
+	}
+
+	if data == nil {
+		common.WriteMessageResponse(w, "File is required", http.StatusBadRequest)
+		return
+	}
+
+	attachment, err := h.service.CreateAttachment(r.Context(), service.CreateAttachmentParams{
+		User:     *user,
+		PostID:   postID,
+		FileName: fileName,
+		Data:     data,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteJSONResponse(w, http.StatusCreated, attachment)
+}
+
+func (h *EndpointHandler) GetAttachmentListByPostID(w http.ResponseWriter, r *http.Request) {
+	postID := r.PathValue("id")
+	if postID == "" {
+		common.WriteMessageResponse(w, "Post ID is required", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	attachmentList, err := h.service.GetAttachmentListByPostID(r.Context(), service.GetAttachmentListByPostIDParams{
+		User:   *user,
+		PostID: postID,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteJSONResponse(w, http.StatusOK, attachmentList)
+}
+
+// GetAttachmentContent serves the attachment itself. Attachments of published
+// posts are public, so the user is nil for anonymous requests.
+func (h *EndpointHandler) GetAttachmentContent(w http.ResponseWriter, r *http.Request) {
+	attachmentID := r.PathValue("id")
+	if attachmentID == "" {
+		common.WriteMessageResponse(w, "Attachment ID is required", http.StatusBadRequest)
+		return
+	}
+
+	content, err := h.service.GetAttachmentContent(r.Context(), service.GetAttachmentContentParams{
+		User:         middleware.GetUserFromContext(r.Context()),
+		AttachmentID: attachmentID,
+		Thumbnail:    r.URL.Query().Get("thumbnail") == "true",
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+	defer content.Body.Close()
+
+	// Only images are displayed inline, other files are downloaded
+	disposition := "attachment"
+	if strings.HasPrefix(content.ContentType, "image/") {
+		disposition = "inline"
+	}
+
+	cacheControl := "private, no-cache"
+	if content.IsPublic {
+		cacheControl = "public, max-age=3600"
+	}
+
+	w.Header().Set("Content-Type", content.ContentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": content.Attachment.FileName}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", cacheControl)
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, content.Body); err != nil {
+		slog.Error("Error writing attachment content: " + err.Error())
+	}
+}
+
+func (h *EndpointHandler) DeleteAttachmentByID(w http.ResponseWriter, r *http.Request) {
+	attachmentID := r.PathValue("id")
+	if attachmentID == "" {
+		common.WriteMessageResponse(w, "Attachment ID is required", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	err := h.service.DeleteAttachmentByID(r.Context(), service.DeleteAttachmentByIDParams{
+		User:         *user,
+		AttachmentID: attachmentID,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteMessageResponse(w, "Attachment deleted successfully", http.StatusOK)
+}
+
+func writeMultipartError(w http.ResponseWriter, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		common.WriteMessageResponse(w, "Request body is too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	common.WriteMessageResponse(w, "Invalid multipart body", http.StatusBadRequest)
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/http/common"
+	"github.com/jljl1337/issho/internal/http/middleware"
+	"github.com/jljl1337/issho/internal/service"
+)
+
+func (h *EndpointHandler) registerBookmarkRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("PUT /posts/{id}/bookmark", h.CreateBookmark)
+	mux.HandleFunc("DELETE /posts/{id}/bookmark", h.DeleteBookmark)
+	mux.HandleFunc("GET /users/me/bookmarks", h.GetBookmarkList)
+}
+
+func (h *EndpointHandler) CreateBookmark(w http.ResponseWriter, r *http.Request) {
+	// Parse post ID from URL path
+	postID := r.PathValue("id")
+	if postID == "" {
+		common.WriteMessageResponse(w, "Post ID is required", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	err := h.service.CreateBookmark(r.Context(), service.CreateBookmarkParams{
+		User:   *user,
+		PostID: postID,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteMessageResponse(w, "Post bookmarked successfully", http.StatusOK)
+}
+
+func (h *EndpointHandler) GetBookmarkList(w http.ResponseWriter, r *http.Request) {
+	// Parse query parameters
+	arg := service.GetBookmarkListParams{}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	arg.User = *user
+	arg.AcceptLanguage = r.Header.Get("Accept-Language")
+
+	cursor := r.URL.Query().Get("cursor")
+	if cursor != "" {
+		arg.Cursor = &cursor
+	}
+
+	cursorID := r.URL.Query().Get("cursor-id")
+	if cursorID != "" {
+		arg.CursorID = &cursorID
+	}
+
+	if (cursor != "" && cursorID == "") || (cursor == "" && cursorID != "") {
+		common.WriteMessageResponse(w, "Both cursor and cursor-id must be provided together", http.StatusBadRequest)
+		return
+	}
+
+	ascending := r.URL.Query().Get("ascending")
+	if ascending == "true" {
+		arg.Ascending = true
+	} else {
+		arg.Ascending = false
+	}
+
+	pageSize := r.URL.Query().Get("page-size")
+	if pageSize != "" {
+		var err error
+		arg.PageSize, err = strconv.Atoi(pageSize)
+		if err != nil {
+			common.WriteMessageResponse(w, "Invalid page-size parameter", http.StatusBadRequest)
+			return
+		}
+	} else {
+		arg.PageSize = env.PageSizeDefault
+	}
+
+	// Call service to get bookmark list
+	bookmarkList, err := h.service.GetBookmarkList(r.Context(), arg)
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteJSONResponse(w, http.StatusOK, bookmarkList)
+}
+
+func (h *EndpointHandler) DeleteBookmark(w http.ResponseWriter, r *http.Request) {
+	// Parse post ID from URL path
+	postID := r.PathValue("id")
+	if postID == "" {
+		common.WriteMessageResponse(w, "Post ID is required", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	err := h.service.DeleteBookmark(r.Context(), service.DeleteBookmarkParams{
+		User:   *user,
+		PostID: postID,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteMessageResponse(w, "Bookmark deleted successfully", http.StatusOK)
+}
@@ -0,0 +1,294 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/http/common"
+	"github.com/jljl1337/issho/internal/http/middleware"
+	"github.com/jljl1337/issho/internal/service"
+)
+
+type CreateCommentParams struct {
+	ParentID *string `json:"parentId"`
+	Content  string  `json:"content"`
+}
+
+func (h *EndpointHandler) registerCommentRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /posts/{id}/comments", h.CreateComment)
+	mux.HandleFunc("GET /posts/{id}/comments", h.GetCommentList)
+	mux.HandleFunc("GET /comments", h.GetModerationCommentList)
+	mux.HandleFunc("PUT /comments/{id}", h.UpdateCommentByID)
+	mux.HandleFunc("PATCH /comments/{id}/status", h.ModerateCommentByID)
+	mux.HandleFunc("DELETE /comments/{id}", h.DeleteCommentByID)
+}
+
+func (h *EndpointHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
+	postID := r.PathValue("id")
+	if postID == "" {
+		common.WriteMessageResponse(w, "Post ID is required", http.StatusBadRequest)
+		return
+	}
+
+	var req CreateCommentParams
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteMessageResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	err := h.service.CreateComment(r.Context(), service.CreateCommentParams{
+		User:     *user,
+		PostID:   postID,
+		ParentID: req.ParentID,
+		Content:  req.Content,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteMessageResponse(w, "Comment created successfully", http.StatusCreated)
+}
+
+func (h *EndpointHandler) GetCommentList(w http.ResponseWriter, r *http.Request) {
+	// Parse query parameters
+	arg := service.GetCommentListParams{}
+
+	arg.PostID = r.PathValue("id")
+	if arg.PostID == "" {
+		common.WriteMessageResponse(w, "Post ID is required", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	arg.User = *user
+
+	cursor := r.URL.Query().Get("cursor")
+	if cursor != "" {
+		arg.Cursor = &cursor
+	}
+
+	cursorID := r.URL.Query().Get("cursor-id")
+	if cursorID != "" {
+		arg.CursorID = &cursorID
+	}
+
+	if (cursor != "" && cursorID == "") || (cursor == "" && cursorID != "") {
+		common.WriteMessageResponse(w, "Both cursor and cursor-id must be provided together", http.StatusBadRequest)
+		return
+	}
+
+	// Comments read oldest first by default
+	arg.Ascending = r.URL.Query().Get("ascending") != "false"
+
+	pageSize := r.URL.Query().Get("page-size")
+	if pageSize != "" {
+		var err error
+		arg.PageSize, err = strconv.Atoi(pageSize)
+		if err != nil {
+			common.WriteMessageResponse(w, "Invalid page-size parameter", http.StatusBadRequest)
+			return
+		}
+	} else {
+		arg.PageSize = env.PageSizeDefault
+	}
+
+	// Call service to get comment list
+	commentList, err := h.service.GetCommentList(r.Context(), arg)
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteJSONResponse(w, http.StatusOK, commentList)
+}
+
+func (h *EndpointHandler) GetModerationCommentList(w http.ResponseWriter, r *http.Request) {
+	// Parse query parameters
+	arg := service.GetModerationCommentListParams{}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	arg.User = *user
+	arg.Status = r.URL.Query().Get("status")
+
+	cursor := r.URL.Query().Get("cursor")
+	if cursor != "" {
+		arg.Cursor = &cursor
+	}
+
+	cursorID := r.URL.Query().Get("cursor-id")
+	if cursorID != "" {
+		arg.CursorID = &cursorID
+	}
+
+	if (cursor != "" && cursorID == "") || (cursor == "" && cursorID != "") {
+		common.WriteMessageResponse(w, "Both cursor and cursor-id must be provided together", http.StatusBadRequest)
+		return
+	}
+
+	// The moderation queue is worked through oldest first by default
+	arg.Ascending = r.URL.Query().Get("ascending") != "false"
+
+	pageSize := r.URL.Query().Get("page-size")
+	if pageSize != "" {
+		var err error
+		arg.PageSize, err = strconv.Atoi(pageSize)
+		if err != nil {
+			common.WriteMessageResponse(w, "Invalid page-size parameter", http.StatusBadRequest)
+			return
+		}
+	} else {
+		arg.PageSize = env.PageSizeDefault
+	}
+
+	// Call service to get comment list
+	commentList, err := h.service.GetModerationCommentList(r.Context(), arg)
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteJSONResponse(w, http.StatusOK, commentList)
+}
+
+func (h *EndpointHandler) UpdateCommentByID(w http.ResponseWriter, r *http.Request) {
+	// Parse query parameters and request body
+	commentID := r.PathValue("id")
+	if commentID == "" {
+		common.WriteMessageResponse(w, "Comment ID is required", http.StatusBadRequest)
+		return
+	}
+
+	versions, ok := common.GetIfMatchVersions(r)
+	if !ok {
+		common.WriteIfMatchRequiredResponse(w)
+		return
+	}
+
+	var req struct {
+		Content string `json:"content"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteMessageResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Call service to update comment by ID
+	err := h.service.UpdateCommentByID(r.Context(), service.UpdateCommentByIDParams{
+		User:      *user,
+		CommentID: commentID,
+		Content:   req.Content,
+		Versions:  versions,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteMessageResponse(w, "Comment updated successfully", http.StatusOK)
+}
+
+func (h *EndpointHandler) ModerateCommentByID(w http.ResponseWriter, r *http.Request) {
+	// Parse query parameters and request body
+	commentID := r.PathValue("id")
+	if commentID == "" {
+		common.WriteMessageResponse(w, "Comment ID is required", http.StatusBadRequest)
+		return
+	}
+
+	versions, ok := common.GetIfMatchVersions(r)
+	if !ok {
+		common.WriteIfMatchRequiredResponse(w)
+		return
+	}
+
+	var req struct {
+		Status string `json:"status"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteMessageResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Status == "" {
+		common.WriteMessageResponse(w, "Status is required", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Call service to moderate comment by ID
+	err := h.service.ModerateCommentByID(r.Context(), service.ModerateCommentByIDParams{
+		User:      *user,
+		CommentID: commentID,
+		Status:    req.Status,
+		Versions:  versions,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteMessageResponse(w, "Comment status updated successfully", http.StatusOK)
+}
+
+func (h *EndpointHandler) DeleteCommentByID(w http.ResponseWriter, r *http.Request) {
+	// Parse query parameters
+	commentID := r.PathValue("id")
+	if commentID == "" {
+		common.WriteMessageResponse(w, "Comment ID is required", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Call service to delete comment by ID
+	err := h.service.DeleteCommentByID(r.Context(), service.DeleteCommentByIDParams{
+		User:      *user,
+		CommentID: commentID,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteMessageResponse(w, "Comment deleted successfully", http.StatusOK)
+}
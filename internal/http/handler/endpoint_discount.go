@@ -0,0 +1,233 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/http/common"
+	"github.com/jljl1337/issho/internal/http/middleware"
+	"github.com/jljl1337/issho/internal/service"
+)
+
+func (h *EndpointHandler) registerDiscountRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /discounts", h.CreateDiscount)
+	mux.HandleFunc("GET /discounts", h.GetDiscountList)
+	mux.HandleFunc("GET /discounts/{id}", h.GetDiscountByID)
+	mux.HandleFunc("PUT /discounts/{id}", h.UpdateDiscountByID)
+	mux.HandleFunc("DELETE /discounts/{id}", h.DeleteDiscountByID)
+}
+
+type CreateDiscountParams struct {
+	Code             string   `json:"code"`
+	Name             string   `json:"name"`
+	Type             string   `json:"type"`
+	Amount           int      `json:"amount"`
+	Currency         *string  `json:"currency"`
+	Duration         string   `json:"duration"`
+	DurationInMonths *int     `json:"durationInMonths"`
+	RedemptionMax    *int     `json:"redemptionMax"`
+	ExpiresAt        *string  `json:"expiresAt"`
+	PriceIDs         []string `json:"priceIds"`
+}
+
+func (h *EndpointHandler) CreateDiscount(w http.ResponseWriter, r *http.Request) {
+	var req CreateDiscountParams
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteMessageResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	err := h.service.CreateDiscount(r.Context(), service.CreateDiscountParams{
+		User:             *user,
+		Code:             req.Code,
+		Name:             req.Name,
+		Type:             req.Type,
+		Amount:           req.Amount,
+		Currency:         req.Currency,
+		Duration:         req.Duration,
+		DurationInMonths: req.DurationInMonths,
+		RedemptionMax:    req.RedemptionMax,
+		ExpiresAt:        req.ExpiresAt,
+		PriceIDs:         req.PriceIDs,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteMessageResponse(w, "Discount created successfully", http.StatusCreated)
+}
+
+func (h *EndpointHandler) GetDiscountList(w http.ResponseWriter, r *http.Request) {
+	// Parse query parameters
+	arg := service.GetDiscountListParams{}
+
+	cursor := r.URL.Query().Get("cursor")
+	if cursor != "" {
+		arg.Cursor = &cursor
+	}
+
+	cursorID := r.URL.Query().Get("cursor-id")
+	if cursorID != "" {
+		arg.CursorID = &cursorID
+	}
+
+	if (cursor != "" && cursorID == "") || (cursor == "" && cursorID != "") {
+		common.WriteMessageResponse(w, "Both cursor and cursor-id must be provided together", http.StatusBadRequest)
+		return
+	}
+
+	pageSize := r.URL.Query().Get("page-size")
+	if pageSize != "" {
+		ps, err := strconv.Atoi(pageSize)
+		if err != nil {
+			common.WriteMessageResponse(w, "Invalid page-size parameter", http.StatusBadRequest)
+			return
+		}
+		arg.PageSize = ps
+	} else {
+		arg.PageSize = env.PageSizeDefault
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	arg.User = *user
+
+	// Call service to get discount list
+	discountList, err := h.service.GetDiscountList(r.Context(), arg)
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteJSONResponse(w, http.StatusOK, discountList)
+}
+
+func (h *EndpointHandler) GetDiscountByID(w http.ResponseWriter, r *http.Request) {
+	// Parse discount ID from URL path
+	discountID := r.PathValue("id")
+	if discountID == "" {
+		common.WriteMessageResponse(w, "Discount ID is required", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Call service to get discount by ID
+	discount, err := h.service.GetDiscountByID(r.Context(), service.GetDiscountByIDParams{
+		User:       *user,
+		DiscountID: discountID,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.SetETag(w, discount.UpdatedAt)
+	if common.IsNoneMatch(r, common.ETag(discount.UpdatedAt)) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	common.WriteJSONResponse(w, http.StatusOK, discount)
+}
+
+func (h *EndpointHandler) UpdateDiscountByID(w http.ResponseWriter, r *http.Request) {
+	// Parse query parameters and request body
+	discountID := r.PathValue("id")
+	if discountID == "" {
+		common.WriteMessageResponse(w, "Discount ID is required", http.StatusBadRequest)
+		return
+	}
+
+	versions, ok := common.GetIfMatchVersions(r)
+	if !ok {
+		common.WriteIfMatchRequiredResponse(w)
+		return
+	}
+
+	var req struct {
+		Name          string   `json:"name"`
+		RedemptionMax *int     `json:"redemptionMax"`
+		ExpiresAt     *string  `json:"expiresAt"`
+		IsActive      bool     `json:"isActive"`
+		PriceIDs      []string `json:"priceIds"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteMessageResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Call service to update discount by ID
+	err := h.service.UpdateDiscountByID(r.Context(), service.UpdateDiscountByIDParams{
+		User:          *user,
+		DiscountID:    discountID,
+		Name:          req.Name,
+		RedemptionMax: req.RedemptionMax,
+		ExpiresAt:     req.ExpiresAt,
+		IsActive:      req.IsActive,
+		PriceIDs:      req.PriceIDs,
+		Versions:      versions,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteMessageResponse(w, "Discount updated successfully", http.StatusOK)
+}
+
+func (h *EndpointHandler) DeleteDiscountByID(w http.ResponseWriter, r *http.Request) {
+	// Parse discount ID from URL path
+	discountID := r.PathValue("id")
+	if discountID == "" {
+		common.WriteMessageResponse(w, "Discount ID is required", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Call service to delete discount by ID
+	err := h.service.DeleteDiscountByID(r.Context(), service.DeleteDiscountByIDParams{
+		User:       *user,
+		DiscountID: discountID,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteMessageResponse(w, "Discount deleted successfully", http.StatusOK)
+}
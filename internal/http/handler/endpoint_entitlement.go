@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/http/common"
+	"github.com/jljl1337/issho/internal/http/middleware"
+	"github.com/jljl1337/issho/internal/service"
+)
+
+type CreateEntitlementParams struct {
+	UserID    string  `json:"userId"`
+	ProductID string  `json:"productId"`
+	EndsAt    *string `json:"endsAt"`
+}
+
+func (h *EndpointHandler) registerEntitlementRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /entitlements", h.CreateEntitlement)
+	mux.HandleFunc("GET /entitlements", h.GetEntitlementList)
+	mux.HandleFunc("DELETE /entitlements/{id}", h.DeleteEntitlementByID)
+}
+
+func (h *EndpointHandler) CreateEntitlement(w http.ResponseWriter, r *http.Request) {
+	var req CreateEntitlementParams
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteMessageResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.UserID == "" || req.ProductID == "" {
+		common.WriteMessageResponse(w, "User ID and product ID are required", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	err := h.service.CreateEntitlement(r.Context(), service.CreateEntitlementParams{
+		User:      *user,
+		UserID:    req.UserID,
+		ProductID: req.ProductID,
+		EndsAt:    req.EndsAt,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteMessageResponse(w, "Entitlement created successfully", http.StatusCreated)
+}
+
+func (h *EndpointHandler) GetEntitlementList(w http.ResponseWriter, r *http.Request) {
+	// Parse query parameters
+	arg := service.GetEntitlementListParams{}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	arg.User = *user
+
+	userID := r.URL.Query().Get("user-id")
+	if userID != "" {
+		arg.UserID = &userID
+	}
+
+	productID := r.URL.Query().Get("product-id")
+	if productID != "" {
+		arg.ProductID = &productID
+	}
+
+	cursor := r.URL.Query().Get("cursor")
+	if cursor != "" {
+		arg.Cursor = &cursor
+	}
+
+	cursorID := r.URL.Query().Get("cursor-id")
+	if cursorID != "" {
+		arg.CursorID = &cursorID
+	}
+
+	if (cursor != "" && cursorID == "") || (cursor == "" && cursorID != "") {
+		common.WriteMessageResponse(w, "Both cursor and cursor-id must be provided together", http.StatusBadRequest)
+		return
+	}
+
+	pageSize := r.URL.Query().Get("page-size")
+	if pageSize != "" {
+		var err error
+		arg.PageSize, err = strconv.Atoi(pageSize)
+		if err != nil {
+			common.WriteMessageResponse(w, "Invalid page-size parameter", http.StatusBadRequest)
+			return
+		}
+	} else {
+		arg.PageSize = env.PageSizeDefault
+	}
+
+	// Call service to get entitlement list
+	entitlementList, err := h.service.GetEntitlementList(r.Context(), arg)
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteJSONResponse(w, http.StatusOK, entitlementList)
+}
+
+func (h *EndpointHandler) DeleteEntitlementByID(w http.ResponseWriter, r *http.Request) {
+	// Parse query parameters
+	entitlementID := r.PathValue("id")
+	if entitlementID == "" {
+		common.WriteMessageResponse(w, "Entitlement ID is required", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Call service to delete entitlement by ID
+	err := h.service.DeleteEntitlementByID(r.Context(), service.DeleteEntitlementByIDParams{
+		User:          *user,
+		EntitlementID: entitlementID,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteMessageResponse(w, "Entitlement deleted successfully", http.StatusOK)
+}
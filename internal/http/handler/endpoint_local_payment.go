@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/http/common"
+	"github.com/jljl1337/issho/internal/service"
+)
+
+func (h *EndpointHandler) registerLocalPaymentRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /payment/local/checkouts/{id}", h.GetLocalCheckoutPage)
+	mux.HandleFunc("POST /payment/local/checkouts/{id}", h.SubmitLocalCheckout)
+}
+
+// GetLocalCheckoutPage serves the checkout page of the local payment provider,
+// which stands in for the hosted checkout page of a real provider.
+func (h *EndpointHandler) GetLocalCheckoutPage(w http.ResponseWriter, r *http.Request) {
+	checkoutID := r.PathValue("id")
+	if checkoutID == "" {
+		common.WriteMessageResponse(w, "Checkout ID is required", http.StatusBadRequest)
+		return
+	}
+
+	checkout, err := h.service.GetLocalCheckout(r.Context(), service.GetLocalCheckoutParams{
+		CheckoutID: checkoutID,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	price := formatLocalAmount(checkout.Price.PriceAmount, checkout.Price.PriceCurrency)
+	if checkout.Price.IsRecurring {
+		price += fmt.Sprintf(" every %d %s(s)", *checkout.Price.RecurringIntervalCount, *checkout.Price.RecurringInterval)
+	}
+
+	if checkout.Discount != nil {
+		price += fmt.Sprintf(", %s with %s", formatLocalAmount(checkout.TotalAmount(), checkout.Price.PriceCurrency), checkout.Discount.Name)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(
+		w,
+		"<!DOCTYPE html><html><head><meta charset=\"utf-8\"><title>Checkout</title></head><body><form method=\"post\"><p>%s - %s</p><p>%s</p><p>This is a test checkout of %s, no payment is made.</p><button type=\"submit\" name=\"action\" value=\"pay\">Pay</button> <button type=\"submit\" name=\"action\" value=\"cancel\">Cancel</button></form></body></html>",
+		html.EscapeString(checkout.Price.Name),
+		html.EscapeString(price),
+		html.EscapeString(checkout.Price.Description),
+		html.EscapeString(env.SiteTitle),
+	)
+}
+
+func formatLocalAmount(amount int, currency string) string {
+	return fmt.Sprintf("%d.%02d %s", amount/100, amount%100, strings.ToUpper(currency))
+}
+
+// SubmitLocalCheckout handles the form of the checkout page, and redirects to
+// the success or cancel URL like a real provider.
+func (h *EndpointHandler) SubmitLocalCheckout(w http.ResponseWriter, r *http.Request) {
+	checkoutID := r.PathValue("id")
+	if checkoutID == "" {
+		common.WriteMessageResponse(w, "Checkout ID is required", http.StatusBadRequest)
+		return
+	}
+
+	var redirectURL string
+	var err error
+	switch r.FormValue("action") {
+	case "pay":
+		redirectURL, err = h.service.CompleteLocalCheckout(r.Context(), service.CompleteLocalCheckoutParams{
+			CheckoutID: checkoutID,
+		})
+	case "cancel":
+		redirectURL, err = h.service.CancelLocalCheckout(r.Context(), service.CancelLocalCheckoutParams{
+			CheckoutID: checkoutID,
+		})
+	default:
+		common.WriteMessageResponse(w, "Invalid action", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+}
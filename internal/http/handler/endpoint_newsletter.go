@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"log/slog"
+	"net/http"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/http/common"
+	"github.com/jljl1337/issho/internal/http/middleware"
+	"github.com/jljl1337/issho/internal/service"
+)
+
+func (h *EndpointHandler) registerNewsletterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /users/me/newsletter", h.GetNewsletterStatus)
+	mux.HandleFunc("PUT /users/me/newsletter", h.SubscribeNewsletter)
+	mux.HandleFunc("DELETE /users/me/newsletter", h.UnsubscribeNewsletter)
+	mux.HandleFunc("POST /newsletter/subscriptions", h.CreateNewsletterSubscription)
+	mux.HandleFunc("GET /newsletter/confirm", h.GetNewsletterConfirmPage)
+	mux.HandleFunc("POST /newsletter/confirm", h.ConfirmNewsletterSubscription)
+	mux.HandleFunc("GET /newsletter/unsubscribe", h.GetNewsletterUnsubscribePage)
+	mux.HandleFunc("POST /newsletter/unsubscribe", h.DeleteNewsletterSubscriptionByToken)
+}
+
+func (h *EndpointHandler) GetNewsletterStatus(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	status, err := h.service.GetNewsletterStatus(r.Context(), *user)
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteJSONResponse(w, http.StatusOK, status)
+}
+
+func (h *EndpointHandler) SubscribeNewsletter(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	err := h.service.SubscribeNewsletter(r.Context(), *user)
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteMessageResponse(w, "Subscribed to newsletter successfully", http.StatusOK)
+}
+
+func (h *EndpointHandler) UnsubscribeNewsletter(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	err := h.service.UnsubscribeNewsletter(r.Context(), *user)
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteMessageResponse(w, "Unsubscribed from newsletter successfully", http.StatusOK)
+}
+
+func (h *EndpointHandler) CreateNewsletterSubscription(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var req struct {
+		Email string `json:"email"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteMessageResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Email == "" {
+		common.WriteMessageResponse(w, "Email is required", http.StatusBadRequest)
+		return
+	}
+
+	err := h.service.CreateNewsletterSubscription(r.Context(), service.CreateNewsletterSubscriptionParams{
+		Email: req.Email,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteMessageResponse(w, "Please check your email to confirm the subscription", http.StatusAccepted)
+}
+
+// GetNewsletterConfirmPage serves the page of the confirmation link in the
+// email body. Confirming takes a POST, so that link scanners opening the link
+// do not confirm the subscription on behalf of the recipient.
+func (h *EndpointHandler) GetNewsletterConfirmPage(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		common.WriteMessageResponse(w, "Token is required", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(
+		w,
+		"<!DOCTYPE html><html><head><meta charset=\"utf-8\"><title>Confirm subscription</title></head><body><form method=\"post\"><p>Subscribe to the %s newsletter?</p><button type=\"submit\">Confirm</button></form></body></html>",
+		html.EscapeString(env.SiteTitle),
+	)
+}
+
+func (h *EndpointHandler) ConfirmNewsletterSubscription(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		common.WriteMessageResponse(w, "Token is required", http.StatusBadRequest)
+		return
+	}
+
+	err := h.service.ConfirmNewsletterSubscription(r.Context(), service.ConfirmNewsletterSubscriptionParams{
+		Token: token,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteMessageResponse(w, "Newsletter subscription confirmed successfully", http.StatusOK)
+}
+
+// GetNewsletterUnsubscribePage serves the page of the unsubscribe link in the
+// email body. Unsubscribing takes a POST, so that link scanners opening the
+// link do not unsubscribe the recipient.
+func (h *EndpointHandler) GetNewsletterUnsubscribePage(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		common.WriteMessageResponse(w, "Token is required", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(
+		w,
+		"<!DOCTYPE html><html><head><meta charset=\"utf-8\"><title>Unsubscribe</title></head><body><form method=\"post\"><p>Unsubscribe from the %s newsletter?</p><button type=\"submit\" name=\"List-Unsubscribe\" value=\"One-Click\">Unsubscribe</button></form></body></html>",
+		html.EscapeString(env.SiteTitle),
+	)
+}
+
+// DeleteNewsletterSubscriptionByToken handles both the form of the unsubscribe
+// page and the one-click unsubscribe of email clients in RFC 8058.
+func (h *EndpointHandler) DeleteNewsletterSubscriptionByToken(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		common.WriteMessageResponse(w, "Token is required", http.StatusBadRequest)
+		return
+	}
+
+	err := h.service.DeleteNewsletterSubscriptionByToken(r.Context(), service.DeleteNewsletterSubscriptionByTokenParams{
+		Token: token,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteMessageResponse(w, "Unsubscribed from newsletter successfully", http.StatusOK)
+}
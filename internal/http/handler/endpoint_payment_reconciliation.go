@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/jljl1337/issho/internal/http/common"
+	"github.com/jljl1337/issho/internal/http/middleware"
+	"github.com/jljl1337/issho/internal/service"
+)
+
+func (h *EndpointHandler) registerPaymentReconciliationRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /admin/payment/reconciliation", h.GetPaymentReconciliationIssueList)
+	mux.HandleFunc("POST /admin/payment/reconciliation", h.RunPaymentReconciliation)
+}
+
+func (h *EndpointHandler) GetPaymentReconciliationIssueList(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Call service to get payment reconciliation issues
+	issues, err := h.service.GetPaymentReconciliationIssueList(r.Context(), service.GetPaymentReconciliationIssueListParams{
+		User: *user,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteJSONResponse(w, http.StatusOK, issues)
+}
+
+func (h *EndpointHandler) RunPaymentReconciliation(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Call service to run payment reconciliation
+	issues, err := h.service.RunPaymentReconciliation(r.Context(), service.RunPaymentReconciliationParams{
+		User: *user,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteJSONResponse(w, http.StatusOK, issues)
+}
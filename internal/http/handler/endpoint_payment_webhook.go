@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/jljl1337/issho/internal/http/common"
+	"github.com/jljl1337/issho/internal/service"
+)
+
+// paymentWebhookSizeMaxBytes is the maximum size of a webhook payload, which
+// is far larger than the events of payment providers.
+const paymentWebhookSizeMaxBytes = 1 << 20
+
+func (h *EndpointHandler) registerPaymentWebhookRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /webhooks/payment/{provider}", h.ReceivePaymentWebhook)
+}
+
+func (h *EndpointHandler) ReceivePaymentWebhook(w http.ResponseWriter, r *http.Request) {
+	// Parse provider from URL path
+	provider := r.PathValue("provider")
+	if provider == "" {
+		common.WriteMessageResponse(w, "Provider is required", http.StatusBadRequest)
+		return
+	}
+
+	// The raw body is needed to verify the signature
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, paymentWebhookSizeMaxBytes))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			common.WriteMessageResponse(w, "Request body is too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		common.WriteMessageResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.ReceivePaymentWebhook(r.Context(), service.ReceivePaymentWebhookParams{
+		Provider: provider,
+		Header:   r.Header,
+		Body:     body,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteMessageResponse(w, "Webhook received successfully", http.StatusAccepted)
+}
@@ -13,10 +13,15 @@ import (
 )
 
 type CreatePostParams struct {
-	Title       string  `json:"title"`
-	Description string  `json:"description"`
-	Content     string  `json:"content"`
-	PublishedAt *string `json:"publishedAt"`
+	Title        string   `json:"title"`
+	Description  string   `json:"description"`
+	Content      string   `json:"content"`
+	PublishedAt  *string  `json:"publishedAt"`
+	Tags         []string `json:"tags"`
+	Visibility   string   `json:"visibility"`
+	ProductIDs   []string `json:"productIDs"`
+	LanguageCode string   `json:"languageCode"`
+	Slug         *string  `json:"slug"`
 }
 
 func (h *EndpointHandler) registerPostRoutes(mux *http.ServeMux) {
@@ -25,6 +30,8 @@ func (h *EndpointHandler) registerPostRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /posts/{id}", h.GetPostByID)
 	mux.HandleFunc("PUT /posts/{id}", h.UpdatePostByID)
 	mux.HandleFunc("DELETE /posts/{id}", h.DeletePostByID)
+	mux.HandleFunc("GET /posts/trash", h.GetTrashedPostList)
+	mux.HandleFunc("POST /posts/{id}/restore", h.RestorePostByID)
 }
 
 func (h *EndpointHandler) CreatePost(w http.ResponseWriter, r *http.Request) {
@@ -42,11 +49,16 @@ func (h *EndpointHandler) CreatePost(w http.ResponseWriter, r *http.Request) {
 	}
 
 	err := h.service.CreatePost(r.Context(), service.CreatePostParams{
-		User:        *user,
-		Title:       req.Title,
-		Description: req.Description,
-		Content:     req.Content,
-		PublishedAt: req.PublishedAt,
+		User:         *user,
+		Title:        req.Title,
+		Description:  req.Description,
+		Content:      req.Content,
+		PublishedAt:  req.PublishedAt,
+		Tags:         req.Tags,
+		Visibility:   req.Visibility,
+		ProductIDs:   req.ProductIDs,
+		LanguageCode: req.LanguageCode,
+		Slug:         req.Slug,
 	})
 	if err != nil {
 		common.WriteErrorResponse(w, err)
@@ -68,12 +80,18 @@ func (h *EndpointHandler) GetPostList(w http.ResponseWriter, r *http.Request) {
 	}
 
 	arg.User = *user
+	arg.AcceptLanguage = r.Header.Get("Accept-Language")
 
 	userID := r.URL.Query().Get("user-id")
 	if userID != "" {
 		arg.UserID = &userID
 	}
 
+	tag := r.URL.Query().Get("tag")
+	if tag != "" {
+		arg.Tag = &tag
+	}
+
 	searchQuery := r.URL.Query().Get("search-query")
 	if searchQuery != "" {
 		arg.SearchQuery = &searchQuery
@@ -154,14 +172,45 @@ func (h *EndpointHandler) GetPostByID(w http.ResponseWriter, r *http.Request) {
 
 	// Call service to get post by ID
 	post, err := h.service.GetPostByID(r.Context(), service.GetPostByIDParams{
-		User:   *user,
-		PostID: postID,
+		User:           *user,
+		PostID:         postID,
+		AcceptLanguage: r.Header.Get("Accept-Language"),
 	})
 	if err != nil {
 		common.WriteErrorResponse(w, err)
 		return
 	}
 
+	// Failing to count the view should not fail the request
+	var sessionToken string
+	if cookie, err := r.Cookie(env.SessionCookieName); err == nil {
+		sessionToken = cookie.Value
+	}
+
+	err = h.service.RecordPostView(r.Context(), service.RecordPostViewParams{
+		User:         *user,
+		PostID:       postID,
+		SessionToken: sessionToken,
+	})
+	if err != nil {
+		slog.Error("Error recording post view: " + err.Error())
+	}
+
+	w.Header().Set("Vary", "Accept-Language")
+
+	// The teaser of a paid post depends on the entitlements of the viewer, the
+	// translation on the language of the viewer, and the series navigation and
+	// reactions on other rows, instead of the post version, so they are not
+	// given an ETag
+	isTranslated := post.ContentLanguageCode != post.LanguageCode
+	if !post.IsTeaser && !isTranslated && post.Series == nil && len(post.Reactions) == 0 {
+		common.SetETag(w, post.UpdatedAt)
+		if common.IsNoneMatch(r, common.ETag(post.UpdatedAt)) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
 	common.WriteJSONResponse(w, http.StatusOK, post)
 }
 
@@ -173,11 +222,22 @@ func (h *EndpointHandler) UpdatePostByID(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	versions, ok := common.GetIfMatchVersions(r)
+	if !ok {
+		common.WriteIfMatchRequiredResponse(w)
+		return
+	}
+
 	var req struct {
-		Title       string  `json:"title"`
-		Description string  `json:"description"`
-		Content     string  `json:"content"`
-		PublishedAt *string `json:"publishedAt"`
+		Title        string    `json:"title"`
+		Description  string    `json:"description"`
+		Content      string    `json:"content"`
+		PublishedAt  *string   `json:"publishedAt"`
+		Tags         *[]string `json:"tags"`
+		Visibility   *string   `json:"visibility"`
+		ProductIDs   *[]string `json:"productIDs"`
+		LanguageCode *string   `json:"languageCode"`
+		Slug         *string   `json:"slug"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		common.WriteMessageResponse(w, "Invalid request body", http.StatusBadRequest)
@@ -193,12 +253,18 @@ func (h *EndpointHandler) UpdatePostByID(w http.ResponseWriter, r *http.Request)
 
 	// Call service to update post by ID
 	err := h.service.UpdatePostByID(r.Context(), service.UpdatePostByIDParams{
-		User:        *user,
-		PostID:      postID,
-		Title:       req.Title,
-		Description: req.Description,
-		Content:     req.Content,
-		PublishedAt: req.PublishedAt,
+		User:         *user,
+		PostID:       postID,
+		Title:        req.Title,
+		Description:  req.Description,
+		Content:      req.Content,
+		PublishedAt:  req.PublishedAt,
+		Tags:         req.Tags,
+		Visibility:   req.Visibility,
+		ProductIDs:   req.ProductIDs,
+		LanguageCode: req.LanguageCode,
+		Slug:         req.Slug,
+		Versions:     versions,
 	})
 	if err != nil {
 		common.WriteErrorResponse(w, err)
@@ -235,3 +301,81 @@ func (h *EndpointHandler) DeletePostByID(w http.ResponseWriter, r *http.Request)
 
 	common.WriteMessageResponse(w, "Post deleted successfully", http.StatusOK)
 }
+
+func (h *EndpointHandler) GetTrashedPostList(w http.ResponseWriter, r *http.Request) {
+	// Parse query parameters
+	arg := service.GetTrashedPostListParams{}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	arg.User = *user
+
+	cursor := r.URL.Query().Get("cursor")
+	if cursor != "" {
+		arg.Cursor = &cursor
+	}
+
+	cursorID := r.URL.Query().Get("cursor-id")
+	if cursorID != "" {
+		arg.CursorID = &cursorID
+	}
+
+	if (cursor != "" && cursorID == "") || (cursor == "" && cursorID != "") {
+		common.WriteMessageResponse(w, "Both cursor and cursor-id must be provided together", http.StatusBadRequest)
+		return
+	}
+
+	pageSize := r.URL.Query().Get("page-size")
+	if pageSize != "" {
+		var err error
+		arg.PageSize, err = strconv.Atoi(pageSize)
+		if err != nil {
+			common.WriteMessageResponse(w, "Invalid page-size parameter", http.StatusBadRequest)
+			return
+		}
+	} else {
+		arg.PageSize = env.PageSizeDefault
+	}
+
+	// Call service to get trashed post list
+	postList, err := h.service.GetTrashedPostList(r.Context(), arg)
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteJSONResponse(w, http.StatusOK, postList)
+}
+
+func (h *EndpointHandler) RestorePostByID(w http.ResponseWriter, r *http.Request) {
+	// Parse query parameters
+	postID := r.PathValue("id")
+	if postID == "" {
+		common.WriteMessageResponse(w, "Post ID is required", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Call service to restore post by ID
+	err := h.service.RestorePostByID(r.Context(), service.RestorePostByIDParams{
+		User:   *user,
+		PostID: postID,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteMessageResponse(w, "Post restored successfully", http.StatusOK)
+}
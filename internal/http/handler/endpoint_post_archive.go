@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/generator"
+	"github.com/jljl1337/issho/internal/http/common"
+	"github.com/jljl1337/issho/internal/http/middleware"
+	"github.com/jljl1337/issho/internal/service"
+)
+
+func (h *EndpointHandler) registerPostArchiveRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /posts/export", h.ExportPosts)
+	mux.HandleFunc("POST /posts/import", h.ImportPosts)
+}
+
+func (h *EndpointHandler) ExportPosts(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Build the archive first, so that an error can still be responded
+	var buffer bytes.Buffer
+	err := h.service.ExportPosts(r.Context(), service.ExportPostsParams{
+		User: *user,
+	}, &buffer)
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	fileName := "issho-posts-" + generator.DaysFromTodayDate(0) + ".zip"
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	w.WriteHeader(http.StatusOK)
+	w.Write(buffer.Bytes())
+}
+
+func (h *EndpointHandler) ImportPosts(w http.ResponseWriter, r *http.Request) {
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Authors are mapped with repeated author=name=username parameters, split
+	// at the last "=" as usernames cannot contain it
+	authorMap := make(map[string]string)
+	for _, author := range r.URL.Query()["author"] {
+		i := strings.LastIndex(author, "=")
+		if i <= 0 || i == len(author)-1 {
+			common.WriteMessageResponse(w, "Invalid author parameter", http.StatusBadRequest)
+			return
+		}
+		authorMap[author[:i]] = author[i+1:]
+	}
+
+	dryRun := r.URL.Query().Get("dry-run") == "true"
+
+	r.Body = http.MaxBytesReader(w, r.Body, env.PostImportSizeMaxBytes+multipartOverheadBytes)
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		common.WriteMessageResponse(w, "Request body must be multipart/form-data", http.StatusBadRequest)
+		return
+	}
+
+	// Read the first part named "file"
+	var data []byte
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			writeMultipartError(w, err)
+			return
+		}
+
+		if part.FormName() != "file" {
+			part.Close()
+			continue
+		}
+
+		data, err = io.ReadAll(part)
+		part.Close()
+		if err != nil {
+			writeMultipartError(w, err)
+			return
+		}
+		break
+	}
+
+	if data == nil {
+		common.WriteMessageResponse(w, "File is required", http.StatusBadRequest)
+		return
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		common.WriteMessageResponse(w, "File must be a ZIP archive", http.StatusBadRequest)
+		return
+	}
+
+	// Call service to import posts
+	result, err := h.service.ImportPosts(r.Context(), service.ImportPostsParams{
+		User:      *user,
+		Files:     archive,
+		AuthorMap: authorMap,
+		DryRun:    dryRun,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	status := http.StatusCreated
+	if dryRun {
+		status = http.StatusOK
+	}
+
+	common.WriteJSONResponse(w, status, result)
+}
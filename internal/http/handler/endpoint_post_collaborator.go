@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/jljl1337/issho/internal/http/common"
+	"github.com/jljl1337/issho/internal/http/middleware"
+	"github.com/jljl1337/issho/internal/service"
+)
+
+func (h *EndpointHandler) registerPostCollaboratorRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /posts/{id}/collaborators", h.GetPostCollaboratorList)
+	mux.HandleFunc("PUT /posts/{id}/collaborators/{userId}", h.UpsertPostCollaborator)
+	mux.HandleFunc("DELETE /posts/{id}/collaborators/{userId}", h.DeletePostCollaborator)
+	mux.HandleFunc("POST /posts/{id}/transfer", h.TransferPostByID)
+}
+
+func (h *EndpointHandler) GetPostCollaboratorList(w http.ResponseWriter, r *http.Request) {
+	// Parse query parameters
+	postID := r.PathValue("id")
+	if postID == "" {
+		common.WriteMessageResponse(w, "Post ID is required", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Call service to get post collaborator list
+	collaboratorList, err := h.service.GetPostCollaboratorList(r.Context(), service.GetPostCollaboratorListParams{
+		User:   *user,
+		PostID: postID,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteJSONResponse(w, http.StatusOK, collaboratorList)
+}
+
+func (h *EndpointHandler) UpsertPostCollaborator(w http.ResponseWriter, r *http.Request) {
+	// Parse query parameters and request body
+	postID := r.PathValue("id")
+	userID := r.PathValue("userId")
+	if postID == "" || userID == "" {
+		common.WriteMessageResponse(w, "Post ID and user ID are required", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Role string `json:"role"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteMessageResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Call service to upsert post collaborator
+	err := h.service.UpsertPostCollaborator(r.Context(), service.UpsertPostCollaboratorParams{
+		User:   *user,
+		PostID: postID,
+		UserID: userID,
+		Role:   req.Role,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteMessageResponse(w, "Post collaborator saved successfully", http.StatusOK)
+}
+
+func (h *EndpointHandler) DeletePostCollaborator(w http.ResponseWriter, r *http.Request) {
+	// Parse query parameters
+	postID := r.PathValue("id")
+	userID := r.PathValue("userId")
+	if postID == "" || userID == "" {
+		common.WriteMessageResponse(w, "Post ID and user ID are required", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Call service to delete post collaborator
+	err := h.service.DeletePostCollaborator(r.Context(), service.DeletePostCollaboratorParams{
+		User:   *user,
+		PostID: postID,
+		UserID: userID,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteMessageResponse(w, "Post collaborator deleted successfully", http.StatusOK)
+}
+
+func (h *EndpointHandler) TransferPostByID(w http.ResponseWriter, r *http.Request) {
+	// Parse query parameters and request body
+	postID := r.PathValue("id")
+	if postID == "" {
+		common.WriteMessageResponse(w, "Post ID is required", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		UserID string `json:"userId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteMessageResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.UserID == "" {
+		common.WriteMessageResponse(w, "User ID is required", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Call service to transfer post by ID
+	err := h.service.TransferPostByID(r.Context(), service.TransferPostByIDParams{
+		User:   *user,
+		PostID: postID,
+		UserID: req.UserID,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteMessageResponse(w, "Post transferred successfully", http.StatusOK)
+}
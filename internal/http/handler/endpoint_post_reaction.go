@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/jljl1337/issho/internal/http/common"
+	"github.com/jljl1337/issho/internal/http/middleware"
+	"github.com/jljl1337/issho/internal/service"
+)
+
+func (h *EndpointHandler) registerPostReactionRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("PUT /posts/{id}/reactions/{type}", h.CreatePostReaction)
+	mux.HandleFunc("DELETE /posts/{id}/reactions/{type}", h.DeletePostReaction)
+}
+
+func (h *EndpointHandler) CreatePostReaction(w http.ResponseWriter, r *http.Request) {
+	// Parse post ID and reaction type from URL path
+	postID := r.PathValue("id")
+	if postID == "" {
+		common.WriteMessageResponse(w, "Post ID is required", http.StatusBadRequest)
+		return
+	}
+
+	reactionType := r.PathValue("type")
+	if reactionType == "" {
+		common.WriteMessageResponse(w, "Reaction type is required", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	err := h.service.CreatePostReaction(r.Context(), service.CreatePostReactionParams{
+		User:   *user,
+		PostID: postID,
+		Type:   reactionType,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteMessageResponse(w, "Reaction added successfully", http.StatusOK)
+}
+
+func (h *EndpointHandler) DeletePostReaction(w http.ResponseWriter, r *http.Request) {
+	// Parse post ID and reaction type from URL path
+	postID := r.PathValue("id")
+	if postID == "" {
+		common.WriteMessageResponse(w, "Post ID is required", http.StatusBadRequest)
+		return
+	}
+
+	reactionType := r.PathValue("type")
+	if reactionType == "" {
+		common.WriteMessageResponse(w, "Reaction type is required", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	err := h.service.DeletePostReaction(r.Context(), service.DeletePostReactionParams{
+		User:   *user,
+		PostID: postID,
+		Type:   reactionType,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteMessageResponse(w, "Reaction deleted successfully", http.StatusOK)
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/http/common"
+	"github.com/jljl1337/issho/internal/http/middleware"
+	"github.com/jljl1337/issho/internal/service"
+)
+
+func (h *EndpointHandler) registerPostStatsRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /posts/{id}/stats", h.GetPostStats)
+	mux.HandleFunc("GET /posts/top", h.GetTopPostList)
+}
+
+func (h *EndpointHandler) GetPostStats(w http.ResponseWriter, r *http.Request) {
+	// Parse post ID from URL path
+	postID := r.PathValue("id")
+	if postID == "" {
+		common.WriteMessageResponse(w, "Post ID is required", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	arg := service.GetPostStatsParams{
+		User:   *user,
+		PostID: postID,
+	}
+
+	days := r.URL.Query().Get("days")
+	if days != "" {
+		var err error
+		arg.Days, err = strconv.Atoi(days)
+		if err != nil {
+			common.WriteMessageResponse(w, "Invalid days parameter", http.StatusBadRequest)
+			return
+		}
+	} else {
+		arg.Days = env.PostStatsDaysDefault
+	}
+
+	// Call service to get post stats
+	stats, err := h.service.GetPostStats(r.Context(), arg)
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteJSONResponse(w, http.StatusOK, stats)
+}
+
+func (h *EndpointHandler) GetTopPostList(w http.ResponseWriter, r *http.Request) {
+	// Parse query parameters
+	arg := service.GetTopPostListParams{}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	arg.User = *user
+
+	days := r.URL.Query().Get("days")
+	if days != "" {
+		var err error
+		arg.Days, err = strconv.Atoi(days)
+		if err != nil {
+			common.WriteMessageResponse(w, "Invalid days parameter", http.StatusBadRequest)
+			return
+		}
+	} else {
+		arg.Days = env.PostStatsDaysDefault
+	}
+
+	pageSize := r.URL.Query().Get("page-size")
+	if pageSize != "" {
+		var err error
+		arg.PageSize, err = strconv.Atoi(pageSize)
+		if err != nil {
+			common.WriteMessageResponse(w, "Invalid page-size parameter", http.StatusBadRequest)
+			return
+		}
+	} else {
+		arg.PageSize = env.PageSizeDefault
+	}
+
+	// Call service to get top post list
+	postList, err := h.service.GetTopPostList(r.Context(), arg)
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteJSONResponse(w, http.StatusOK, postList)
+}
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/http/common"
+	"github.com/jljl1337/issho/internal/http/middleware"
+	"github.com/jljl1337/issho/internal/service"
+)
+
+func (h *EndpointHandler) registerPostTranslationRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /posts/missing-translations", h.GetPostListMissingTranslation)
+	mux.HandleFunc("GET /posts/{id}/translations", h.GetPostTranslationList)
+	mux.HandleFunc("PUT /posts/{id}/translations/{languageCode}", h.UpsertPostTranslation)
+	mux.HandleFunc("DELETE /posts/{id}/translations/{languageCode}", h.DeletePostTranslation)
+}
+
+func (h *EndpointHandler) UpsertPostTranslation(w http.ResponseWriter, r *http.Request) {
+	// Parse query parameters and request body
+	postID := r.PathValue("id")
+	languageCode := r.PathValue("languageCode")
+	if postID == "" || languageCode == "" {
+		common.WriteMessageResponse(w, "Post ID and language code are required", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Content     string `json:"content"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteMessageResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Call service to upsert post translation
+	err := h.service.UpsertPostTranslation(r.Context(), service.UpsertPostTranslationParams{
+		User:         *user,
+		PostID:       postID,
+		LanguageCode: languageCode,
+		Title:        req.Title,
+		Description:  req.Description,
+		Content:      req.Content,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteMessageResponse(w, "Post translation saved successfully", http.StatusOK)
+}
+
+func (h *EndpointHandler) GetPostTranslationList(w http.ResponseWriter, r *http.Request) {
+	// Parse query parameters
+	postID := r.PathValue("id")
+	if postID == "" {
+		common.WriteMessageResponse(w, "Post ID is required", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Call service to get post translation list
+	translationList, err := h.service.GetPostTranslationList(r.Context(), service.GetPostTranslationListParams{
+		User:   *user,
+		PostID: postID,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteJSONResponse(w, http.StatusOK, translationList)
+}
+
+func (h *EndpointHandler) DeletePostTranslation(w http.ResponseWriter, r *http.Request) {
+	// Parse query parameters
+	postID := r.PathValue("id")
+	languageCode := r.PathValue("languageCode")
+	if postID == "" || languageCode == "" {
+		common.WriteMessageResponse(w, "Post ID and language code are required", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Call service to delete post translation
+	err := h.service.DeletePostTranslation(r.Context(), service.DeletePostTranslationParams{
+		User:         *user,
+		PostID:       postID,
+		LanguageCode: languageCode,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteMessageResponse(w, "Post translation deleted successfully", http.StatusOK)
+}
+
+func (h *EndpointHandler) GetPostListMissingTranslation(w http.ResponseWriter, r *http.Request) {
+	// Parse query parameters
+	arg := service.GetPostListMissingTranslationParams{}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	arg.User = *user
+
+	languageCode := r.URL.Query().Get("language-code")
+	if languageCode != "" {
+		arg.LanguageCode = &languageCode
+	}
+
+	cursor := r.URL.Query().Get("cursor")
+	if cursor != "" {
+		arg.Cursor = &cursor
+	}
+
+	cursorID := r.URL.Query().Get("cursor-id")
+	if cursorID != "" {
+		arg.CursorID = &cursorID
+	}
+
+	if (cursor != "" && cursorID == "") || (cursor == "" && cursorID != "") {
+		common.WriteMessageResponse(w, "Both cursor and cursor-id must be provided together", http.StatusBadRequest)
+		return
+	}
+
+	pageSize := r.URL.Query().Get("page-size")
+	if pageSize != "" {
+		var err error
+		arg.PageSize, err = strconv.Atoi(pageSize)
+		if err != nil {
+			common.WriteMessageResponse(w, "Invalid page-size parameter", http.StatusBadRequest)
+			return
+		}
+	} else {
+		arg.PageSize = env.PageSizeDefault
+	}
+
+	// Call service to get post list missing translation
+	postList, err := h.service.GetPostListMissingTranslation(r.Context(), arg)
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteJSONResponse(w, http.StatusOK, postList)
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"encoding/json"
+	"io"
 	"log/slog"
 	"net/http"
 	"strconv"
@@ -17,6 +18,7 @@ func (h *EndpointHandler) registerPriceRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /prices", h.GetPriceList)
 	mux.HandleFunc("GET /prices/{id}", h.GetPriceByID)
 	mux.HandleFunc("PUT /prices/{id}", h.UpdatePriceByID)
+	mux.HandleFunc("POST /prices/{id}/checkout", h.CreateCheckout)
 }
 
 type CreatePriceParams struct {
@@ -121,6 +123,12 @@ func (h *EndpointHandler) GetPriceByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	common.SetETag(w, price.UpdatedAt)
+	if common.IsNoneMatch(r, common.ETag(price.UpdatedAt)) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
 	common.WriteJSONResponse(w, http.StatusOK, price)
 }
 
@@ -132,6 +140,12 @@ func (h *EndpointHandler) UpdatePriceByID(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	versions, ok := common.GetIfMatchVersions(r)
+	if !ok {
+		common.WriteIfMatchRequiredResponse(w)
+		return
+	}
+
 	var req struct {
 		Name                   string  `json:"name"`
 		Description            string  `json:"description"`
@@ -166,6 +180,7 @@ func (h *EndpointHandler) UpdatePriceByID(w http.ResponseWriter, r *http.Request
 		RecurringInterval:      req.RecurringInterval,
 		RecurringIntervalCount: req.RecurringIntervalCount,
 		IsActive:               req.IsActive,
+		Versions:               versions,
 	})
 	if err != nil {
 		common.WriteErrorResponse(w, err)
@@ -174,3 +189,40 @@ func (h *EndpointHandler) UpdatePriceByID(w http.ResponseWriter, r *http.Request
 
 	common.WriteMessageResponse(w, "Price updated successfully", http.StatusOK)
 }
+
+func (h *EndpointHandler) CreateCheckout(w http.ResponseWriter, r *http.Request) {
+	// Parse price ID from URL path
+	priceID := r.PathValue("id")
+	if priceID == "" {
+		common.WriteMessageResponse(w, "Price ID is required", http.StatusBadRequest)
+		return
+	}
+
+	// The request body with the discount code is optional
+	var req struct {
+		DiscountCode *string `json:"discountCode"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		common.WriteMessageResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	checkout, err := h.service.CreateCheckout(r.Context(), service.CreateCheckoutParams{
+		User:         *user,
+		PriceID:      priceID,
+		DiscountCode: req.DiscountCode,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteJSONResponse(w, http.StatusCreated, checkout)
+}
@@ -109,6 +109,12 @@ func (h *EndpointHandler) GetProductByID(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	common.SetETag(w, product.UpdatedAt)
+	if common.IsNoneMatch(r, common.ETag(product.UpdatedAt)) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
 	common.WriteJSONResponse(w, http.StatusOK, product)
 }
 
@@ -120,6 +126,12 @@ func (h *EndpointHandler) UpdateProductByID(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	versions, ok := common.GetIfMatchVersions(r)
+	if !ok {
+		common.WriteIfMatchRequiredResponse(w)
+		return
+	}
+
 	var req struct {
 		Name        string `json:"name"`
 		Description string `json:"description"`
@@ -144,6 +156,7 @@ func (h *EndpointHandler) UpdateProductByID(w http.ResponseWriter, r *http.Reque
 		Name:        req.Name,
 		Description: req.Description,
 		IsActive:    req.IsActive,
+		Versions:    versions,
 	})
 	if err != nil {
 		common.WriteErrorResponse(w, err)
@@ -0,0 +1,288 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/http/common"
+	"github.com/jljl1337/issho/internal/http/middleware"
+	"github.com/jljl1337/issho/internal/service"
+)
+
+func (h *EndpointHandler) registerSeriesRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("POST /series", h.CreateSeries)
+	mux.HandleFunc("GET /series", h.GetSeriesList)
+	mux.HandleFunc("GET /series/{id}", h.GetSeriesByID)
+	mux.HandleFunc("GET /series/slug/{slug}", h.GetSeriesBySlug)
+	mux.HandleFunc("PUT /series/{id}", h.UpdateSeriesByID)
+	mux.HandleFunc("DELETE /series/{id}", h.DeleteSeriesByID)
+	mux.HandleFunc("PUT /series/{id}/posts", h.SetSeriesPosts)
+}
+
+type CreateSeriesParams struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Slug        string `json:"slug"`
+}
+
+func (h *EndpointHandler) CreateSeries(w http.ResponseWriter, r *http.Request) {
+	var req CreateSeriesParams
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteMessageResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Title == "" {
+		common.WriteMessageResponse(w, "Title is required", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	err := h.service.CreateSeries(r.Context(), service.CreateSeriesParams{
+		User:        *user,
+		Title:       req.Title,
+		Description: req.Description,
+		Slug:        req.Slug,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteMessageResponse(w, "Series created successfully", http.StatusCreated)
+}
+
+func (h *EndpointHandler) GetSeriesList(w http.ResponseWriter, r *http.Request) {
+	// Parse query parameters
+	arg := service.GetSeriesListParams{}
+
+	cursor := r.URL.Query().Get("cursor")
+	if cursor != "" {
+		arg.Cursor = &cursor
+	}
+
+	cursorID := r.URL.Query().Get("cursor-id")
+	if cursorID != "" {
+		arg.CursorID = &cursorID
+	}
+
+	if (cursor != "" && cursorID == "") || (cursor == "" && cursorID != "") {
+		common.WriteMessageResponse(w, "Both cursor and cursor-id must be provided together", http.StatusBadRequest)
+		return
+	}
+
+	pageSize := r.URL.Query().Get("page-size")
+	if pageSize != "" {
+		var err error
+		arg.PageSize, err = strconv.Atoi(pageSize)
+		if err != nil {
+			common.WriteMessageResponse(w, "Invalid page-size parameter", http.StatusBadRequest)
+			return
+		}
+	} else {
+		arg.PageSize = env.PageSizeDefault
+	}
+
+	// Call service to get series list
+	seriesList, err := h.service.GetSeriesList(r.Context(), arg)
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteJSONResponse(w, http.StatusOK, seriesList)
+}
+
+func (h *EndpointHandler) GetSeriesByID(w http.ResponseWriter, r *http.Request) {
+	// Parse series ID from URL path
+	seriesID := r.PathValue("id")
+	if seriesID == "" {
+		common.WriteMessageResponse(w, "Series ID is required", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Call service to get series by ID
+	series, err := h.service.GetSeriesByID(r.Context(), service.GetSeriesByIDParams{
+		User:           *user,
+		SeriesID:       seriesID,
+		AcceptLanguage: r.Header.Get("Accept-Language"),
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	w.Header().Set("Vary", "Accept-Language")
+	common.WriteJSONResponse(w, http.StatusOK, series)
+}
+
+func (h *EndpointHandler) GetSeriesBySlug(w http.ResponseWriter, r *http.Request) {
+	// Parse series slug from URL path
+	slug := r.PathValue("slug")
+	if slug == "" {
+		common.WriteMessageResponse(w, "Series slug is required", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Call service to get series by slug
+	series, err := h.service.GetSeriesBySlug(r.Context(), service.GetSeriesBySlugParams{
+		User:           *user,
+		Slug:           slug,
+		AcceptLanguage: r.Header.Get("Accept-Language"),
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	w.Header().Set("Vary", "Accept-Language")
+	common.WriteJSONResponse(w, http.StatusOK, series)
+}
+
+func (h *EndpointHandler) UpdateSeriesByID(w http.ResponseWriter, r *http.Request) {
+	// Parse query parameters and request body
+	seriesID := r.PathValue("id")
+	if seriesID == "" {
+		common.WriteMessageResponse(w, "Series ID is required", http.StatusBadRequest)
+		return
+	}
+
+	versions, ok := common.GetIfMatchVersions(r)
+	if !ok {
+		common.WriteIfMatchRequiredResponse(w)
+		return
+	}
+
+	var req struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Slug        string `json:"slug"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteMessageResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Title == "" {
+		common.WriteMessageResponse(w, "Title is required", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Call service to update series by ID
+	err := h.service.UpdateSeriesByID(r.Context(), service.UpdateSeriesByIDParams{
+		User:        *user,
+		SeriesID:    seriesID,
+		Title:       req.Title,
+		Description: req.Description,
+		Slug:        req.Slug,
+		Versions:    versions,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteMessageResponse(w, "Series updated successfully", http.StatusOK)
+}
+
+func (h *EndpointHandler) DeleteSeriesByID(w http.ResponseWriter, r *http.Request) {
+	// Parse query parameters
+	seriesID := r.PathValue("id")
+	if seriesID == "" {
+		common.WriteMessageResponse(w, "Series ID is required", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Call service to delete series by ID
+	err := h.service.DeleteSeriesByID(r.Context(), service.DeleteSeriesByIDParams{
+		User:     *user,
+		SeriesID: seriesID,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteMessageResponse(w, "Series deleted successfully", http.StatusOK)
+}
+
+func (h *EndpointHandler) SetSeriesPosts(w http.ResponseWriter, r *http.Request) {
+	// Parse query parameters and request body
+	seriesID := r.PathValue("id")
+	if seriesID == "" {
+		common.WriteMessageResponse(w, "Series ID is required", http.StatusBadRequest)
+		return
+	}
+
+	versions, ok := common.GetIfMatchVersions(r)
+	if !ok {
+		common.WriteIfMatchRequiredResponse(w)
+		return
+	}
+
+	var req struct {
+		PostIDs []string `json:"postIDs"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		common.WriteMessageResponse(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user := middleware.GetUserFromContext(r.Context())
+	if user == nil {
+		slog.Error("Error getting user from context")
+		common.WriteMessageResponse(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Call service to set series posts
+	err := h.service.SetSeriesPosts(r.Context(), service.SetSeriesPostsParams{
+		User:     *user,
+		SeriesID: seriesID,
+		PostIDs:  req.PostIDs,
+		Versions: versions,
+	})
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	common.WriteMessageResponse(w, "Series posts updated successfully", http.StatusOK)
+}
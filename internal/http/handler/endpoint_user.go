@@ -11,13 +11,14 @@ import (
 )
 
 type getCurrentUserResponse struct {
-	ID           string `json:"id"`
-	Username     string `json:"username"`
-	Email        string `json:"email"`
-	Role         string `json:"role"`
-	LanguageCode string `json:"languageCode"`
-	IsVerified   bool   `json:"isVerified"`
-	CreatedAt    string `json:"createdAt"`
+	ID                string `json:"id"`
+	Username          string `json:"username"`
+	Email             string `json:"email"`
+	Role              string `json:"role"`
+	LanguageCode      string `json:"languageCode"`
+	IsVerified        bool   `json:"isVerified"`
+	PaymentSyncStatus string `json:"paymentSyncStatus"`
+	CreatedAt         string `json:"createdAt"`
 }
 
 func (h *EndpointHandler) registerUserRoutes(mux *http.ServeMux) {
@@ -43,13 +44,14 @@ func (h *EndpointHandler) getCurrentUser(w http.ResponseWriter, r *http.Request)
 
 	// Respond to the client
 	response := getCurrentUserResponse{
-		ID:           user.ID,
-		Username:     user.Username,
-		Email:        user.Email,
-		Role:         user.Role,
-		LanguageCode: user.LanguageCode,
-		IsVerified:   user.IsVerified,
-		CreatedAt:    user.CreatedAt,
+		ID:                user.ID,
+		Username:          user.Username,
+		Email:             user.Email,
+		Role:              user.Role,
+		LanguageCode:      user.LanguageCode,
+		IsVerified:        user.IsVerified,
+		PaymentSyncStatus: user.PaymentSyncStatus,
+		CreatedAt:         user.CreatedAt,
 	}
 	common.WriteJSONResponse(w, http.StatusOK, response)
 }
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/feed"
+	"github.com/jljl1337/issho/internal/http/common"
+	"github.com/jljl1337/issho/internal/service"
+)
+
+// FeedHandler serves the public syndication feeds of published posts. The
+// feeds are served outside of the API so that they live at the site root.
+type FeedHandler struct {
+	service *service.EndpointService
+}
+
+func NewFeedHandler(service *service.EndpointService) *FeedHandler {
+	return &FeedHandler{
+		service: service,
+	}
+}
+
+func (h *FeedHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /feed.xml", h.serveFeed(feed.RSS, "application/rss+xml; charset=utf-8"))
+	mux.HandleFunc("GET /atom.xml", h.serveFeed(feed.Atom, "application/atom+xml; charset=utf-8"))
+	mux.HandleFunc("GET /feed.json", h.serveFeed(feed.JSON, "application/feed+json; charset=utf-8"))
+}
+
+func (h *FeedHandler) serveFeed(render func(feed.Feed) ([]byte, error), contentType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse query parameters
+		arg := service.GetFeedParams{
+			FeedURL:        env.SiteURL + r.URL.RequestURI(),
+			AcceptLanguage: r.Header.Get("Accept-Language"),
+		}
+
+		tag := r.URL.Query().Get("tag")
+		if tag != "" {
+			arg.Tag = &tag
+		}
+
+		userID := r.URL.Query().Get("user-id")
+		if userID != "" {
+			arg.UserID = &userID
+		}
+
+		// Build and render the feed
+		result, err := h.service.GetFeed(r.Context(), arg)
+		if err != nil {
+			common.WriteErrorResponse(w, err)
+			return
+		}
+
+		body, err := render(*result)
+		if err != nil {
+			common.WriteErrorResponse(w, err)
+			return
+		}
+
+		sum := sha256.Sum256(body)
+		etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+		// An empty feed has no modification time to report
+		lastModified := time.Time{}
+		if len(result.Items) > 0 {
+			lastModified = result.UpdatedAt.UTC().Truncate(time.Second)
+		}
+
+		w.Header().Set("ETag", etag)
+		if !lastModified.IsZero() {
+			w.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+		}
+		w.Header().Set("Cache-Control", "public, max-age=0, must-revalidate")
+		w.Header().Set("Vary", "Accept-Language")
+
+		if isFeedNotModified(r, etag, lastModified) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(http.StatusOK)
+		w.Write(body)
+	}
+}
+
+// isFeedNotModified checks the conditional request headers. If-None-Match
+// takes precedence over If-Modified-Since when both are present, and the
+// latter is ignored when there is no modification time.
+func isFeedNotModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if r.Header.Get("If-None-Match") != "" {
+		return common.IsNoneMatch(r, etag)
+	}
+
+	if ifModifiedSince := r.Header.Get("If-Modified-Since"); ifModifiedSince != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(ifModifiedSince)
+		if err != nil {
+			return false
+		}
+		return !lastModified.After(since)
+	}
+
+	return false
+}
@@ -1,24 +1,40 @@
 package handler
 
 import (
+	"errors"
 	"io/fs"
+	"log/slog"
 	"net/http"
 	"strings"
 
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/http/common"
+	"github.com/jljl1337/issho/internal/seo"
+	"github.com/jljl1337/issho/internal/service"
 	"github.com/jljl1337/issho/web"
 )
 
 type WebHandler struct {
-	siteFs fs.FS
+	siteFs  fs.FS
+	service *service.EndpointService
 }
 
-func NewWebHandler() *WebHandler {
+func NewWebHandler(service *service.EndpointService) *WebHandler {
 	buildFS, err := fs.Sub(web.SiteDir, "build/client")
 	if err != nil {
 		panic("Failed to create sub filesystem: " + err.Error())
 	}
 
-	return &WebHandler{siteFs: buildFS}
+	return &WebHandler{
+		siteFs:  buildFS,
+		service: service,
+	}
+}
+
+func (h *WebHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /sitemap.xml", h.ServeSitemap)
+	mux.HandleFunc("GET /robots.txt", h.ServeRobots)
+	mux.HandleFunc("/", h.ServeSite)
 }
 
 func (h *WebHandler) ServeSite(w http.ResponseWriter, r *http.Request) {
@@ -35,7 +51,75 @@ func (h *WebHandler) ServeSite(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Post pages are given their metadata, so that shared links have a
+	// preview without running the SPA
+	if postID, ok := strings.CutPrefix(r.URL.Path, "/posts/"); ok && postID != "" && !strings.Contains(postID, "/") {
+		if h.servePostPage(w, r, postID) {
+			return
+		}
+	}
+
 	// File doesn't exist, serve index.html for SPA routing
 	r.URL.Path = "/"
 	http.FileServer(http.FS(h.siteFs)).ServeHTTP(w, r)
 }
+
+// servePostPage serves index.html with the metadata of the post injected. It
+// returns false if the post is not found, to fall back to the plain index.html.
+func (h *WebHandler) servePostPage(w http.ResponseWriter, r *http.Request, postID string) bool {
+	page, err := h.service.GetPostPage(r.Context(), service.GetPostPageParams{
+		PostID:         postID,
+		AcceptLanguage: r.Header.Get("Accept-Language"),
+	})
+	if err != nil {
+		var serviceErr *service.ServiceError
+		if !errors.As(err, &serviceErr) || serviceErr.Code != service.ErrCodeNotFound {
+			slog.Error("Error getting post page: " + err.Error())
+		}
+		return false
+	}
+
+	document, err := fs.ReadFile(h.siteFs, "index.html")
+	if err != nil {
+		slog.Error("Error reading index.html: " + err.Error())
+		return false
+	}
+
+	body := seo.InjectMeta(document, *page)
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Vary", "Accept-Language")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+
+	return true
+}
+
+func (h *WebHandler) ServeSitemap(w http.ResponseWriter, r *http.Request) {
+	urls, err := h.service.GetSitemap(r.Context())
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	body, err := seo.Sitemap(urls)
+	if err != nil {
+		common.WriteErrorResponse(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+func (h *WebHandler) ServeRobots(w http.ResponseWriter, r *http.Request) {
+	body := seo.Robots(env.SiteURL + "/sitemap.xml")
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
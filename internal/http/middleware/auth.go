@@ -3,6 +3,7 @@ package middleware
 import (
 	"context"
 	"net/http"
+	"strings"
 
 	"github.com/jljl1337/issho/internal/env"
 	"github.com/jljl1337/issho/internal/http/common"
@@ -24,14 +25,29 @@ func (m *MiddlewareProvider) Auth() Middleware {
 				"/auth/pre-session": true,
 				"/auth/sign-in":     true,
 				"/auth/csrf-token":  true,
+				// The newsletter is also for email addresses without an account
+				"/newsletter/subscriptions": true,
+				"/newsletter/confirm":       true,
+				"/newsletter/unsubscribe":   true,
 			}
-			if publicRoutes[r.URL.Path] {
+			// Webhooks are authenticated by their signature instead, and the
+			// checkout page of the local payment provider stands in for a page
+			// hosted by the provider
+			if publicRoutes[r.URL.Path] || strings.HasPrefix(r.URL.Path, "/webhooks/") || strings.HasPrefix(r.URL.Path, "/payment/local/") {
 				next.ServeHTTP(w, r)
 				return
 			}
 
+			// Routes that can be read without a session, but still resolve the
+			// user if a valid session is present
+			isOptionalAuthRoute := r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/attachments/")
+
 			// Get session token from cookie
 			cookie, err := r.Cookie(env.SessionCookieName)
+			if err != nil && isOptionalAuthRoute {
+				next.ServeHTTP(w, r)
+				return
+			}
 			if err != nil {
 				// err is not nil only if the cookie is not present
 				common.WriteMessageResponse(w, "Unauthorized", http.StatusUnauthorized)
@@ -48,6 +64,10 @@ func (m *MiddlewareProvider) Auth() Middleware {
 
 			// Validate session token (and CSRF token)
 			user, err := m.service.GetSessionUserAndRefreshSession(r.Context(), cookie.Value, CSRFToken)
+			if err != nil && isOptionalAuthRoute {
+				next.ServeHTTP(w, r)
+				return
+			}
 			if err != nil {
 				common.WriteErrorResponse(w, err)
 				return
@@ -0,0 +1,124 @@
+package media
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+)
+
+// pixelCountMax caps the size of the images that are decoded for thumbnails,
+// so that a small but highly compressed upload cannot exhaust the memory.
+const pixelCountMax = 50_000_000
+
+// ProbeImage returns the format and dimensions of the image without decoding
+// the whole image. It returns false if the data is not a supported image.
+func ProbeImage(data []byte) (format string, width, height int, ok bool) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", 0, 0, false
+	}
+
+	return format, config.Width, config.Height, true
+}
+
+// Thumbnail scales the image down to fit in a square of sizeMax pixels,
+// keeping the aspect ratio, and returns the encoded thumbnail with its
+// content type. JPEG images stay JPEG, the others are encoded as PNG to keep
+// the transparency. It returns nil if the image already fits.
+func Thumbnail(data []byte, sizeMax int) ([]byte, string, error) {
+	format, width, height, ok := ProbeImage(data)
+	if !ok {
+		return nil, "", fmt.Errorf("unsupported image")
+	}
+
+	if width <= sizeMax && height <= sizeMax {
+		return nil, "", nil
+	}
+
+	if width*height > pixelCountMax {
+		return nil, "", fmt.Errorf("image is too large to create a thumbnail: %dx%d", width, height)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	dstWidth, dstHeight := sizeMax, sizeMax
+	if width > height {
+		dstHeight = max(1, height*sizeMax/width)
+	} else {
+		dstWidth = max(1, width*sizeMax/height)
+	}
+
+	dst := resize(src, dstWidth, dstHeight)
+
+	var buf bytes.Buffer
+	if format == "jpeg" {
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85})
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to encode thumbnail: %w", err)
+		}
+		return buf.Bytes(), "image/jpeg", nil
+	}
+
+	err = png.Encode(&buf, dst)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+	return buf.Bytes(), "image/png", nil
+}
+
+// resize scales the image down with a box filter, i.e. each destination pixel
+// is the average of the source pixels it covers.
+func resize(src image.Image, dstWidth, dstHeight int) *image.NRGBA {
+	bounds := src.Bounds()
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+
+	// Convert to NRGBA first to access the pixels directly
+	srcNRGBA := image.NewNRGBA(image.Rect(0, 0, srcWidth, srcHeight))
+	draw.Draw(srcNRGBA, srcNRGBA.Bounds(), src, bounds.Min, draw.Src)
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+
+	for dy := range dstHeight {
+		y0 := dy * srcHeight / dstHeight
+		y1 := max(y0+1, (dy+1)*srcHeight/dstHeight)
+
+		for dx := range dstWidth {
+			x0 := dx * srcWidth / dstWidth
+			x1 := max(x0+1, (dx+1)*srcWidth/dstWidth)
+
+			// Weight the colours by alpha so that transparent pixels do not
+			// darken the edges
+			var r, g, b, a, count uint64
+			for sy := y0; sy < y1; sy++ {
+				offset := srcNRGBA.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					pixel := srcNRGBA.Pix[offset : offset+4 : offset+4]
+					alpha := uint64(pixel[3])
+					r += uint64(pixel[0]) * alpha
+					g += uint64(pixel[1]) * alpha
+					b += uint64(pixel[2]) * alpha
+					a += alpha
+					count++
+					offset += 4
+				}
+			}
+
+			offset := dst.PixOffset(dx, dy)
+			if a > 0 {
+				dst.Pix[offset] = uint8(r / a)
+				dst.Pix[offset+1] = uint8(g / a)
+				dst.Pix[offset+2] = uint8(b / a)
+			}
+			dst.Pix[offset+3] = uint8(a / count)
+		}
+	}
+
+	return dst
+}
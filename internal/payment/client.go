@@ -0,0 +1,174 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jljl1337/issho/internal/generator"
+)
+
+// ErrProviderUnavailable is returned when the payment provider cannot be
+// reached, or keeps failing or limiting the rate of the requests.
+var ErrProviderUnavailable = errors.New("payment provider is unavailable")
+
+// ProviderError is an error response of the payment provider.
+type ProviderError struct {
+	StatusCode int
+	Type       string
+	Code       string
+	Message    string
+}
+
+func (e *ProviderError) Error() string {
+	if e.Type == "" {
+		return fmt.Sprintf("received non-2xx response: %d, with body: %s", e.StatusCode, e.Message)
+	}
+
+	return fmt.Sprintf("received non-2xx response: %d, with %s error: %s", e.StatusCode, e.Type, e.Message)
+}
+
+// Unwrap returns ErrProviderUnavailable for a response that was worth retrying.
+func (e *ProviderError) Unwrap() error {
+	if isRetryableStatus(e.StatusCode) {
+		return ErrProviderUnavailable
+	}
+
+	return nil
+}
+
+// HTTPClient sends the requests of the payment providers, retrying the ones
+// failed with a network error, a 429 or a 5xx response with exponential
+// backoff. A POST request is sent with an idempotency key, from the context or
+// generated for the request, so that it is never made twice by a retry.
+type HTTPClient struct {
+	client     *http.Client
+	timeout    time.Duration
+	retryMax   int
+	retryDelay time.Duration
+}
+
+func NewHTTPClient(timeout time.Duration, retryMax int, retryDelay time.Duration) *HTTPClient {
+	return &HTTPClient{
+		client:     &http.Client{Timeout: timeout},
+		timeout:    timeout,
+		retryMax:   retryMax,
+		retryDelay: retryDelay,
+	}
+}
+
+// Do sends the request and decodes the JSON body of a 2xx response into the
+// response, if not nil. Any other response is turned into an error by
+// parseError, with its status code and body.
+func (c *HTTPClient) Do(req *http.Request, parseError func(statusCode int, body []byte) *ProviderError, response any) error {
+	ctx := req.Context()
+
+	if req.Method == http.MethodPost {
+		key := idempotencyKeyFromContext(ctx)
+		if key == "" {
+			key = generator.NewULID()
+		}
+		req.Header.Set("Idempotency-Key", key)
+	}
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return fmt.Errorf("failed to reset request body: %w", err)
+			}
+			req.Body = body
+		}
+
+		resp, err := c.client.Do(req)
+		if err != nil {
+			if ctx.Err() != nil || attempt >= c.retryMax {
+				return fmt.Errorf("%w: failed to send request: %v", ErrProviderUnavailable, err)
+			}
+
+			if err := c.wait(ctx, c.retryDelay<<attempt); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			defer resp.Body.Close()
+
+			if response == nil {
+				return nil
+			}
+
+			if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+				return fmt.Errorf("failed to decode response body: %w", err)
+			}
+
+			return nil
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read error response body: %w", err)
+		}
+
+		providerErr := parseError(resp.StatusCode, body)
+
+		if !isRetryableStatus(resp.StatusCode) || attempt >= c.retryMax {
+			return providerErr
+		}
+
+		delay := c.retryDelay << attempt
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			delay = retryAfter
+		}
+
+		// Leave a longer wait to the caller, such as the queue of the outbox
+		if delay > c.timeout {
+			return providerErr
+		}
+
+		if err := c.wait(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *HTTPClient) wait(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func isRetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
+
+// parseRetryAfter parses the Retry-After header, which is either a number of
+// seconds or an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		return max(time.Until(t), 0), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,148 @@
+package payment
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/jljl1337/issho/internal/generator"
+	"github.com/jljl1337/issho/internal/repository"
+)
+
+const (
+	localObjectTypeCustomer     = "customer"
+	localObjectTypeProduct      = "product"
+	localObjectTypePrice        = "price"
+	localObjectTypeDiscount     = "discount"
+	localObjectTypeCheckout     = "checkout"
+	localObjectTypeSubscription = "subscription"
+	localObjectTypeOrder        = "order"
+)
+
+// ErrLocalObjectNotFound is returned when an object of the local provider does
+// not exist.
+var ErrLocalObjectNotFound = errors.New("local payment object not found")
+
+// LocalProvider is a payment provider for development, which keeps its objects
+// in the database and completes checkouts on a page served by this
+// application without any payment. It emits the same webhook events as Polar,
+// signed with a secret generated on start.
+type LocalProvider struct {
+	db            *sqlx.DB
+	webhookSecret string
+}
+
+func NewLocalProvider(dbInstance *sqlx.DB) *LocalProvider {
+	return &LocalProvider{
+		db:            dbInstance,
+		webhookSecret: rand.Text(),
+	}
+}
+
+// createObject stores the data of a new object as JSON, and returns its ID
+// with the prefix.
+func (p *LocalProvider) createObject(ctx context.Context, objectType, prefix string, customerID *string, data func(id string) any) (string, error) {
+	id := prefix + generator.NewULID()
+
+	encoded, err := json.Marshal(data(id))
+	if err != nil {
+		return "", fmt.Errorf("failed to encode %s: %w", objectType, err)
+	}
+
+	now := generator.NowISO8601()
+
+	err = repository.New(p.db).CreateLocalPaymentObject(ctx, repository.LocalPaymentObject{
+		ID:         id,
+		Type:       objectType,
+		CustomerID: customerID,
+		Data:       string(encoded),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", objectType, err)
+	}
+
+	return id, nil
+}
+
+func (p *LocalProvider) getObject(ctx context.Context, objectType, id string, data any) error {
+	objectList, err := repository.New(p.db).GetLocalPaymentObjectByTypeAndID(ctx, repository.GetLocalPaymentObjectByTypeAndIDParams{
+		Type: objectType,
+		ID:   id,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get %s: %w", objectType, err)
+	}
+
+	if len(objectList) == 0 {
+		return fmt.Errorf("%w: %s %s", ErrLocalObjectNotFound, objectType, id)
+	}
+
+	if err := json.Unmarshal([]byte(objectList[0].Data), data); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", objectType, err)
+	}
+
+	return nil
+}
+
+func (p *LocalProvider) updateObject(ctx context.Context, objectType, id string, data any) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", objectType, err)
+	}
+
+	err = repository.New(p.db).UpdateLocalPaymentObjectData(ctx, repository.UpdateLocalPaymentObjectDataParams{
+		Type:      objectType,
+		ID:        id,
+		Data:      string(encoded),
+		UpdatedAt: generator.NowISO8601(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update %s: %w", objectType, err)
+	}
+
+	return nil
+}
+
+// getObjectListByCustomerID decodes the objects of the type of the customer,
+// oldest first.
+func getObjectListByCustomerID[Data any](ctx context.Context, p *LocalProvider, objectType, customerID string) ([]Data, error) {
+	objectList, err := repository.New(p.db).GetLocalPaymentObjectListByTypeAndCustomerID(ctx, repository.GetLocalPaymentObjectListByTypeAndCustomerIDParams{
+		Type:       objectType,
+		CustomerID: customerID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s list: %w", objectType, err)
+	}
+
+	return decodeObjectList[Data](objectType, objectList)
+}
+
+// getObjectList decodes every object of the type, oldest first.
+func getObjectList[Data any](ctx context.Context, p *LocalProvider, objectType string) ([]Data, error) {
+	objectList, err := repository.New(p.db).GetLocalPaymentObjectListByType(ctx, objectType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s list: %w", objectType, err)
+	}
+
+	return decodeObjectList[Data](objectType, objectList)
+}
+
+func decodeObjectList[Data any](objectType string, objectList []repository.LocalPaymentObject) ([]Data, error) {
+	items := []Data{}
+	for _, object := range objectList {
+		var item Data
+		if err := json.Unmarshal([]byte(object.Data), &item); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", objectType, err)
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,257 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/format"
+	"github.com/jljl1337/issho/internal/generator"
+)
+
+const (
+	LocalCheckoutStatusOpen      = "open"
+	LocalCheckoutStatusCompleted = "completed"
+	LocalCheckoutStatusCanceled  = "canceled"
+)
+
+// ErrLocalCheckoutNotOpen is returned when a checkout of the local provider is
+// already completed, canceled or expired.
+var ErrLocalCheckoutNotOpen = errors.New("local checkout is not open")
+
+// LocalCheckout is a checkout of the local provider, with a copy of the price
+// and the discount to show on the checkout page.
+type LocalCheckout struct {
+	ID         string         `json:"id"`
+	CustomerID string         `json:"customer_id"`
+	Price      LocalPrice     `json:"price"`
+	Discount   *LocalDiscount `json:"discount"`
+	SuccessURL string         `json:"success_url"`
+	CancelURL  string         `json:"cancel_url"`
+	Status     string         `json:"status"`
+	ExpiresAt  string         `json:"expires_at"`
+}
+
+// isOpen reports whether the checkout can still be completed or canceled.
+func (c *LocalCheckout) isOpen() bool {
+	return c.Status == LocalCheckoutStatusOpen && c.ExpiresAt > generator.NowISO8601()
+}
+
+// TotalAmount returns the amount to pay for the checkout, after the discount.
+func (c *LocalCheckout) TotalAmount() int {
+	if c.Discount == nil {
+		return c.Price.PriceAmount
+	}
+
+	return c.Discount.apply(c.Price.PriceAmount)
+}
+
+// LocalWebhook is a signed webhook emitted by the local provider.
+type LocalWebhook struct {
+	Header http.Header
+	Body   []byte
+}
+
+// CreateCheckout creates a checkout, of which the page is served by this
+// application, expiring after the checkout lifetime.
+func (p *LocalProvider) CreateCheckout(ctx context.Context, customerExternalID, priceExternalID string, discountExternalID *string, successURL, cancelURL string) (*Checkout, error) {
+	price := LocalPrice{}
+	err := p.getObject(ctx, localObjectTypePrice, priceExternalID, &price)
+	if err != nil {
+		return nil, fmt.Errorf("error getting local price: %w", err)
+	}
+
+	var discount *LocalDiscount
+	if discountExternalID != nil {
+		discount = &LocalDiscount{}
+		err = p.getObject(ctx, localObjectTypeDiscount, *discountExternalID, discount)
+		if err != nil {
+			return nil, fmt.Errorf("error getting local discount: %w", err)
+		}
+	}
+
+	expiresAt := generator.MinutesFromNowISO8601(env.CheckoutLifetimeMin)
+
+	id, err := p.createObject(ctx, localObjectTypeCheckout, "checkout_", &customerExternalID, func(id string) any {
+		return LocalCheckout{
+			ID:         id,
+			CustomerID: customerExternalID,
+			Price:      price,
+			Discount:   discount,
+			SuccessURL: successURL,
+			CancelURL:  cancelURL,
+			Status:     LocalCheckoutStatusOpen,
+			ExpiresAt:  expiresAt,
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating local checkout: %w", err)
+	}
+
+	return &Checkout{
+		ExternalID: id,
+		URL:        env.SiteURL + "/api/payment/local/checkouts/" + id,
+		ExpiresAt:  expiresAt,
+	}, nil
+}
+
+func (p *LocalProvider) GetCheckout(ctx context.Context, id string) (*LocalCheckout, error) {
+	checkout := &LocalCheckout{}
+	err := p.getObject(ctx, localObjectTypeCheckout, id, checkout)
+	if err != nil {
+		return nil, err
+	}
+
+	return checkout, nil
+}
+
+// CompleteCheckout completes the open checkout as if it were paid. Like Polar,
+// it creates an order, and a subscription for recurring prices, and returns
+// the webhooks of their events to be received.
+func (p *LocalProvider) CompleteCheckout(ctx context.Context, id string) (*LocalCheckout, []LocalWebhook, error) {
+	checkout, err := p.GetCheckout(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !checkout.isOpen() {
+		return nil, nil, ErrLocalCheckoutNotOpen
+	}
+
+	checkout.Status = LocalCheckoutStatusCompleted
+	err = p.updateObject(ctx, localObjectTypeCheckout, id, checkout)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	nowISO8601 := format.TimeToISO8601(now)
+	webhooks := []LocalWebhook{}
+
+	var discountID *string
+	if checkout.Discount != nil {
+		discountID = &checkout.Discount.ID
+	}
+
+	var subscriptionID *string
+	if checkout.Price.IsRecurring {
+		periodEnd := addRecurringInterval(now, *checkout.Price.RecurringInterval, *checkout.Price.RecurringIntervalCount)
+		periodEndISO8601 := format.TimeToISO8601(periodEnd)
+
+		subscription := PolarSubscriptionResponse{}
+		createdID, err := p.createObject(ctx, localObjectTypeSubscription, "sub_", &checkout.CustomerID, func(id string) any {
+			subscription = PolarSubscriptionResponse{
+				ID:                 id,
+				CustomerID:         checkout.CustomerID,
+				ProductID:          checkout.Price.ID,
+				DiscountID:         discountID,
+				Status:             env.SubscriptionStatusActive,
+				StartedAt:          &nowISO8601,
+				CurrentPeriodStart: nowISO8601,
+				CurrentPeriodEnd:   &periodEndISO8601,
+				CancelAtPeriodEnd:  false,
+				CanceledAt:         nil,
+				EndedAt:            nil,
+			}
+			return subscription
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+
+		subscriptionID = &createdID
+
+		for _, eventType := range []string{"subscription.created", "subscription.active"} {
+			webhook, err := p.newWebhook(eventType, now, subscription)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			webhooks = append(webhooks, *webhook)
+		}
+	}
+
+	order := PolarOrderResponse{}
+	_, err = p.createObject(ctx, localObjectTypeOrder, "order_", &checkout.CustomerID, func(id string) any {
+		order = PolarOrderResponse{
+			ID:             id,
+			CustomerID:     checkout.CustomerID,
+			ProductID:      checkout.Price.ID,
+			DiscountID:     discountID,
+			SubscriptionID: subscriptionID,
+			Status:         env.OrderStatusPaid,
+			TotalAmount:    checkout.TotalAmount(),
+			Currency:       checkout.Price.PriceCurrency,
+			CreatedAt:      nowISO8601,
+		}
+		return order
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, eventType := range []string{"order.created", "order.paid"} {
+		webhook, err := p.newWebhook(eventType, now, order)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		webhooks = append(webhooks, *webhook)
+	}
+
+	return checkout, webhooks, nil
+}
+
+// CancelCheckout cancels the open checkout.
+func (p *LocalProvider) CancelCheckout(ctx context.Context, id string) (*LocalCheckout, error) {
+	checkout, err := p.GetCheckout(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !checkout.isOpen() {
+		return nil, ErrLocalCheckoutNotOpen
+	}
+
+	checkout.Status = LocalCheckoutStatusCanceled
+	err = p.updateObject(ctx, localObjectTypeCheckout, id, checkout)
+	if err != nil {
+		return nil, err
+	}
+
+	return checkout, nil
+}
+
+// newWebhook signs an event in the format of Polar.
+func (p *LocalProvider) newWebhook(eventType string, timestamp time.Time, data any) (*LocalWebhook, error) {
+	body, err := json.Marshal(map[string]any{
+		"type":      eventType,
+		"timestamp": format.TimeToISO8601(timestamp),
+		"data":      data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode webhook: %w", err)
+	}
+
+	return &LocalWebhook{
+		Header: signStandardWebhook(p.webhookSecret, "msg_"+generator.NewULID(), timestamp, body),
+		Body:   body,
+	}, nil
+}
+
+func addRecurringInterval(t time.Time, interval string, count int) time.Time {
+	switch interval {
+	case "day":
+		return t.AddDate(0, 0, count)
+	case "week":
+		return t.AddDate(0, 0, 7*count)
+	case "month":
+		return t.AddDate(0, count, 0)
+	default:
+		return t.AddDate(count, 0, 0)
+	}
+}
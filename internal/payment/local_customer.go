@@ -0,0 +1,100 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jljl1337/issho/internal/repository"
+)
+
+type LocalCustomer struct {
+	ID           string `json:"id"`
+	UserID       string `json:"user_id"`
+	Name         string `json:"name"`
+	Email        string `json:"email"`
+	LanguageCode string `json:"language_code"`
+}
+
+// CreateCustomer returns the customer already created for the user if any,
+// like Polar does with the external ID.
+func (p *LocalProvider) CreateCustomer(ctx context.Context, params CreateCustomerParams) (string, error) {
+	customers, err := getObjectList[LocalCustomer](ctx, p, localObjectTypeCustomer)
+	if err != nil {
+		return "", fmt.Errorf("error getting local customers: %w", err)
+	}
+
+	for _, customer := range customers {
+		if params.UserID != "" && customer.UserID == params.UserID {
+			return customer.ID, nil
+		}
+	}
+
+	id, err := p.createObject(ctx, localObjectTypeCustomer, "cus_", nil, func(id string) any {
+		return LocalCustomer{
+			ID:           id,
+			UserID:       params.UserID,
+			Name:         params.Name,
+			Email:        params.Email,
+			LanguageCode: params.LanguageCode,
+		}
+	})
+	if err != nil {
+		return "", fmt.Errorf("error creating local customer: %w", err)
+	}
+
+	return id, nil
+}
+
+func (p *LocalProvider) UpdateCustomer(ctx context.Context, params UpdateCustomerParams) error {
+	customer := LocalCustomer{}
+	err := p.getObject(ctx, localObjectTypeCustomer, params.ExternalID, &customer)
+	if err != nil {
+		return fmt.Errorf("error getting local customer: %w", err)
+	}
+
+	customer.Name = params.Name
+	customer.Email = params.Email
+	customer.LanguageCode = params.LanguageCode
+
+	err = p.updateObject(ctx, localObjectTypeCustomer, params.ExternalID, customer)
+	if err != nil {
+		return fmt.Errorf("error updating local customer: %w", err)
+	}
+
+	return nil
+}
+
+func (p *LocalProvider) DeleteCustomer(ctx context.Context, externalID string) error {
+	err := repository.New(p.db).DeleteLocalPaymentObject(ctx, repository.DeleteLocalPaymentObjectParams{
+		Type: localObjectTypeCustomer,
+		ID:   externalID,
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting local customer: %w", err)
+	}
+
+	return nil
+}
+
+func (p *LocalProvider) GetCustomerList(ctx context.Context) ([]Customer, error) {
+	items, err := getObjectList[LocalCustomer](ctx, p, localObjectTypeCustomer)
+	if err != nil {
+		return nil, fmt.Errorf("error getting local customers: %w", err)
+	}
+
+	customers := []Customer{}
+	for _, item := range items {
+		customer := Customer{
+			ExternalID: item.ID,
+			Email:      item.Email,
+		}
+
+		if item.UserID != "" {
+			customer.UserID = &item.UserID
+		}
+
+		customers = append(customers, customer)
+	}
+
+	return customers, nil
+}
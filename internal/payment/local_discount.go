@@ -0,0 +1,102 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/repository"
+)
+
+type LocalDiscount struct {
+	ID               string  `json:"id"`
+	Code             string  `json:"code"`
+	Name             string  `json:"name"`
+	Type             string  `json:"type"`
+	Amount           int     `json:"amount"`
+	Currency         *string `json:"currency"`
+	Duration         string  `json:"duration"`
+	DurationInMonths *int    `json:"duration_in_months"`
+	RedemptionMax    *int    `json:"redemption_max"`
+	ExpiresAt        *string `json:"expires_at"`
+}
+
+func (p *LocalProvider) CreateDiscount(ctx context.Context, params CreateDiscountParams) (string, error) {
+	id, err := p.createObject(ctx, localObjectTypeDiscount, "discount_", nil, func(id string) any {
+		return LocalDiscount{
+			ID:               id,
+			Code:             params.Code,
+			Name:             params.Name,
+			Type:             params.Type,
+			Amount:           params.Amount,
+			Currency:         params.Currency,
+			Duration:         params.Duration,
+			DurationInMonths: params.DurationInMonths,
+			RedemptionMax:    params.RedemptionMax,
+			ExpiresAt:        params.ExpiresAt,
+		}
+	})
+	if err != nil {
+		return "", fmt.Errorf("error creating local discount: %w", err)
+	}
+
+	return id, nil
+}
+
+func (p *LocalProvider) UpdateDiscount(ctx context.Context, params UpdateDiscountParams) error {
+	discount := LocalDiscount{}
+	err := p.getObject(ctx, localObjectTypeDiscount, params.ExternalID, &discount)
+	if err != nil {
+		return fmt.Errorf("error getting local discount: %w", err)
+	}
+
+	discount.Name = params.Name
+	discount.RedemptionMax = params.RedemptionMax
+	discount.ExpiresAt = params.ExpiresAt
+
+	err = p.updateObject(ctx, localObjectTypeDiscount, params.ExternalID, discount)
+	if err != nil {
+		return fmt.Errorf("error updating local discount: %w", err)
+	}
+
+	return nil
+}
+
+func (p *LocalProvider) DeleteDiscount(ctx context.Context, externalID string) error {
+	err := repository.New(p.db).DeleteLocalPaymentObject(ctx, repository.DeleteLocalPaymentObjectParams{
+		Type: localObjectTypeDiscount,
+		ID:   externalID,
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting local discount: %w", err)
+	}
+
+	return nil
+}
+
+func (p *LocalProvider) GetDiscountList(ctx context.Context) ([]CatalogDiscount, error) {
+	items, err := getObjectList[LocalDiscount](ctx, p, localObjectTypeDiscount)
+	if err != nil {
+		return nil, fmt.Errorf("error getting local discounts: %w", err)
+	}
+
+	discounts := []CatalogDiscount{}
+	for _, item := range items {
+		discounts = append(discounts, CatalogDiscount{
+			ExternalID: item.ID,
+			Code:       &item.Code,
+			Name:       item.Name,
+		})
+	}
+
+	return discounts, nil
+}
+
+// apply returns the amount after the discount, which is never negative.
+func (d LocalDiscount) apply(amount int) int {
+	if d.Type == env.DiscountTypePercentage {
+		return amount - amount*d.Amount/100
+	}
+
+	return max(amount-d.Amount, 0)
+}
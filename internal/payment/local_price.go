@@ -0,0 +1,121 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jljl1337/issho/internal/repository"
+)
+
+type LocalPrice struct {
+	ID                     string  `json:"id"`
+	ProductID              *string `json:"product_id"`
+	Name                   string  `json:"name"`
+	Description            string  `json:"description"`
+	PriceAmount            int     `json:"price_amount"`
+	PriceCurrency          string  `json:"price_currency"`
+	IsRecurring            bool    `json:"is_recurring"`
+	RecurringInterval      *string `json:"recurring_interval"`
+	RecurringIntervalCount *int    `json:"recurring_interval_count"`
+	IsActive               bool    `json:"is_active"`
+}
+
+func (p *LocalProvider) CreatePrice(ctx context.Context, params CreatePriceParams) (*repository.Price, error) {
+	price := LocalPrice{}
+	_, err := p.createObject(ctx, localObjectTypePrice, "price_", nil, func(id string) any {
+		price = LocalPrice{
+			ID:                     id,
+			ProductID:              params.ProductExternalID,
+			Name:                   params.Name,
+			Description:            params.Description,
+			PriceAmount:            params.PriceAmount,
+			PriceCurrency:          params.PriceCurrency,
+			IsRecurring:            params.IsRecurring,
+			RecurringInterval:      params.RecurringInterval,
+			RecurringIntervalCount: params.RecurringIntervalCount,
+			IsActive:               true,
+		}
+		return price
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating local price: %w", err)
+	}
+
+	return toLocalPrice(price), nil
+}
+
+func (p *LocalProvider) UpdatePrice(ctx context.Context, params UpdatePriceParams) (*repository.Price, error) {
+	price := LocalPrice{}
+	err := p.getObject(ctx, localObjectTypePrice, params.ExternalID, &price)
+	if err != nil {
+		return nil, fmt.Errorf("error getting local price: %w", err)
+	}
+
+	price.Name = params.Name
+	price.Description = params.Description
+	price.PriceAmount = params.PriceAmount
+	price.PriceCurrency = params.PriceCurrency
+	price.IsRecurring = params.IsRecurring
+	price.RecurringInterval = params.RecurringInterval
+	price.RecurringIntervalCount = params.RecurringIntervalCount
+	price.IsActive = params.IsActive
+
+	err = p.updateObject(ctx, localObjectTypePrice, params.ExternalID, price)
+	if err != nil {
+		return nil, fmt.Errorf("error updating local price: %w", err)
+	}
+
+	return toLocalPrice(price), nil
+}
+
+func (p *LocalProvider) CanUpdatePriceAmount() bool {
+	return true
+}
+
+func (p *LocalProvider) AttachPrice(ctx context.Context, productExternalID, priceExternalID string) error {
+	price := LocalPrice{}
+	err := p.getObject(ctx, localObjectTypePrice, priceExternalID, &price)
+	if err != nil {
+		return fmt.Errorf("error getting local price: %w", err)
+	}
+
+	price.ProductID = &productExternalID
+
+	err = p.updateObject(ctx, localObjectTypePrice, priceExternalID, price)
+	if err != nil {
+		return fmt.Errorf("error updating local price: %w", err)
+	}
+
+	return nil
+}
+
+func (p *LocalProvider) GetPriceList(ctx context.Context) ([]CatalogPrice, error) {
+	items, err := getObjectList[LocalPrice](ctx, p, localObjectTypePrice)
+	if err != nil {
+		return nil, fmt.Errorf("error getting local prices: %w", err)
+	}
+
+	prices := []CatalogPrice{}
+	for _, item := range items {
+		prices = append(prices, CatalogPrice{
+			Price:             *toLocalPrice(item),
+			ProductExternalID: item.ProductID,
+		})
+	}
+
+	return prices, nil
+}
+
+func toLocalPrice(price LocalPrice) *repository.Price {
+	return &repository.Price{
+		ExternalID:             price.ID,
+		Name:                   price.Name,
+		Description:            price.Description,
+		PriceAmount:            price.PriceAmount,
+		PriceCurrency:          price.PriceCurrency,
+		IsRecurring:            price.IsRecurring,
+		RecurringInterval:      price.RecurringInterval,
+		RecurringIntervalCount: price.RecurringIntervalCount,
+		IsActive:               price.IsActive,
+	}
+}
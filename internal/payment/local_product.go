@@ -0,0 +1,79 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jljl1337/issho/internal/repository"
+)
+
+type LocalProduct struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	IsActive    bool   `json:"is_active"`
+}
+
+func (p *LocalProvider) CreateProduct(ctx context.Context, params CreateProductParams) (*repository.Product, error) {
+	product := LocalProduct{}
+	_, err := p.createObject(ctx, localObjectTypeProduct, "prod_", nil, func(id string) any {
+		product = LocalProduct{
+			ID:          id,
+			Name:        params.Name,
+			Description: params.Description,
+			IsActive:    true,
+		}
+		return product
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating local product: %w", err)
+	}
+
+	return toLocalProduct(product), nil
+}
+
+func (p *LocalProvider) UpdateProduct(ctx context.Context, params UpdateProductParams) (*repository.Product, error) {
+	if params.ExternalID == "" {
+		return nil, fmt.Errorf("product has no local product")
+	}
+
+	product := LocalProduct{}
+	err := p.getObject(ctx, localObjectTypeProduct, params.ExternalID, &product)
+	if err != nil {
+		return nil, fmt.Errorf("error getting local product: %w", err)
+	}
+
+	product.Name = params.Name
+	product.Description = params.Description
+	product.IsActive = params.IsActive
+
+	err = p.updateObject(ctx, localObjectTypeProduct, params.ExternalID, product)
+	if err != nil {
+		return nil, fmt.Errorf("error updating local product: %w", err)
+	}
+
+	return toLocalProduct(product), nil
+}
+
+func (p *LocalProvider) GetProductList(ctx context.Context) ([]repository.Product, error) {
+	items, err := getObjectList[LocalProduct](ctx, p, localObjectTypeProduct)
+	if err != nil {
+		return nil, fmt.Errorf("error getting local products: %w", err)
+	}
+
+	products := []repository.Product{}
+	for _, item := range items {
+		products = append(products, *toLocalProduct(item))
+	}
+
+	return products, nil
+}
+
+func toLocalProduct(product LocalProduct) *repository.Product {
+	return &repository.Product{
+		ExternalID:  &product.ID,
+		Name:        product.Name,
+		Description: product.Description,
+		IsActive:    product.IsActive,
+	}
+}
@@ -0,0 +1,47 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+)
+
+// The subscriptions and orders of the local provider are stored as Polar
+// objects, as they are sent in the same webhook events as Polar.
+
+func (p *LocalProvider) GetSubscriptionList(ctx context.Context, customerExternalID string) ([]Subscription, error) {
+	items, err := getObjectListByCustomerID[PolarSubscriptionResponse](ctx, p, localObjectTypeSubscription, customerExternalID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting local subscriptions: %w", err)
+	}
+
+	subscriptions := []Subscription{}
+	for _, item := range items {
+		subscription, err := toSubscription(item)
+		if err != nil {
+			return nil, fmt.Errorf("error converting local subscription: %w", err)
+		}
+
+		subscriptions = append(subscriptions, *subscription)
+	}
+
+	return subscriptions, nil
+}
+
+func (p *LocalProvider) GetOrderList(ctx context.Context, customerExternalID string) ([]Order, error) {
+	items, err := getObjectListByCustomerID[PolarOrderResponse](ctx, p, localObjectTypeOrder, customerExternalID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting local orders: %w", err)
+	}
+
+	orders := []Order{}
+	for _, item := range items {
+		order, err := toOrder(item)
+		if err != nil {
+			return nil, fmt.Errorf("error converting local order: %w", err)
+		}
+
+		orders = append(orders, *order)
+	}
+
+	return orders, nil
+}
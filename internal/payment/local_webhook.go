@@ -0,0 +1,17 @@
+package payment
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/jljl1337/issho/internal/env"
+)
+
+func (p *LocalProvider) VerifyWebhook(header http.Header, body []byte) (*WebhookEvent, error) {
+	return verifyStandardWebhook(p.webhookSecret, header, body, time.Duration(env.PaymentWebhookToleranceSec)*time.Second)
+}
+
+func (p *LocalProvider) ParseWebhookEvent(ctx context.Context, eventType string, payload []byte) (*WebhookData, error) {
+	return parsePolarWebhookEvent(eventType, payload)
+}
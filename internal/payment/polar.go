@@ -2,26 +2,36 @@ package payment
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/jljl1337/issho/internal/format"
 )
 
 type PolarProvider struct {
-	baseURL     string
-	accessToken string
+	httpClient    *HTTPClient
+	baseURL       string
+	accessToken   string
+	webhookSecret string
 }
 
-func NewPolarProvider(accessToken string, isSandbox bool) *PolarProvider {
+func NewPolarProvider(httpClient *HTTPClient, accessToken, webhookSecret string, isSandbox bool) *PolarProvider {
 	baseURL := "https://api.polar.sh/v1"
 	if isSandbox {
 		baseURL = "https://sandbox-api.polar.sh/v1"
 	}
 
 	return &PolarProvider{
-		baseURL:     baseURL,
-		accessToken: accessToken,
+		httpClient:    httpClient,
+		baseURL:       baseURL,
+		accessToken:   accessToken,
+		webhookSecret: webhookSecret,
 	}
 }
 
@@ -33,51 +43,120 @@ var mapAllowedMethods = map[string]bool{
 	http.MethodDelete: true,
 }
 
-func (p *PolarProvider) sendRequest(method, endpoint string, body any, response any) error {
+// PolarErrorResponse is the body of the error responses of Polar, of which
+// the detail is a message or a list of validation errors.
+type PolarErrorResponse struct {
+	Error  string          `json:"error"`
+	Detail json.RawMessage `json:"detail"`
+}
+
+// sendRequest sends a request to Polar, which takes a JSON body except for GET
+// and DELETE requests.
+func (p *PolarProvider) sendRequest(ctx context.Context, method, endpoint string, body any, response any) error {
 	if !mapAllowedMethods[method] {
 		return fmt.Errorf("invalid HTTP method: %s", method)
 	}
 
-	client := &http.Client{}
-
-	reqBody, err := json.Marshal(body)
-	if err != nil {
-		return fmt.Errorf("failed to marshal request body: %w", err)
+	var reqBody io.Reader
+	if method != http.MethodGet && method != http.MethodDelete {
+		bodyBytes, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		reqBody = bytes.NewReader(bodyBytes)
 	}
 
-	req, err := http.NewRequest(method, p.baseURL+endpoint, bytes.NewBuffer(reqBody))
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL+endpoint, reqBody)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Authorization", "Bearer "+p.accessToken)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		bodyBytes, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return fmt.Errorf("failed to read error response body: %w", err)
+	return p.httpClient.Do(req, parsePolarError, response)
+}
+
+func parsePolarError(statusCode int, body []byte) *ProviderError {
+	errorResponse := PolarErrorResponse{}
+	if err := json.Unmarshal(body, &errorResponse); err != nil || errorResponse.Error == "" || len(errorResponse.Detail) == 0 {
+		return &ProviderError{
+			StatusCode: statusCode,
+			Message:    string(body),
 		}
+	}
 
-		// Convert the byte slice to a string
-		bodyString := string(bodyBytes)
+	// The detail is a message, or the validation errors kept as JSON
+	message := string(errorResponse.Detail)
+	var detail string
+	if err := json.Unmarshal(errorResponse.Detail, &detail); err == nil {
+		message = detail
+	}
 
-		return fmt.Errorf("received non-2xx response: %d, with body: %v", resp.StatusCode, bodyString)
+	return &ProviderError{
+		StatusCode: statusCode,
+		Type:       errorResponse.Error,
+		Message:    message,
 	}
+}
 
-	if response == nil {
-		return nil
+// polarTimeToISO8601 converts a timestamp of Polar, which has microseconds and
+// an offset, to the ISO 8601 format used in the database.
+func polarTimeToISO8601(timestamp string) (string, error) {
+	t, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse timestamp %q: %w", timestamp, err)
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return fmt.Errorf("failed to decode response body: %w", err)
+	return format.TimeToISO8601(t), nil
+}
+
+func polarOptionalTimeToISO8601(timestamp *string) (*string, error) {
+	if timestamp == nil {
+		return nil, nil
+	}
+
+	converted, err := polarTimeToISO8601(*timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &converted, nil
+}
+
+// PolarPagination is the pagination of the list endpoints of Polar, of which
+// the pages start at 1.
+type PolarPagination struct {
+	TotalCount int `json:"total_count"`
+	MaxPage    int `json:"max_page"`
+}
+
+// getPolarList gets every item of the list endpoint, page by page.
+func getPolarList[Item any](ctx context.Context, p *PolarProvider, endpoint string, query url.Values) ([]Item, error) {
+	items := []Item{}
+
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+		query.Set("limit", "100")
+
+		resp := &struct {
+			Items      []Item          `json:"items"`
+			Pagination PolarPagination `json:"pagination"`
+		}{}
+
+		err := p.sendRequest(ctx, http.MethodGet, endpoint+"?"+query.Encode(), nil, resp)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, resp.Items...)
+
+		if page >= resp.Pagination.MaxPage {
+			break
+		}
 	}
 
-	return nil
+	return items, nil
 }
@@ -0,0 +1,46 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// CreateCheckout creates a checkout session of the Polar product of the price
+// for the customer, with the discount applied. Polar has no cancel URL, so the
+// cancel URL is used as the URL of the back button on the checkout page. Polar
+// sets the expiry of the session itself.
+func (p *PolarProvider) CreateCheckout(ctx context.Context, customerExternalID, priceExternalID string, discountExternalID *string, successURL, cancelURL string) (*Checkout, error) {
+	body := map[string]any{
+		"products":    []string{priceExternalID},
+		"customer_id": customerExternalID,
+		"success_url": successURL,
+		"return_url":  cancelURL,
+	}
+
+	if discountExternalID != nil {
+		body["discount_id"] = *discountExternalID
+	}
+
+	resp := &struct {
+		ID        string `json:"id"`
+		URL       string `json:"url"`
+		ExpiresAt string `json:"expires_at"`
+	}{}
+
+	err := p.sendRequest(ctx, http.MethodPost, "/checkouts/", body, resp)
+	if err != nil {
+		return nil, fmt.Errorf("error creating checkout in Polar: %w", err)
+	}
+
+	expiresAt, err := polarTimeToISO8601(resp.ExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("error converting Polar checkout response: %w", err)
+	}
+
+	return &Checkout{
+		ExternalID: resp.ID,
+		URL:        resp.URL,
+		ExpiresAt:  expiresAt,
+	}, nil
+}
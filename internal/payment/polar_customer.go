@@ -4,21 +4,38 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 )
 
+type PolarCustomerResponse struct {
+	ID       string         `json:"id"`
+	Email    string         `json:"email"`
+	Metadata map[string]any `json:"metadata"`
+}
+
+// CreateCustomer creates the customer with the ID of the user as its external
+// ID, which Polar keeps unique. A customer already created for the user by a
+// failed attempt is returned instead.
 func (p *PolarProvider) CreateCustomer(ctx context.Context, params CreateCustomerParams) (string, error) {
 	body := map[string]any{
-		"name":  params.Name,
-		"email": params.Email,
+		"external_id": params.UserID,
+		"name":        params.Name,
+		"email":       params.Email,
+		"metadata": map[string]any{
+			"user_id": params.UserID,
+		},
 	}
 
 	resp := &struct {
 		ExternalID string `json:"id"`
 	}{}
 
-	err := p.sendRequest(http.MethodPost, "/customers/", body, resp)
+	err := p.sendRequest(ctx, http.MethodPost, "/customers/", body, resp)
 	if err != nil {
-		return "", fmt.Errorf("error creating customer in Polar: %w", err)
+		getErr := p.sendRequest(ctx, http.MethodGet, "/customers/external/"+url.PathEscape(params.UserID), nil, resp)
+		if getErr != nil {
+			return "", fmt.Errorf("error creating customer in Polar: %w", err)
+		}
 	}
 
 	return resp.ExternalID, nil
@@ -30,7 +47,7 @@ func (p *PolarProvider) UpdateCustomer(ctx context.Context, params UpdateCustome
 		"email": params.Email,
 	}
 
-	err := p.sendRequest(http.MethodPatch, "/customers/"+params.ExternalID, body, nil)
+	err := p.sendRequest(ctx, http.MethodPatch, "/customers/"+params.ExternalID, body, nil)
 	if err != nil {
 		return fmt.Errorf("error updating customer in Polar: %w", err)
 	}
@@ -39,10 +56,33 @@ func (p *PolarProvider) UpdateCustomer(ctx context.Context, params UpdateCustome
 }
 
 func (p *PolarProvider) DeleteCustomer(ctx context.Context, externalID string) error {
-	err := p.sendRequest(http.MethodDelete, "/customers/"+externalID, nil, nil)
+	err := p.sendRequest(ctx, http.MethodDelete, "/customers/"+externalID, nil, nil)
 	if err != nil {
 		return fmt.Errorf("error deleting customer in Polar: %w", err)
 	}
 
 	return nil
 }
+
+func (p *PolarProvider) GetCustomerList(ctx context.Context) ([]Customer, error) {
+	items, err := getPolarList[PolarCustomerResponse](ctx, p, "/customers/", url.Values{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting customers in Polar: %w", err)
+	}
+
+	customers := []Customer{}
+	for _, item := range items {
+		customer := Customer{
+			ExternalID: item.ID,
+			Email:      item.Email,
+		}
+
+		if userID, ok := item.Metadata["user_id"].(string); ok && userID != "" {
+			customer.UserID = &userID
+		}
+
+		customers = append(customers, customer)
+	}
+
+	return customers, nil
+}
@@ -0,0 +1,101 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/jljl1337/issho/internal/env"
+)
+
+type PolarDiscountResponse struct {
+	ID       string         `json:"id"`
+	Name     string         `json:"name"`
+	Metadata map[string]any `json:"metadata"`
+}
+
+// CreateDiscount creates a discount without a code, so that it can only be
+// applied by a checkout. The code is kept in its metadata.
+func (p *PolarProvider) CreateDiscount(ctx context.Context, params CreateDiscountParams) (string, error) {
+	body := map[string]any{
+		"name":            params.Name,
+		"type":            params.Type,
+		"duration":        params.Duration,
+		"max_redemptions": params.RedemptionMax,
+		"ends_at":         params.ExpiresAt,
+		"metadata": map[string]any{
+			"code": params.Code,
+		},
+	}
+
+	// Polar takes percentages in basis points
+	if params.Type == env.DiscountTypePercentage {
+		body["basis_points"] = params.Amount * 100
+	} else {
+		body["amount"] = params.Amount
+		body["currency"] = params.Currency
+	}
+
+	if params.DurationInMonths != nil {
+		body["duration_in_months"] = params.DurationInMonths
+	}
+
+	resp := &struct {
+		ID string `json:"id"`
+	}{}
+
+	err := p.sendRequest(ctx, http.MethodPost, "/discounts/", body, resp)
+	if err != nil {
+		return "", fmt.Errorf("error creating discount in Polar: %w", err)
+	}
+
+	return resp.ID, nil
+}
+
+func (p *PolarProvider) UpdateDiscount(ctx context.Context, params UpdateDiscountParams) error {
+	body := map[string]any{
+		"name":            params.Name,
+		"max_redemptions": params.RedemptionMax,
+		"ends_at":         params.ExpiresAt,
+	}
+
+	err := p.sendRequest(ctx, http.MethodPatch, "/discounts/"+params.ExternalID, body, nil)
+	if err != nil {
+		return fmt.Errorf("error updating discount in Polar: %w", err)
+	}
+
+	return nil
+}
+
+func (p *PolarProvider) DeleteDiscount(ctx context.Context, externalID string) error {
+	err := p.sendRequest(ctx, http.MethodDelete, "/discounts/"+externalID, nil, nil)
+	if err != nil {
+		return fmt.Errorf("error deleting discount in Polar: %w", err)
+	}
+
+	return nil
+}
+
+func (p *PolarProvider) GetDiscountList(ctx context.Context) ([]CatalogDiscount, error) {
+	items, err := getPolarList[PolarDiscountResponse](ctx, p, "/discounts/", url.Values{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting discounts in Polar: %w", err)
+	}
+
+	discounts := []CatalogDiscount{}
+	for _, item := range items {
+		var code *string
+		if value, ok := item.Metadata["code"].(string); ok {
+			code = &value
+		}
+
+		discounts = append(discounts, CatalogDiscount{
+			ExternalID: item.ID,
+			Code:       code,
+			Name:       item.Name,
+		})
+	}
+
+	return discounts, nil
+}
@@ -0,0 +1,76 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type PolarOrderResponse struct {
+	ID             string  `json:"id"`
+	CustomerID     string  `json:"customer_id"`
+	ProductID      string  `json:"product_id"`
+	DiscountID     *string `json:"discount_id"`
+	SubscriptionID *string `json:"subscription_id"`
+	Status         string  `json:"status"`
+	TotalAmount    int     `json:"total_amount"`
+	Currency       string  `json:"currency"`
+	CreatedAt      string  `json:"created_at"`
+}
+
+func (p *PolarProvider) GetOrderList(ctx context.Context, customerExternalID string) ([]Order, error) {
+	orders := []Order{}
+
+	for page := 1; ; page++ {
+		query := url.Values{}
+		query.Set("customer_id", customerExternalID)
+		query.Set("page", strconv.Itoa(page))
+		query.Set("limit", "100")
+
+		resp := &struct {
+			Items      []PolarOrderResponse `json:"items"`
+			Pagination PolarPagination      `json:"pagination"`
+		}{}
+
+		err := p.sendRequest(ctx, http.MethodGet, "/orders/?"+query.Encode(), nil, resp)
+		if err != nil {
+			return nil, fmt.Errorf("error getting orders in Polar: %w", err)
+		}
+
+		for _, item := range resp.Items {
+			order, err := toOrder(item)
+			if err != nil {
+				return nil, fmt.Errorf("error converting Polar order response: %w", err)
+			}
+
+			orders = append(orders, *order)
+		}
+
+		if page >= resp.Pagination.MaxPage {
+			break
+		}
+	}
+
+	return orders, nil
+}
+
+func toOrder(resp PolarOrderResponse) (*Order, error) {
+	orderedAt, err := polarTimeToISO8601(resp.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Order{
+		ExternalID:             resp.ID,
+		CustomerExternalID:     resp.CustomerID,
+		PriceExternalID:        resp.ProductID,
+		DiscountExternalID:     resp.DiscountID,
+		SubscriptionExternalID: resp.SubscriptionID,
+		Status:                 resp.Status,
+		Amount:                 resp.TotalAmount,
+		Currency:               resp.Currency,
+		OrderedAt:              orderedAt,
+	}, nil
+}
@@ -4,19 +4,21 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 
 	"github.com/jljl1337/issho/internal/repository"
 )
 
 type PolarProductResponse struct {
-	ID                     string               `json:"id"`
-	Name                   string               `json:"name"`
-	Description            *string              `json:"description"`
-	IsRecurring            bool                 `json:"is_recurring"`
-	RecurringInterval      *string              `json:"recurring_interval"`
-	RecurringIntervalCount *int                 `json:"recurring_interval_count"`
-	IsArchived             bool                 `json:"is_archived"`
-	Price                  []PolarPriceResponse `json:"prices"`
+	ID                     string                 `json:"id"`
+	Name                   string                 `json:"name"`
+	Description            *string                `json:"description"`
+	IsRecurring            bool                   `json:"is_recurring"`
+	RecurringInterval      *string                `json:"recurring_interval"`
+	RecurringIntervalCount *int                   `json:"recurring_interval_count"`
+	IsArchived             bool                   `json:"is_archived"`
+	Price                  []PolarPriceResponse   `json:"prices"`
+	Benefits               []PolarBenefitResponse `json:"benefits"`
 }
 
 type PolarPriceResponse struct {
@@ -41,7 +43,7 @@ func (p *PolarProvider) CreatePrice(ctx context.Context, params CreatePriceParam
 	}
 
 	response := &PolarProductResponse{}
-	err := p.sendRequest(http.MethodPost, "/products/", body, response)
+	err := p.sendRequest(ctx, http.MethodPost, "/products/", body, response)
 	if err != nil {
 		return nil, fmt.Errorf("error creating price in Polar: %w", err)
 	}
@@ -51,6 +53,13 @@ func (p *PolarProvider) CreatePrice(ctx context.Context, params CreatePriceParam
 		return nil, fmt.Errorf("error converting Polar price response: %w", err)
 	}
 
+	if params.ProductExternalID != nil {
+		err = p.AttachPrice(ctx, *params.ProductExternalID, price.ExternalID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return price, nil
 }
 
@@ -73,7 +82,7 @@ func (p *PolarProvider) UpdatePrice(ctx context.Context, params UpdatePriceParam
 	}
 
 	response := &PolarProductResponse{}
-	err := p.sendRequest(http.MethodPatch, "/products/"+params.ExternalID, body, response)
+	err := p.sendRequest(ctx, http.MethodPatch, "/products/"+params.ExternalID, body, response)
 	if err != nil {
 		return nil, fmt.Errorf("error creating price in Polar: %w", err)
 	}
@@ -86,6 +95,57 @@ func (p *PolarProvider) UpdatePrice(ctx context.Context, params UpdatePriceParam
 	return price, nil
 }
 
+// CanUpdatePriceAmount returns true, as the price of a Polar product is
+// replaced on update.
+func (p *PolarProvider) CanUpdatePriceAmount() bool {
+	return true
+}
+
+func (p *PolarProvider) AttachPrice(ctx context.Context, productExternalID, priceExternalID string) error {
+	body := map[string]any{
+		"benefits": []string{productExternalID},
+	}
+
+	err := p.sendRequest(ctx, http.MethodPost, "/products/"+priceExternalID+"/benefits", body, &PolarProductResponse{})
+	if err != nil {
+		return fmt.Errorf("error attaching price to product in Polar: %w", err)
+	}
+
+	return nil
+}
+
+// GetPriceList returns the Polar products, each attached to its first custom
+// benefit.
+func (p *PolarProvider) GetPriceList(ctx context.Context) ([]CatalogPrice, error) {
+	items, err := getPolarList[PolarProductResponse](ctx, p, "/products/", url.Values{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting prices in Polar: %w", err)
+	}
+
+	prices := []CatalogPrice{}
+	for _, item := range items {
+		price, err := toPrice(item)
+		if err != nil {
+			return nil, fmt.Errorf("error converting Polar price response: %w", err)
+		}
+
+		catalogPrice := CatalogPrice{
+			Price: *price,
+		}
+
+		for _, benefit := range item.Benefits {
+			if benefit.Type == "custom" {
+				catalogPrice.ProductExternalID = &benefit.ID
+				break
+			}
+		}
+
+		prices = append(prices, catalogPrice)
+	}
+
+	return prices, nil
+}
+
 func toPrice(resp PolarProductResponse) (*repository.Price, error) {
 	if len(resp.Price) == 0 {
 		return nil, fmt.Errorf("no price information found in Polar response")
@@ -2,13 +2,41 @@ package payment
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/url"
 
 	"github.com/jljl1337/issho/internal/repository"
 )
 
+// polarBenefitDescriptionMaxLength is the maximum number of runes Polar accepts
+// in the description of a benefit.
+const polarBenefitDescriptionMaxLength = 42
+
+type PolarBenefitResponse struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+}
+
+// CreateProduct creates a custom benefit named after the product. Polar has no
+// product grouping several prices, so a product is a custom benefit granted by
+// the Polar product of each of its prices.
 func (p *PolarProvider) CreateProduct(ctx context.Context, params CreateProductParams) (*repository.Product, error) {
+	body := map[string]any{
+		"type":        "custom",
+		"description": toPolarBenefitDescription(params.Name),
+		"properties":  map[string]any{},
+	}
+
+	response := &PolarBenefitResponse{}
+	err := p.sendRequest(ctx, http.MethodPost, "/benefits/", body, response)
+	if err != nil {
+		return nil, fmt.Errorf("error creating product in Polar: %w", err)
+	}
+
 	return &repository.Product{
-		ExternalID:  nil,
+		ExternalID:  &response.ID,
 		Name:        params.Name,
 		Description: params.Description,
 		IsActive:    true,
@@ -16,10 +44,70 @@ func (p *PolarProvider) CreateProduct(ctx context.Context, params CreateProductP
 }
 
 func (p *PolarProvider) UpdateProduct(ctx context.Context, params UpdateProductParams) (*repository.Product, error) {
+	if params.ExternalID == "" {
+		return nil, fmt.Errorf("product has no Polar benefit")
+	}
+
+	body := map[string]any{
+		"type":        "custom",
+		"description": toPolarBenefitDescription(params.Name),
+	}
+
+	response := &PolarBenefitResponse{}
+	err := p.sendRequest(ctx, http.MethodPatch, "/benefits/"+params.ExternalID, body, response)
+	if err != nil {
+		return nil, fmt.Errorf("error updating product in Polar: %w", err)
+	}
+
+	// Archiving the Polar products stops the prices from being purchased
+	for _, priceExternalID := range params.PriceExternalIDs {
+		body := map[string]any{
+			"is_archived": !params.IsActive,
+		}
+
+		err := p.sendRequest(ctx, http.MethodPatch, "/products/"+priceExternalID, body, &PolarProductResponse{})
+		if err != nil {
+			return nil, fmt.Errorf("error archiving price %s in Polar: %w", priceExternalID, err)
+		}
+	}
+
 	return &repository.Product{
-		ExternalID:  nil,
+		ExternalID:  &response.ID,
 		Name:        params.Name,
 		Description: params.Description,
-		IsActive:    true,
+		IsActive:    params.IsActive,
 	}, nil
 }
+
+// GetProductList returns the custom benefits, of which the names are the
+// possibly truncated descriptions. Benefits cannot be archived, so they are
+// always active.
+func (p *PolarProvider) GetProductList(ctx context.Context) ([]repository.Product, error) {
+	query := url.Values{}
+	query.Set("type", "custom")
+
+	items, err := getPolarList[PolarBenefitResponse](ctx, p, "/benefits/", query)
+	if err != nil {
+		return nil, fmt.Errorf("error getting products in Polar: %w", err)
+	}
+
+	products := []repository.Product{}
+	for _, item := range items {
+		products = append(products, repository.Product{
+			ExternalID: &item.ID,
+			Name:       item.Description,
+			IsActive:   true,
+		})
+	}
+
+	return products, nil
+}
+
+func toPolarBenefitDescription(name string) string {
+	runes := []rune(name)
+	if len(runes) > polarBenefitDescriptionMaxLength {
+		return string(runes[:polarBenefitDescriptionMaxLength])
+	}
+
+	return name
+}
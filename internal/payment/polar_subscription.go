@@ -0,0 +1,106 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type PolarSubscriptionResponse struct {
+	ID                 string  `json:"id"`
+	CustomerID         string  `json:"customer_id"`
+	ProductID          string  `json:"product_id"`
+	DiscountID         *string `json:"discount_id"`
+	Status             string  `json:"status"`
+	StartedAt          *string `json:"started_at"`
+	CurrentPeriodStart string  `json:"current_period_start"`
+	CurrentPeriodEnd   *string `json:"current_period_end"`
+	CancelAtPeriodEnd  bool    `json:"cancel_at_period_end"`
+	CanceledAt         *string `json:"canceled_at"`
+	EndedAt            *string `json:"ended_at"`
+}
+
+func (p *PolarProvider) GetSubscriptionList(ctx context.Context, customerExternalID string) ([]Subscription, error) {
+	subscriptions := []Subscription{}
+
+	for page := 1; ; page++ {
+		query := url.Values{}
+		query.Set("customer_id", customerExternalID)
+		query.Set("page", strconv.Itoa(page))
+		query.Set("limit", "100")
+
+		resp := &struct {
+			Items      []PolarSubscriptionResponse `json:"items"`
+			Pagination PolarPagination             `json:"pagination"`
+		}{}
+
+		err := p.sendRequest(ctx, http.MethodGet, "/subscriptions/?"+query.Encode(), nil, resp)
+		if err != nil {
+			return nil, fmt.Errorf("error getting subscriptions in Polar: %w", err)
+		}
+
+		for _, item := range resp.Items {
+			subscription, err := toSubscription(item)
+			if err != nil {
+				return nil, fmt.Errorf("error converting Polar subscription response: %w", err)
+			}
+
+			subscriptions = append(subscriptions, *subscription)
+		}
+
+		if page >= resp.Pagination.MaxPage {
+			break
+		}
+	}
+
+	return subscriptions, nil
+}
+
+// toSubscription converts a Polar subscription, of which the product is the
+// price in this application.
+func toSubscription(resp PolarSubscriptionResponse) (*Subscription, error) {
+	currentPeriodStart, err := polarTimeToISO8601(resp.CurrentPeriodStart)
+	if err != nil {
+		return nil, err
+	}
+
+	// Subscriptions which never became active have no start time
+	startedAt := currentPeriodStart
+	if resp.StartedAt != nil {
+		startedAt, err = polarTimeToISO8601(*resp.StartedAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	currentPeriodEnd, err := polarOptionalTimeToISO8601(resp.CurrentPeriodEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	canceledAt, err := polarOptionalTimeToISO8601(resp.CanceledAt)
+	if err != nil {
+		return nil, err
+	}
+
+	endedAt, err := polarOptionalTimeToISO8601(resp.EndedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Subscription{
+		ExternalID:         resp.ID,
+		CustomerExternalID: resp.CustomerID,
+		PriceExternalID:    resp.ProductID,
+		DiscountExternalID: resp.DiscountID,
+		Status:             resp.Status,
+		StartedAt:          startedAt,
+		CurrentPeriodStart: currentPeriodStart,
+		CurrentPeriodEnd:   currentPeriodEnd,
+		CancelAtPeriodEnd:  resp.CancelAtPeriodEnd,
+		CanceledAt:         canceledAt,
+		EndedAt:            endedAt,
+	}, nil
+}
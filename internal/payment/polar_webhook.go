@@ -0,0 +1,60 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jljl1337/issho/internal/env"
+)
+
+// VerifyWebhook verifies a webhook from Polar, which follows the Standard
+// Webhooks specification.
+func (p *PolarProvider) VerifyWebhook(header http.Header, body []byte) (*WebhookEvent, error) {
+	return verifyStandardWebhook(p.webhookSecret, header, body, time.Duration(env.PaymentWebhookToleranceSec)*time.Second)
+}
+
+func (p *PolarProvider) ParseWebhookEvent(ctx context.Context, eventType string, payload []byte) (*WebhookData, error) {
+	return parsePolarWebhookEvent(eventType, payload)
+}
+
+// parsePolarWebhookEvent parses the subscription or order of the
+// "subscription.*" and "order.*" events from Polar, which carry the whole
+// object as data.
+func parsePolarWebhookEvent(eventType string, payload []byte) (*WebhookData, error) {
+	switch {
+	case strings.HasPrefix(eventType, "subscription."):
+		var event struct {
+			Data PolarSubscriptionResponse `json:"data"`
+		}
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return nil, fmt.Errorf("failed to parse Polar subscription event: %w", err)
+		}
+
+		subscription, err := toSubscription(event.Data)
+		if err != nil {
+			return nil, fmt.Errorf("error converting Polar subscription: %w", err)
+		}
+
+		return &WebhookData{Subscription: subscription}, nil
+	case strings.HasPrefix(eventType, "order."):
+		var event struct {
+			Data PolarOrderResponse `json:"data"`
+		}
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return nil, fmt.Errorf("failed to parse Polar order event: %w", err)
+		}
+
+		order, err := toOrder(event.Data)
+		if err != nil {
+			return nil, fmt.Errorf("error converting Polar order: %w", err)
+		}
+
+		return &WebhookData{Order: order}, nil
+	default:
+		return &WebhookData{}, nil
+	}
+}
@@ -2,12 +2,38 @@ package payment
 
 import (
 	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/jmoiron/sqlx"
 
 	"github.com/jljl1337/issho/internal/env"
 	"github.com/jljl1337/issho/internal/repository"
 )
 
+// ErrPriceNotUpdatable is returned when the payment provider cannot change the
+// amount, currency or recurrence of an existing price.
+var ErrPriceNotUpdatable = errors.New("price amount, currency and recurrence cannot be updated")
+
+type idempotencyKeyContextKey struct{}
+
+// WithIdempotencyKey returns a context with which the requests creating
+// objects at the payment provider are sent with the idempotency key, instead
+// of one generated for each request, so that retrying them in another attempt
+// does not create duplicates either.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+func idempotencyKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyContextKey{}).(string)
+	return key
+}
+
 type CreateCustomerParams struct {
+	UserID       string
 	Name         string
 	Email        string
 	LanguageCode string
@@ -30,6 +56,9 @@ type UpdateProductParams struct {
 	Name        string
 	Description string
 	IsActive    bool
+	// External IDs of the active prices of the product, for providers that
+	// archive the prices with the product
+	PriceExternalIDs []string
 }
 
 type CreatePriceParams struct {
@@ -55,6 +84,99 @@ type UpdatePriceParams struct {
 	IsActive               bool
 }
 
+// CreateDiscountParams is a discount to create at the payment provider. The
+// amount is a percentage for percentage discounts, and in the smallest unit of
+// the currency for fixed ones. The code is for reference only, as discounts
+// are applied by checkouts instead of being entered by customers.
+type CreateDiscountParams struct {
+	Code             string
+	Name             string
+	Type             string
+	Amount           int
+	Currency         *string
+	Duration         string
+	DurationInMonths *int
+	RedemptionMax    *int
+	ExpiresAt        *string
+}
+
+type UpdateDiscountParams struct {
+	ExternalID    string
+	Name          string
+	RedemptionMax *int
+	ExpiresAt     *string
+}
+
+// Subscription is the state of a subscription at the payment provider. The
+// timestamps are in ISO 8601, and the price and the discount applied by the
+// checkout are referred to by their external IDs.
+type Subscription struct {
+	ExternalID         string
+	CustomerExternalID string
+	PriceExternalID    string
+	DiscountExternalID *string
+	Status             string
+	StartedAt          string
+	CurrentPeriodStart string
+	CurrentPeriodEnd   *string
+	CancelAtPeriodEnd  bool
+	CanceledAt         *string
+	EndedAt            *string
+}
+
+// Order is the state of an order at the payment provider, which is either a
+// one-time purchase or a payment of a subscription.
+type Order struct {
+	ExternalID             string
+	CustomerExternalID     string
+	PriceExternalID        string
+	DiscountExternalID     *string
+	SubscriptionExternalID *string
+	Status                 string
+	Amount                 int
+	Currency               string
+	OrderedAt              string
+}
+
+// Checkout is a checkout session hosted by the payment provider, which cannot
+// be completed after it expires. The expiry is in ISO 8601.
+type Checkout struct {
+	ExternalID string
+	URL        string
+	ExpiresAt  string
+}
+
+// Customer is a customer at the payment provider. UserID is the ID of the user
+// the customer was created for, which is nil for customers created elsewhere.
+type Customer struct {
+	ExternalID string
+	UserID     *string
+	Email      string
+}
+
+// CatalogPrice is a price at the payment provider, with the external ID of
+// the product it is attached to, if any.
+type CatalogPrice struct {
+	repository.Price
+	ProductExternalID *string
+}
+
+// CatalogDiscount is a discount at the payment provider. Code is the code of
+// the discount it was created for, which is nil for discounts created
+// elsewhere.
+type CatalogDiscount struct {
+	ExternalID string
+	Code       *string
+	Name       string
+}
+
+// WebhookData is the object a webhook event is about. Both fields are nil for
+// events that are not about subscriptions or orders.
+type WebhookData struct {
+	Subscription *Subscription
+	Order        *Order
+}
+
 type PaymentProvider interface {
 	// Return external customer ID
 	CreateCustomer(ctx context.Context, params CreateCustomerParams) (string, error)
@@ -66,13 +188,56 @@ type PaymentProvider interface {
 
 	CreatePrice(ctx context.Context, params CreatePriceParams) (*repository.Price, error)
 	UpdatePrice(ctx context.Context, params UpdatePriceParams) (*repository.Price, error)
+	// Report whether the amount, currency and recurrence of an existing price
+	// can be changed, as UpdatePrice returns ErrPriceNotUpdatable otherwise
+	CanUpdatePriceAmount() bool
+	// Attach an existing price to a product, for prices created before the
+	// product existed at the provider
+	AttachPrice(ctx context.Context, productExternalID, priceExternalID string) error
+
+	// Return external discount ID
+	CreateDiscount(ctx context.Context, params CreateDiscountParams) (string, error)
+	// Update the discount as far as the provider allows. The limits are
+	// enforced by this application, so providers which cannot change them do
+	// not keep them either
+	UpdateDiscount(ctx context.Context, params UpdateDiscountParams) error
+	DeleteDiscount(ctx context.Context, externalID string) error
+
+	// Return hosted checkout, with the discount applied if not nil
+	CreateCheckout(ctx context.Context, customerExternalID, priceExternalID string, discountExternalID *string, successURL, cancelURL string) (*Checkout, error)
+
+	// List every object at the provider, for reconciliation
+	GetCustomerList(ctx context.Context) ([]Customer, error)
+	GetProductList(ctx context.Context) ([]repository.Product, error)
+	GetPriceList(ctx context.Context) ([]CatalogPrice, error)
+	GetDiscountList(ctx context.Context) ([]CatalogDiscount, error)
+
+	GetSubscriptionList(ctx context.Context, customerExternalID string) ([]Subscription, error)
+	GetOrderList(ctx context.Context, customerExternalID string) ([]Order, error)
+
+	// Return ErrInvalidWebhookSignature if the webhook is not from the provider
+	VerifyWebhook(header http.Header, body []byte) (*WebhookEvent, error)
+	ParseWebhookEvent(ctx context.Context, eventType string, payload []byte) (*WebhookData, error)
 }
 
-func NewPaymentProvider(providerName string) PaymentProvider {
+func NewPaymentProvider(providerName string, dbInstance *sqlx.DB) PaymentProvider {
 	switch providerName {
 	case "polar":
-		return NewPolarProvider(env.PolarAccessToken, env.PolarIsSandbox)
+		return NewPolarProvider(newHTTPClientFromEnv(), env.PolarAccessToken, env.PolarWebhookSecret, env.PolarIsSandbox)
+	case "stripe":
+		return NewStripeProvider(newHTTPClientFromEnv(), env.StripeSecretKey, env.StripeWebhookSecret, env.StripeAPIBaseURL)
+	case "local":
+		slog.Warn("The local payment provider completes checkouts without payment, and is only for development")
+		return NewLocalProvider(dbInstance)
 	default:
 		return nil
 	}
 }
+
+func newHTTPClientFromEnv() *HTTPClient {
+	return NewHTTPClient(
+		time.Duration(env.PaymentProviderTimeoutSec)*time.Second,
+		env.PaymentProviderRetryMax,
+		time.Duration(env.PaymentProviderRetryDelayMs)*time.Millisecond,
+	)
+}
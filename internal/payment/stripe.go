@@ -0,0 +1,137 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jljl1337/issho/internal/format"
+)
+
+type StripeProvider struct {
+	httpClient    *HTTPClient
+	baseURL       string
+	secretKey     string
+	webhookSecret string
+}
+
+// NewStripeProvider creates a provider for the Stripe API at the base URL,
+// which can point to a local stub such as stripe-mock.
+func NewStripeProvider(httpClient *HTTPClient, secretKey, webhookSecret, baseURL string) *StripeProvider {
+	return &StripeProvider{
+		httpClient:    httpClient,
+		baseURL:       strings.TrimSuffix(baseURL, "/"),
+		secretKey:     secretKey,
+		webhookSecret: webhookSecret,
+	}
+}
+
+// StripeErrorResponse is the body of the error responses of Stripe.
+type StripeErrorResponse struct {
+	Error struct {
+		Type    string `json:"type"`
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// sendRequest sends a request to Stripe, which takes form encoded parameters
+// in the body, or in the query for GET and DELETE requests.
+func (p *StripeProvider) sendRequest(ctx context.Context, method, endpoint string, params url.Values, response any) error {
+	if !mapAllowedMethods[method] {
+		return fmt.Errorf("invalid HTTP method: %s", method)
+	}
+
+	requestURL := p.baseURL + endpoint
+	var body io.Reader
+	if method == http.MethodGet || method == http.MethodDelete {
+		if len(params) > 0 {
+			requestURL += "?" + params.Encode()
+		}
+	} else {
+		body = strings.NewReader(params.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+p.secretKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	return p.httpClient.Do(req, parseStripeError, response)
+}
+
+func parseStripeError(statusCode int, body []byte) *ProviderError {
+	errorResponse := StripeErrorResponse{}
+	if err := json.Unmarshal(body, &errorResponse); err != nil {
+		return &ProviderError{
+			StatusCode: statusCode,
+			Message:    string(body),
+		}
+	}
+
+	return &ProviderError{
+		StatusCode: statusCode,
+		Type:       errorResponse.Error.Type,
+		Code:       errorResponse.Error.Code,
+		Message:    errorResponse.Error.Message,
+	}
+}
+
+// StripeList is a page of a list endpoint of Stripe, which is paginated with
+// the ID of the last item.
+type StripeList[Item any] struct {
+	Data    []Item `json:"data"`
+	HasMore bool   `json:"has_more"`
+}
+
+// getStripeList gets every item of the list endpoint, page by page.
+func getStripeList[Item interface{ stripeID() string }](ctx context.Context, p *StripeProvider, endpoint string, query url.Values) ([]Item, error) {
+	items := []Item{}
+
+	startingAfter := ""
+	for {
+		query.Set("limit", "100")
+		if startingAfter != "" {
+			query.Set("starting_after", startingAfter)
+		}
+
+		resp := &StripeList[Item]{}
+		err := p.sendRequest(ctx, http.MethodGet, endpoint, query, resp)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, resp.Data...)
+
+		if !resp.HasMore || len(resp.Data) == 0 {
+			break
+		}
+
+		startingAfter = resp.Data[len(resp.Data)-1].stripeID()
+	}
+
+	return items, nil
+}
+
+func stripeTimeToISO8601(timestamp int64) string {
+	return format.TimeToISO8601(time.Unix(timestamp, 0))
+}
+
+func stripeOptionalTimeToISO8601(timestamp *int64) *string {
+	if timestamp == nil {
+		return nil
+	}
+
+	converted := stripeTimeToISO8601(*timestamp)
+	return &converted
+}
@@ -0,0 +1,66 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/jljl1337/issho/internal/env"
+)
+
+// CreateCheckout creates a Checkout Session of the price for the customer, in
+// subscription mode for recurring prices, with the coupon of the discount.
+// One-time payments carry the price in their metadata, as Stripe does not link
+// payments to prices, and the payment or subscription carries the coupon in
+// its metadata, as the coupon is only kept by the checkout. The session
+// expires after the checkout lifetime, which Stripe requires to be between 30
+// minutes and 24 hours.
+func (p *StripeProvider) CreateCheckout(ctx context.Context, customerExternalID, priceExternalID string, discountExternalID *string, successURL, cancelURL string) (*Checkout, error) {
+	price := &StripePriceResponse{}
+	err := p.sendRequest(ctx, http.MethodGet, "/prices/"+priceExternalID, nil, price)
+	if err != nil {
+		return nil, fmt.Errorf("error getting price in Stripe: %w", err)
+	}
+
+	form := url.Values{}
+	form.Set("customer", customerExternalID)
+	form.Set("line_items[0][price]", priceExternalID)
+	form.Set("line_items[0][quantity]", "1")
+	form.Set("success_url", successURL)
+	form.Set("cancel_url", cancelURL)
+	form.Set("expires_at", strconv.FormatInt(time.Now().Add(time.Duration(env.CheckoutLifetimeMin)*time.Minute).Unix(), 10))
+
+	metadataPrefix := "payment_intent_data[metadata]"
+	if price.Type == "recurring" {
+		form.Set("mode", "subscription")
+		metadataPrefix = "subscription_data[metadata]"
+	} else {
+		form.Set("mode", "payment")
+		form.Set(metadataPrefix+"["+stripePriceMetadataKey+"]", priceExternalID)
+	}
+
+	if discountExternalID != nil {
+		form.Set("discounts[0][coupon]", *discountExternalID)
+		form.Set(metadataPrefix+"["+stripeDiscountMetadataKey+"]", *discountExternalID)
+	}
+
+	resp := &struct {
+		ID        string `json:"id"`
+		URL       string `json:"url"`
+		ExpiresAt int64  `json:"expires_at"`
+	}{}
+
+	err = p.sendRequest(ctx, http.MethodPost, "/checkout/sessions", form, resp)
+	if err != nil {
+		return nil, fmt.Errorf("error creating checkout in Stripe: %w", err)
+	}
+
+	return &Checkout{
+		ExternalID: resp.ID,
+		URL:        resp.URL,
+		ExpiresAt:  stripeTimeToISO8601(resp.ExpiresAt),
+	}, nil
+}
@@ -0,0 +1,86 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type StripeCustomerResponse struct {
+	ID       string            `json:"id"`
+	Email    *string           `json:"email"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+func (resp StripeCustomerResponse) stripeID() string {
+	return resp.ID
+}
+
+func (p *StripeProvider) CreateCustomer(ctx context.Context, params CreateCustomerParams) (string, error) {
+	form := url.Values{}
+	form.Set("name", params.Name)
+	form.Set("email", params.Email)
+	form.Set("preferred_locales[0]", params.LanguageCode)
+	form.Set("metadata[user_id]", params.UserID)
+
+	resp := &struct {
+		ExternalID string `json:"id"`
+	}{}
+
+	err := p.sendRequest(ctx, http.MethodPost, "/customers", form, resp)
+	if err != nil {
+		return "", fmt.Errorf("error creating customer in Stripe: %w", err)
+	}
+
+	return resp.ExternalID, nil
+}
+
+func (p *StripeProvider) UpdateCustomer(ctx context.Context, params UpdateCustomerParams) error {
+	form := url.Values{}
+	form.Set("name", params.Name)
+	form.Set("email", params.Email)
+	form.Set("preferred_locales[0]", params.LanguageCode)
+
+	err := p.sendRequest(ctx, http.MethodPost, "/customers/"+params.ExternalID, form, nil)
+	if err != nil {
+		return fmt.Errorf("error updating customer in Stripe: %w", err)
+	}
+
+	return nil
+}
+
+func (p *StripeProvider) DeleteCustomer(ctx context.Context, externalID string) error {
+	err := p.sendRequest(ctx, http.MethodDelete, "/customers/"+externalID, nil, nil)
+	if err != nil {
+		return fmt.Errorf("error deleting customer in Stripe: %w", err)
+	}
+
+	return nil
+}
+
+func (p *StripeProvider) GetCustomerList(ctx context.Context) ([]Customer, error) {
+	items, err := getStripeList[StripeCustomerResponse](ctx, p, "/customers", url.Values{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting customers in Stripe: %w", err)
+	}
+
+	customers := []Customer{}
+	for _, item := range items {
+		customer := Customer{
+			ExternalID: item.ID,
+		}
+
+		if userID, ok := item.Metadata["user_id"]; ok && userID != "" {
+			customer.UserID = &userID
+		}
+
+		if item.Email != nil {
+			customer.Email = *item.Email
+		}
+
+		customers = append(customers, customer)
+	}
+
+	return customers, nil
+}
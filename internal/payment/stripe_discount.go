@@ -0,0 +1,99 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/jljl1337/issho/internal/env"
+)
+
+type StripeCouponResponse struct {
+	ID       string            `json:"id"`
+	Name     *string           `json:"name"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+func (resp StripeCouponResponse) stripeID() string {
+	return resp.ID
+}
+
+// CreateDiscount creates a coupon, with the code in its metadata. The limits
+// are not sent, as Stripe cannot change them once the coupon is created, and
+// they are enforced by the checkout of this application instead.
+func (p *StripeProvider) CreateDiscount(ctx context.Context, params CreateDiscountParams) (string, error) {
+	form := url.Values{}
+	form.Set("name", params.Name)
+	form.Set("duration", params.Duration)
+	form.Set("metadata[code]", params.Code)
+
+	if params.Type == env.DiscountTypePercentage {
+		form.Set("percent_off", strconv.Itoa(params.Amount))
+	} else {
+		form.Set("amount_off", strconv.Itoa(params.Amount))
+		form.Set("currency", *params.Currency)
+	}
+
+	if params.DurationInMonths != nil {
+		form.Set("duration_in_months", strconv.Itoa(*params.DurationInMonths))
+	}
+
+	resp := &struct {
+		ID string `json:"id"`
+	}{}
+
+	err := p.sendRequest(ctx, http.MethodPost, "/coupons", form, resp)
+	if err != nil {
+		return "", fmt.Errorf("error creating coupon in Stripe: %w", err)
+	}
+
+	return resp.ID, nil
+}
+
+// UpdateDiscount updates the name of the coupon, which is the only field of a
+// coupon Stripe can change.
+func (p *StripeProvider) UpdateDiscount(ctx context.Context, params UpdateDiscountParams) error {
+	form := url.Values{}
+	form.Set("name", params.Name)
+
+	err := p.sendRequest(ctx, http.MethodPost, "/coupons/"+params.ExternalID, form, nil)
+	if err != nil {
+		return fmt.Errorf("error updating coupon in Stripe: %w", err)
+	}
+
+	return nil
+}
+
+func (p *StripeProvider) DeleteDiscount(ctx context.Context, externalID string) error {
+	err := p.sendRequest(ctx, http.MethodDelete, "/coupons/"+externalID, nil, nil)
+	if err != nil {
+		return fmt.Errorf("error deleting coupon in Stripe: %w", err)
+	}
+
+	return nil
+}
+
+func (p *StripeProvider) GetDiscountList(ctx context.Context) ([]CatalogDiscount, error) {
+	items, err := getStripeList[StripeCouponResponse](ctx, p, "/coupons", url.Values{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting coupons in Stripe: %w", err)
+	}
+
+	discounts := []CatalogDiscount{}
+	for _, item := range items {
+		name := ""
+		if item.Name != nil {
+			name = *item.Name
+		}
+
+		discounts = append(discounts, CatalogDiscount{
+			ExternalID: item.ID,
+			Code:       stripeMetadataValue(item.Metadata, "code"),
+			Name:       name,
+		})
+	}
+
+	return discounts, nil
+}
@@ -0,0 +1,144 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/jljl1337/issho/internal/env"
+)
+
+// stripePriceMetadataKey is the metadata key of the price of a one-time
+// payment, set when creating the checkout.
+const stripePriceMetadataKey = "price_id"
+
+// stripeDiscountMetadataKey is the metadata key of the coupon applied to a
+// one-time payment or a subscription, set when creating the checkout.
+const stripeDiscountMetadataKey = "discount_id"
+
+type StripeChargeResponse struct {
+	ID             string `json:"id"`
+	PaymentIntent  string `json:"payment_intent"`
+	Refunded       bool   `json:"refunded"`
+	AmountRefunded int    `json:"amount_refunded"`
+}
+
+// StripePaymentIntentResponse is a Stripe PaymentIntent, of which the latest
+// charge is either its ID or the charge itself when expanded.
+type StripePaymentIntentResponse struct {
+	ID             string            `json:"id"`
+	Customer       *string           `json:"customer"`
+	Status         string            `json:"status"`
+	AmountReceived int               `json:"amount_received"`
+	Currency       string            `json:"currency"`
+	Created        int64             `json:"created"`
+	Metadata       map[string]string `json:"metadata"`
+	LatestCharge   json.RawMessage   `json:"latest_charge"`
+}
+
+// GetOrderList returns the one-time payments of the customer as orders.
+// Payments of subscriptions are not orders with Stripe, as they are covered by
+// the subscriptions.
+func (p *StripeProvider) GetOrderList(ctx context.Context, customerExternalID string) ([]Order, error) {
+	orders := []Order{}
+
+	startingAfter := ""
+	for {
+		query := url.Values{}
+		query.Set("customer", customerExternalID)
+		query.Set("limit", "100")
+		query.Set("expand[]", "data.latest_charge")
+		if startingAfter != "" {
+			query.Set("starting_after", startingAfter)
+		}
+
+		resp := &StripeList[StripePaymentIntentResponse]{}
+		err := p.sendRequest(ctx, http.MethodGet, "/payment_intents", query, resp)
+		if err != nil {
+			return nil, fmt.Errorf("error getting payment intents in Stripe: %w", err)
+		}
+
+		for _, item := range resp.Data {
+			order, err := toStripeOrder(item)
+			if err != nil {
+				return nil, fmt.Errorf("error converting Stripe payment intent response: %w", err)
+			}
+
+			if order != nil {
+				orders = append(orders, *order)
+			}
+		}
+
+		if !resp.HasMore || len(resp.Data) == 0 {
+			break
+		}
+
+		startingAfter = resp.Data[len(resp.Data)-1].ID
+	}
+
+	return orders, nil
+}
+
+// getOrder gets the payment intent with its latest charge, to have its refund
+// status, and converts it to an order.
+func (p *StripeProvider) getOrder(ctx context.Context, paymentIntentID string) (*Order, error) {
+	query := url.Values{}
+	query.Set("expand[]", "latest_charge")
+
+	resp := &StripePaymentIntentResponse{}
+	err := p.sendRequest(ctx, http.MethodGet, "/payment_intents/"+paymentIntentID, query, resp)
+	if err != nil {
+		return nil, fmt.Errorf("error getting payment intent in Stripe: %w", err)
+	}
+
+	return toStripeOrder(*resp)
+}
+
+// toStripeOrder converts a succeeded payment intent of a checkout of this
+// application to an order, or returns nil for other payment intents.
+func toStripeOrder(resp StripePaymentIntentResponse) (*Order, error) {
+	priceExternalID, ok := resp.Metadata[stripePriceMetadataKey]
+	if !ok || resp.Customer == nil || resp.Status != "succeeded" {
+		return nil, nil
+	}
+
+	status := env.OrderStatusPaid
+	if bytes.HasPrefix(bytes.TrimSpace(resp.LatestCharge), []byte("{")) {
+		charge := StripeChargeResponse{}
+		if err := json.Unmarshal(resp.LatestCharge, &charge); err != nil {
+			return nil, fmt.Errorf("failed to parse latest charge: %w", err)
+		}
+
+		if charge.Refunded {
+			status = env.OrderStatusRefunded
+		} else if charge.AmountRefunded > 0 {
+			status = env.OrderStatusPartiallyRefunded
+		}
+	}
+
+	return &Order{
+		ExternalID:             resp.ID,
+		CustomerExternalID:     *resp.Customer,
+		PriceExternalID:        priceExternalID,
+		DiscountExternalID:     stripeMetadataValue(resp.Metadata, stripeDiscountMetadataKey),
+		SubscriptionExternalID: nil,
+		Status:                 status,
+		Amount:                 resp.AmountReceived,
+		Currency:               resp.Currency,
+		OrderedAt:              stripeTimeToISO8601(resp.Created),
+	}, nil
+}
+
+// stripeMetadataValue returns the value of the metadata key, or nil if it is
+// not set.
+func stripeMetadataValue(metadata map[string]string, key string) *string {
+	value, ok := metadata[key]
+	if !ok {
+		return nil
+	}
+
+	return &value
+}
@@ -0,0 +1,152 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/jljl1337/issho/internal/repository"
+)
+
+type StripePriceResponse struct {
+	ID         string  `json:"id"`
+	Product    string  `json:"product"`
+	Nickname   *string `json:"nickname"`
+	UnitAmount int     `json:"unit_amount"`
+	Currency   string  `json:"currency"`
+	Type       string  `json:"type"`
+	Recurring  *struct {
+		Interval      string `json:"interval"`
+		IntervalCount int    `json:"interval_count"`
+	} `json:"recurring"`
+	Active   bool              `json:"active"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+func (resp StripePriceResponse) stripeID() string {
+	return resp.ID
+}
+
+// CreatePrice creates a price of the Stripe product. Stripe prices have no
+// description, so it is kept in the metadata.
+func (p *StripeProvider) CreatePrice(ctx context.Context, params CreatePriceParams) (*repository.Price, error) {
+	if params.ProductExternalID == nil {
+		return nil, fmt.Errorf("product has no Stripe product")
+	}
+
+	form := url.Values{}
+	form.Set("product", *params.ProductExternalID)
+	form.Set("nickname", params.Name)
+	form.Set("metadata[description]", params.Description)
+	form.Set("unit_amount", strconv.Itoa(params.PriceAmount))
+	form.Set("currency", params.PriceCurrency)
+
+	if params.IsRecurring {
+		form.Set("recurring[interval]", *params.RecurringInterval)
+		form.Set("recurring[interval_count]", strconv.Itoa(*params.RecurringIntervalCount))
+	}
+
+	response := &StripePriceResponse{}
+	err := p.sendRequest(ctx, http.MethodPost, "/prices", form, response)
+	if err != nil {
+		return nil, fmt.Errorf("error creating price in Stripe: %w", err)
+	}
+
+	return toStripePrice(*response), nil
+}
+
+// UpdatePrice updates the name, description and active status of the Stripe
+// price. The amount, currency and recurrence of Stripe prices are immutable,
+// so changing them returns ErrPriceNotUpdatable.
+func (p *StripeProvider) UpdatePrice(ctx context.Context, params UpdatePriceParams) (*repository.Price, error) {
+	current := &StripePriceResponse{}
+	err := p.sendRequest(ctx, http.MethodGet, "/prices/"+params.ExternalID, nil, current)
+	if err != nil {
+		return nil, fmt.Errorf("error getting price in Stripe: %w", err)
+	}
+
+	price := toStripePrice(*current)
+	if price.PriceAmount != params.PriceAmount ||
+		price.PriceCurrency != params.PriceCurrency ||
+		price.IsRecurring != params.IsRecurring ||
+		(params.IsRecurring && (*price.RecurringInterval != *params.RecurringInterval ||
+			*price.RecurringIntervalCount != *params.RecurringIntervalCount)) {
+		return nil, ErrPriceNotUpdatable
+	}
+
+	form := url.Values{}
+	form.Set("nickname", params.Name)
+	form.Set("metadata[description]", params.Description)
+	form.Set("active", strconv.FormatBool(params.IsActive))
+
+	response := &StripePriceResponse{}
+	err = p.sendRequest(ctx, http.MethodPost, "/prices/"+params.ExternalID, form, response)
+	if err != nil {
+		return nil, fmt.Errorf("error updating price in Stripe: %w", err)
+	}
+
+	return toStripePrice(*response), nil
+}
+
+// CanUpdatePriceAmount returns false, as Stripe prices are immutable.
+func (p *StripeProvider) CanUpdatePriceAmount() bool {
+	return false
+}
+
+// AttachPrice only checks the price belongs to the product, as the product of a
+// Stripe price cannot be changed.
+func (p *StripeProvider) AttachPrice(ctx context.Context, productExternalID, priceExternalID string) error {
+	response := &StripePriceResponse{}
+	err := p.sendRequest(ctx, http.MethodGet, "/prices/"+priceExternalID, nil, response)
+	if err != nil {
+		return fmt.Errorf("error getting price in Stripe: %w", err)
+	}
+
+	if response.Product != productExternalID {
+		return fmt.Errorf("Stripe price %s belongs to another product", priceExternalID)
+	}
+
+	return nil
+}
+
+func (p *StripeProvider) GetPriceList(ctx context.Context) ([]CatalogPrice, error) {
+	items, err := getStripeList[StripePriceResponse](ctx, p, "/prices", url.Values{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting prices in Stripe: %w", err)
+	}
+
+	prices := []CatalogPrice{}
+	for _, item := range items {
+		prices = append(prices, CatalogPrice{
+			Price:             *toStripePrice(item),
+			ProductExternalID: &item.Product,
+		})
+	}
+
+	return prices, nil
+}
+
+func toStripePrice(resp StripePriceResponse) *repository.Price {
+	price := &repository.Price{
+		ExternalID:    resp.ID,
+		Name:          "",
+		Description:   resp.Metadata["description"],
+		PriceAmount:   resp.UnitAmount,
+		PriceCurrency: resp.Currency,
+		IsRecurring:   resp.Type == "recurring",
+		IsActive:      resp.Active,
+	}
+
+	if resp.Nickname != nil {
+		price.Name = *resp.Nickname
+	}
+
+	if price.IsRecurring && resp.Recurring != nil {
+		price.RecurringInterval = &resp.Recurring.Interval
+		price.RecurringIntervalCount = &resp.Recurring.IntervalCount
+	}
+
+	return price
+}
@@ -0,0 +1,89 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/jljl1337/issho/internal/repository"
+)
+
+type StripeProductResponse struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Description *string `json:"description"`
+	Active      bool    `json:"active"`
+}
+
+func (resp StripeProductResponse) stripeID() string {
+	return resp.ID
+}
+
+func (p *StripeProvider) CreateProduct(ctx context.Context, params CreateProductParams) (*repository.Product, error) {
+	form := url.Values{}
+	form.Set("name", params.Name)
+
+	// Stripe rejects an empty description when creating a product
+	if params.Description != "" {
+		form.Set("description", params.Description)
+	}
+
+	response := &StripeProductResponse{}
+	err := p.sendRequest(ctx, http.MethodPost, "/products", form, response)
+	if err != nil {
+		return nil, fmt.Errorf("error creating product in Stripe: %w", err)
+	}
+
+	return toStripeProduct(*response), nil
+}
+
+func (p *StripeProvider) UpdateProduct(ctx context.Context, params UpdateProductParams) (*repository.Product, error) {
+	if params.ExternalID == "" {
+		return nil, fmt.Errorf("product has no Stripe product")
+	}
+
+	// An empty description unsets the description
+	form := url.Values{}
+	form.Set("name", params.Name)
+	form.Set("description", params.Description)
+	form.Set("active", strconv.FormatBool(params.IsActive))
+
+	response := &StripeProductResponse{}
+	err := p.sendRequest(ctx, http.MethodPost, "/products/"+params.ExternalID, form, response)
+	if err != nil {
+		return nil, fmt.Errorf("error updating product in Stripe: %w", err)
+	}
+
+	return toStripeProduct(*response), nil
+}
+
+func (p *StripeProvider) GetProductList(ctx context.Context) ([]repository.Product, error) {
+	items, err := getStripeList[StripeProductResponse](ctx, p, "/products", url.Values{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting products in Stripe: %w", err)
+	}
+
+	products := []repository.Product{}
+	for _, item := range items {
+		products = append(products, *toStripeProduct(item))
+	}
+
+	return products, nil
+}
+
+func toStripeProduct(resp StripeProductResponse) *repository.Product {
+	product := &repository.Product{
+		ExternalID:  &resp.ID,
+		Name:        resp.Name,
+		Description: "",
+		IsActive:    resp.Active,
+	}
+
+	if resp.Description != nil {
+		product.Description = *resp.Description
+	}
+
+	return product
+}
@@ -0,0 +1,106 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type StripeSubscriptionItemResponse struct {
+	Price struct {
+		ID string `json:"id"`
+	} `json:"price"`
+	CurrentPeriodStart *int64 `json:"current_period_start"`
+	CurrentPeriodEnd   *int64 `json:"current_period_end"`
+}
+
+// StripeSubscriptionResponse is a Stripe subscription. Newer API versions have
+// the current period on the items instead of the subscription.
+type StripeSubscriptionResponse struct {
+	ID                 string                                     `json:"id"`
+	Customer           string                                     `json:"customer"`
+	Status             string                                     `json:"status"`
+	StartDate          int64                                      `json:"start_date"`
+	CurrentPeriodStart *int64                                     `json:"current_period_start"`
+	CurrentPeriodEnd   *int64                                     `json:"current_period_end"`
+	CancelAtPeriodEnd  bool                                       `json:"cancel_at_period_end"`
+	CanceledAt         *int64                                     `json:"canceled_at"`
+	EndedAt            *int64                                     `json:"ended_at"`
+	Metadata           map[string]string                          `json:"metadata"`
+	Items              StripeList[StripeSubscriptionItemResponse] `json:"items"`
+}
+
+func (p *StripeProvider) GetSubscriptionList(ctx context.Context, customerExternalID string) ([]Subscription, error) {
+	subscriptions := []Subscription{}
+
+	startingAfter := ""
+	for {
+		query := url.Values{}
+		query.Set("customer", customerExternalID)
+		query.Set("status", "all")
+		query.Set("limit", "100")
+		if startingAfter != "" {
+			query.Set("starting_after", startingAfter)
+		}
+
+		resp := &StripeList[StripeSubscriptionResponse]{}
+		err := p.sendRequest(ctx, http.MethodGet, "/subscriptions", query, resp)
+		if err != nil {
+			return nil, fmt.Errorf("error getting subscriptions in Stripe: %w", err)
+		}
+
+		for _, item := range resp.Data {
+			subscription, err := toStripeSubscription(item)
+			if err != nil {
+				return nil, fmt.Errorf("error converting Stripe subscription response: %w", err)
+			}
+
+			subscriptions = append(subscriptions, *subscription)
+		}
+
+		if !resp.HasMore || len(resp.Data) == 0 {
+			break
+		}
+
+		startingAfter = resp.Data[len(resp.Data)-1].ID
+	}
+
+	return subscriptions, nil
+}
+
+func toStripeSubscription(resp StripeSubscriptionResponse) (*Subscription, error) {
+	if len(resp.Items.Data) == 0 {
+		return nil, fmt.Errorf("subscription %s has no items", resp.ID)
+	}
+
+	item := resp.Items.Data[0]
+
+	currentPeriodStart := resp.CurrentPeriodStart
+	if currentPeriodStart == nil {
+		currentPeriodStart = item.CurrentPeriodStart
+	}
+
+	currentPeriodEnd := resp.CurrentPeriodEnd
+	if currentPeriodEnd == nil {
+		currentPeriodEnd = item.CurrentPeriodEnd
+	}
+
+	if currentPeriodStart == nil {
+		currentPeriodStart = &resp.StartDate
+	}
+
+	return &Subscription{
+		ExternalID:         resp.ID,
+		CustomerExternalID: resp.Customer,
+		PriceExternalID:    item.Price.ID,
+		DiscountExternalID: stripeMetadataValue(resp.Metadata, stripeDiscountMetadataKey),
+		Status:             resp.Status,
+		StartedAt:          stripeTimeToISO8601(resp.StartDate),
+		CurrentPeriodStart: stripeTimeToISO8601(*currentPeriodStart),
+		CurrentPeriodEnd:   stripeOptionalTimeToISO8601(currentPeriodEnd),
+		CancelAtPeriodEnd:  resp.CancelAtPeriodEnd,
+		CanceledAt:         stripeOptionalTimeToISO8601(resp.CanceledAt),
+		EndedAt:            stripeOptionalTimeToISO8601(resp.EndedAt),
+	}, nil
+}
@@ -0,0 +1,567 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/format"
+)
+
+const (
+	testStripeSecretKey     = "sk_test_123"
+	testStripeWebhookSecret = "whsec_test_123"
+)
+
+// newTestStripeProvider returns a Stripe provider sending its requests to a
+// local server with the handler, as STRIPE_API_BASE_URL does in place of the
+// Stripe API.
+func newTestStripeProvider(t *testing.T, handler http.HandlerFunc) *StripeProvider {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	httpClient := NewHTTPClient(5*time.Second, 0, time.Millisecond)
+	return NewStripeProvider(httpClient, testStripeSecretKey, testStripeWebhookSecret, server.URL+"/v1")
+}
+
+// readTestForm checks the request is form encoded and returns its body.
+func readTestForm(t *testing.T, r *http.Request) url.Values {
+	t.Helper()
+
+	if contentType := r.Header.Get("Content-Type"); contentType != "application/x-www-form-urlencoded" {
+		t.Errorf("Content-Type = %q, want application/x-www-form-urlencoded", contentType)
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		t.Fatalf("failed to read request body: %v", err)
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		t.Fatalf("failed to parse request body %q: %v", body, err)
+	}
+
+	return form
+}
+
+func checkTestValues(t *testing.T, values url.Values, want map[string]string) {
+	t.Helper()
+
+	for key, value := range want {
+		if got := values.Get(key); got != value {
+			t.Errorf("%s = %q, want %q", key, got, value)
+		}
+	}
+}
+
+func TestStripeCreateCustomer(t *testing.T) {
+	provider := newTestStripeProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/customers" {
+			t.Errorf("request = %s %s, want POST /v1/customers", r.Method, r.URL.Path)
+		}
+
+		if got := r.Header.Get("Authorization"); got != "Bearer "+testStripeSecretKey {
+			t.Errorf("Authorization = %q", got)
+		}
+
+		if got := r.Header.Get("Idempotency-Key"); got != "user-1" {
+			t.Errorf("Idempotency-Key = %q, want user-1", got)
+		}
+
+		checkTestValues(t, readTestForm(t, r), map[string]string{
+			"name":                 "Alice & Bob",
+			"email":                "alice+test@example.com",
+			"preferred_locales[0]": "en-US",
+			"metadata[user_id]":    "user-1",
+		})
+
+		fmt.Fprint(w, `{"id": "cus_123"}`)
+	})
+
+	ctx := WithIdempotencyKey(context.Background(), "user-1")
+
+	externalID, err := provider.CreateCustomer(ctx, CreateCustomerParams{
+		UserID:       "user-1",
+		Name:         "Alice & Bob",
+		Email:        "alice+test@example.com",
+		LanguageCode: "en-US",
+	})
+	if err != nil {
+		t.Fatalf("CreateCustomer() error = %v", err)
+	}
+
+	if externalID != "cus_123" {
+		t.Errorf("CreateCustomer() = %q, want cus_123", externalID)
+	}
+}
+
+func TestStripeCreateCheckout(t *testing.T) {
+	env.CheckoutLifetimeMin = 60
+	couponID := "coupon_1"
+
+	tests := []struct {
+		name      string
+		priceType string
+		discount  *string
+		want      map[string]string
+		wantEmpty []string
+	}{
+		{
+			name:      "recurring price with discount",
+			priceType: "recurring",
+			discount:  &couponID,
+			want: map[string]string{
+				"customer":                "cus_123",
+				"line_items[0][price]":    "price_123",
+				"line_items[0][quantity]": "1",
+				"mode":                    "subscription",
+				"discounts[0][coupon]":    "coupon_1",
+				"success_url":             "https://example.com/success?a=1&b=2",
+				"cancel_url":              "https://example.com/cancel",
+				"subscription_data[metadata][discount_id]": "coupon_1",
+			},
+			wantEmpty: []string{"payment_intent_data[metadata][price_id]"},
+		},
+		{
+			name:      "one-time price with discount",
+			priceType: "one_time",
+			discount:  &couponID,
+			want: map[string]string{
+				"mode":                 "payment",
+				"discounts[0][coupon]": "coupon_1",
+				"payment_intent_data[metadata][price_id]":    "price_123",
+				"payment_intent_data[metadata][discount_id]": "coupon_1",
+			},
+			wantEmpty: []string{"subscription_data[metadata][discount_id]"},
+		},
+		{
+			name:      "one-time price",
+			priceType: "one_time",
+			discount:  nil,
+			want: map[string]string{
+				"mode": "payment",
+				"payment_intent_data[metadata][price_id]": "price_123",
+			},
+			wantEmpty: []string{"discounts[0][coupon]", "payment_intent_data[metadata][discount_id]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newTestStripeProvider(t, func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodGet && r.URL.Path == "/v1/prices/price_123":
+					fmt.Fprintf(w, `{"id": "price_123", "type": %q}`, tt.priceType)
+				case r.Method == http.MethodPost && r.URL.Path == "/v1/checkout/sessions":
+					form := readTestForm(t, r)
+					checkTestValues(t, form, tt.want)
+					for _, key := range tt.wantEmpty {
+						if form.Has(key) {
+							t.Errorf("%s = %q, want unset", key, form.Get(key))
+						}
+					}
+
+					// The session expires after the checkout lifetime
+					expiresAt, err := strconv.ParseInt(form.Get("expires_at"), 10, 64)
+					if err != nil {
+						t.Errorf("expires_at = %q, want a Unix timestamp", form.Get("expires_at"))
+					}
+					if lifetime := time.Until(time.Unix(expiresAt, 0)); lifetime < 59*time.Minute || lifetime > time.Hour {
+						t.Errorf("expires_at is %s from now, want 1h", lifetime)
+					}
+
+					fmt.Fprint(w, `{"id": "cs_123", "url": "https://checkout.stripe.com/c/cs_123", "expires_at": 1700000000}`)
+				default:
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			})
+
+			checkout, err := provider.CreateCheckout(context.Background(), "cus_123", "price_123", tt.discount, "https://example.com/success?a=1&b=2", "https://example.com/cancel")
+			if err != nil {
+				t.Fatalf("CreateCheckout() error = %v", err)
+			}
+
+			want := Checkout{
+				ExternalID: "cs_123",
+				URL:        "https://checkout.stripe.com/c/cs_123",
+				ExpiresAt:  format.TimeToISO8601(time.Unix(1700000000, 0)),
+			}
+			if *checkout != want {
+				t.Errorf("CreateCheckout() = %+v, want %+v", *checkout, want)
+			}
+		})
+	}
+}
+
+func TestStripeGetSubscriptionListPagination(t *testing.T) {
+	requests := 0
+
+	provider := newTestStripeProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		if r.Method != http.MethodGet || r.URL.Path != "/v1/subscriptions" {
+			t.Errorf("request = %s %s, want GET /v1/subscriptions", r.Method, r.URL.Path)
+		}
+
+		if r.ContentLength > 0 || r.Header.Get("Content-Type") != "" {
+			t.Errorf("GET request has a body of type %q", r.Header.Get("Content-Type"))
+		}
+
+		query := r.URL.Query()
+		checkTestValues(t, query, map[string]string{
+			"customer": "cus_123",
+			"status":   "all",
+			"limit":    "100",
+		})
+
+		item := `{"price": {"id": "price_123"}, "current_period_start": 1700000000, "current_period_end": 1702592000}`
+		switch startingAfter := query.Get("starting_after"); startingAfter {
+		case "":
+			fmt.Fprintf(w, `{"data": [
+				{"id": "sub_1", "customer": "cus_123", "status": "active", "start_date": 1700000000, "items": {"data": [%s]}},
+				{"id": "sub_2", "customer": "cus_123", "status": "canceled", "start_date": 1700000000, "ended_at": 1701000000, "items": {"data": [%s]}}
+			], "has_more": true}`, item, item)
+		case "sub_2":
+			fmt.Fprintf(w, `{"data": [
+				{"id": "sub_3", "customer": "cus_123", "status": "trialing", "start_date": 1700000000, "items": {"data": [%s]}}
+			], "has_more": false}`, item)
+		default:
+			t.Errorf("starting_after = %q, want sub_2", startingAfter)
+			fmt.Fprint(w, `{"data": [], "has_more": false}`)
+		}
+	})
+
+	subscriptions, err := provider.GetSubscriptionList(context.Background(), "cus_123")
+	if err != nil {
+		t.Fatalf("GetSubscriptionList() error = %v", err)
+	}
+
+	if requests != 2 {
+		t.Errorf("requests = %d, want 2", requests)
+	}
+
+	if len(subscriptions) != 3 {
+		t.Fatalf("len(subscriptions) = %d, want 3", len(subscriptions))
+	}
+
+	for i, id := range []string{"sub_1", "sub_2", "sub_3"} {
+		if subscriptions[i].ExternalID != id {
+			t.Errorf("subscriptions[%d].ExternalID = %q, want %q", i, subscriptions[i].ExternalID, id)
+		}
+	}
+
+	// The period is taken from the item for newer API versions
+	subscription := subscriptions[1]
+	if subscription.PriceExternalID != "price_123" ||
+		subscription.CurrentPeriodStart != format.TimeToISO8601(time.Unix(1700000000, 0)) ||
+		subscription.CurrentPeriodEnd == nil || *subscription.CurrentPeriodEnd != format.TimeToISO8601(time.Unix(1702592000, 0)) ||
+		subscription.EndedAt == nil || *subscription.EndedAt != format.TimeToISO8601(time.Unix(1701000000, 0)) {
+		t.Errorf("subscriptions[1] = %+v", subscription)
+	}
+}
+
+func TestStripeRequestError(t *testing.T) {
+	provider := newTestStripeProvider(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error": {"type": "invalid_request_error", "code": "resource_missing", "message": "No such customer"}}`)
+	})
+
+	err := provider.DeleteCustomer(context.Background(), "cus_missing")
+
+	var providerErr *ProviderError
+	if !errors.As(err, &providerErr) {
+		t.Fatalf("DeleteCustomer() error = %v, want a ProviderError", err)
+	}
+
+	if providerErr.StatusCode != http.StatusBadRequest || providerErr.Type != "invalid_request_error" || providerErr.Code != "resource_missing" {
+		t.Errorf("ProviderError = %+v", providerErr)
+	}
+
+	if errors.Is(err, ErrProviderUnavailable) {
+		t.Errorf("DeleteCustomer() error is ErrProviderUnavailable for a 400 response")
+	}
+}
+
+// signTestStripeWebhook returns the Stripe-Signature header of the body signed
+// at the time with the secret.
+func signTestStripeWebhook(secret string, signedAt time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+	return "t=" + timestamp + ",v1=" + testStripeSignature(secret, timestamp, body)
+}
+
+func testStripeSignature(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestStripeVerifyWebhook(t *testing.T) {
+	env.PaymentWebhookToleranceSec = 300
+
+	body := []byte(`{"id": "evt_123", "type": "customer.subscription.updated"}`)
+	now := time.Now()
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	tests := []struct {
+		name      string
+		signature string
+		wantErr   bool
+	}{
+		{
+			name:      "valid signature",
+			signature: signTestStripeWebhook(testStripeWebhookSecret, now, body),
+		},
+		{
+			name:      "valid signature within tolerance",
+			signature: signTestStripeWebhook(testStripeWebhookSecret, now.Add(-290*time.Second), body),
+		},
+		{
+			name:      "one of several signatures is valid",
+			signature: "t=" + timestamp + ",v1=" + testStripeSignature("whsec_old", timestamp, body) + ",v1=" + testStripeSignature(testStripeWebhookSecret, timestamp, body),
+		},
+		{
+			name:      "wrong secret",
+			signature: signTestStripeWebhook("whsec_other", now, body),
+			wantErr:   true,
+		},
+		{
+			name:      "timestamp too old",
+			signature: signTestStripeWebhook(testStripeWebhookSecret, now.Add(-310*time.Second), body),
+			wantErr:   true,
+		},
+		{
+			name:      "timestamp too far in the future",
+			signature: signTestStripeWebhook(testStripeWebhookSecret, now.Add(310*time.Second), body),
+			wantErr:   true,
+		},
+		{
+			name:      "missing signature",
+			signature: "t=" + timestamp,
+			wantErr:   true,
+		},
+		{
+			name:      "missing header",
+			signature: "",
+			wantErr:   true,
+		},
+	}
+
+	provider := NewStripeProvider(NewHTTPClient(time.Second, 0, time.Millisecond), testStripeSecretKey, testStripeWebhookSecret, "http://127.0.0.1")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.signature != "" {
+				header.Set("Stripe-Signature", tt.signature)
+			}
+
+			event, err := provider.VerifyWebhook(header, body)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidWebhookSignature) {
+					t.Errorf("VerifyWebhook() error = %v, want ErrInvalidWebhookSignature", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("VerifyWebhook() error = %v", err)
+			}
+
+			if event.ID != "evt_123" || event.Type != "customer.subscription.updated" {
+				t.Errorf("VerifyWebhook() = %+v", event)
+			}
+		})
+	}
+
+	t.Run("tampered body", func(t *testing.T) {
+		header := http.Header{}
+		header.Set("Stripe-Signature", signTestStripeWebhook(testStripeWebhookSecret, now, body))
+
+		_, err := provider.VerifyWebhook(header, []byte(`{"id": "evt_456", "type": "customer.subscription.updated"}`))
+		if !errors.Is(err, ErrInvalidWebhookSignature) {
+			t.Errorf("VerifyWebhook() error = %v, want ErrInvalidWebhookSignature", err)
+		}
+	})
+}
+
+func TestStripeParseWebhookEventOrder(t *testing.T) {
+	orderedAt := format.TimeToISO8601(time.Unix(1700000000, 0))
+	couponID := "coupon_1"
+
+	tests := []struct {
+		name      string
+		eventType string
+		payload   string
+		// Payment intent returned by the server for a refunded charge
+		paymentIntent string
+		want          *Order
+	}{
+		{
+			name:      "succeeded payment intent",
+			eventType: "payment_intent.succeeded",
+			payload: `{"data": {"object": {
+				"id": "pi_123", "customer": "cus_123", "status": "succeeded",
+				"amount_received": 1000, "currency": "usd", "created": 1700000000,
+				"metadata": {"price_id": "price_123"}, "latest_charge": "ch_123"
+			}}}`,
+			want: &Order{
+				ExternalID:         "pi_123",
+				CustomerExternalID: "cus_123",
+				PriceExternalID:    "price_123",
+				Status:             env.OrderStatusPaid,
+				Amount:             1000,
+				Currency:           "usd",
+				OrderedAt:          orderedAt,
+			},
+		},
+		{
+			name:      "succeeded payment intent with discount",
+			eventType: "payment_intent.succeeded",
+			payload: `{"data": {"object": {
+				"id": "pi_123", "customer": "cus_123", "status": "succeeded",
+				"amount_received": 800, "currency": "usd", "created": 1700000000,
+				"metadata": {"price_id": "price_123", "discount_id": "coupon_1"}, "latest_charge": "ch_123"
+			}}}`,
+			want: &Order{
+				ExternalID:         "pi_123",
+				CustomerExternalID: "cus_123",
+				PriceExternalID:    "price_123",
+				DiscountExternalID: &couponID,
+				Status:             env.OrderStatusPaid,
+				Amount:             800,
+				Currency:           "usd",
+				OrderedAt:          orderedAt,
+			},
+		},
+		{
+			name:      "payment intent not from a checkout",
+			eventType: "payment_intent.succeeded",
+			payload: `{"data": {"object": {
+				"id": "pi_123", "customer": "cus_123", "status": "succeeded",
+				"amount_received": 1000, "currency": "usd", "created": 1700000000,
+				"metadata": {}, "latest_charge": "ch_123"
+			}}}`,
+			want: nil,
+		},
+		{
+			name:      "fully refunded charge",
+			eventType: "charge.refunded",
+			payload:   `{"data": {"object": {"id": "ch_123", "payment_intent": "pi_123", "refunded": true, "amount_refunded": 1000}}}`,
+			paymentIntent: `{
+				"id": "pi_123", "customer": "cus_123", "status": "succeeded",
+				"amount_received": 1000, "currency": "usd", "created": 1700000000,
+				"metadata": {"price_id": "price_123"},
+				"latest_charge": {"id": "ch_123", "payment_intent": "pi_123", "refunded": true, "amount_refunded": 1000}
+			}`,
+			want: &Order{
+				ExternalID:         "pi_123",
+				CustomerExternalID: "cus_123",
+				PriceExternalID:    "price_123",
+				Status:             env.OrderStatusRefunded,
+				Amount:             1000,
+				Currency:           "usd",
+				OrderedAt:          orderedAt,
+			},
+		},
+		{
+			name:      "partially refunded charge",
+			eventType: "charge.refunded",
+			payload:   `{"data": {"object": {"id": "ch_123", "payment_intent": "pi_123", "refunded": false, "amount_refunded": 400}}}`,
+			paymentIntent: `{
+				"id": "pi_123", "customer": "cus_123", "status": "succeeded",
+				"amount_received": 1000, "currency": "usd", "created": 1700000000,
+				"metadata": {"price_id": "price_123"},
+				"latest_charge": {"id": "ch_123", "payment_intent": "pi_123", "refunded": false, "amount_refunded": 400}
+			}`,
+			want: &Order{
+				ExternalID:         "pi_123",
+				CustomerExternalID: "cus_123",
+				PriceExternalID:    "price_123",
+				Status:             env.OrderStatusPartiallyRefunded,
+				Amount:             1000,
+				Currency:           "usd",
+				OrderedAt:          orderedAt,
+			},
+		},
+		{
+			name:      "charge without payment intent",
+			eventType: "charge.refunded",
+			payload:   `{"data": {"object": {"id": "ch_123", "refunded": true, "amount_refunded": 1000}}}`,
+			want:      nil,
+		},
+		{
+			name:      "unrelated event",
+			eventType: "customer.created",
+			payload:   `{"data": {"object": {"id": "cus_123"}}}`,
+			want:      nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newTestStripeProvider(t, func(w http.ResponseWriter, r *http.Request) {
+				if tt.paymentIntent == "" || r.Method != http.MethodGet || r.URL.Path != "/v1/payment_intents/pi_123" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+
+				if got := r.URL.Query().Get("expand[]"); got != "latest_charge" {
+					t.Errorf("expand[] = %q, want latest_charge", got)
+				}
+
+				fmt.Fprint(w, tt.paymentIntent)
+			})
+
+			data, err := provider.ParseWebhookEvent(context.Background(), tt.eventType, []byte(tt.payload))
+			if err != nil {
+				t.Fatalf("ParseWebhookEvent() error = %v", err)
+			}
+
+			if data.Subscription != nil {
+				t.Errorf("ParseWebhookEvent() subscription = %+v, want nil", data.Subscription)
+			}
+
+			if tt.want == nil {
+				if data.Order != nil {
+					t.Errorf("ParseWebhookEvent() order = %+v, want nil", data.Order)
+				}
+				return
+			}
+
+			if data.Order == nil {
+				t.Fatalf("ParseWebhookEvent() order = nil, want %+v", tt.want)
+			}
+
+			got := *data.Order
+			if got.ExternalID != tt.want.ExternalID ||
+				got.CustomerExternalID != tt.want.CustomerExternalID ||
+				got.PriceExternalID != tt.want.PriceExternalID ||
+				(got.DiscountExternalID == nil) != (tt.want.DiscountExternalID == nil) ||
+				(got.DiscountExternalID != nil && *got.DiscountExternalID != *tt.want.DiscountExternalID) ||
+				got.SubscriptionExternalID != nil ||
+				got.Status != tt.want.Status ||
+				got.Amount != tt.want.Amount ||
+				got.Currency != tt.want.Currency ||
+				got.OrderedAt != tt.want.OrderedAt {
+				t.Errorf("ParseWebhookEvent() order = %+v, want %+v", got, *tt.want)
+			}
+		})
+	}
+}
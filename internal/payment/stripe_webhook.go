@@ -0,0 +1,158 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jljl1337/issho/internal/env"
+)
+
+// VerifyWebhook verifies a webhook from Stripe, which is signed with the
+// timestamp and the body in the Stripe-Signature header.
+func (p *StripeProvider) VerifyWebhook(header http.Header, body []byte) (*WebhookEvent, error) {
+	if p.webhookSecret == "" {
+		return nil, fmt.Errorf("webhook secret is not configured")
+	}
+
+	timestamp := ""
+	signatures := []string{}
+	for item := range strings.SplitSeq(header.Get("Stripe-Signature"), ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(item), "=")
+		if !found {
+			continue
+		}
+
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	if timestamp == "" || len(signatures) == 0 {
+		return nil, fmt.Errorf("%w: missing signature header", ErrInvalidWebhookSignature)
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid timestamp", ErrInvalidWebhookSignature)
+	}
+
+	tolerance := time.Duration(env.PaymentWebhookToleranceSec) * time.Second
+	signedAt := time.Unix(seconds, 0)
+	if age := time.Since(signedAt); age > tolerance || age < -tolerance {
+		return nil, fmt.Errorf("%w: timestamp outside of tolerance", ErrInvalidWebhookSignature)
+	}
+
+	mac := hmac.New(sha256.New, []byte(p.webhookSecret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	expected := mac.Sum(nil)
+
+	verified := false
+	for _, signature := range signatures {
+		decoded, err := hex.DecodeString(signature)
+		if err != nil {
+			continue
+		}
+
+		if hmac.Equal(decoded, expected) {
+			verified = true
+			break
+		}
+	}
+
+	if !verified {
+		return nil, ErrInvalidWebhookSignature
+	}
+
+	var payload struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse webhook payload: %w", err)
+	}
+
+	if payload.ID == "" {
+		return nil, fmt.Errorf("webhook payload has no event ID")
+	}
+
+	return &WebhookEvent{
+		ID:        payload.ID,
+		Type:      payload.Type,
+		Timestamp: signedAt,
+	}, nil
+}
+
+// ParseWebhookEvent parses the subscription of the "customer.subscription.*"
+// events, and the order of the "payment_intent.succeeded" and "charge.refunded"
+// events from Stripe. A refunded charge only refers to its payment intent, so
+// the payment intent is fetched from Stripe.
+func (p *StripeProvider) ParseWebhookEvent(ctx context.Context, eventType string, payload []byte) (*WebhookData, error) {
+	switch {
+	case strings.HasPrefix(eventType, "customer.subscription."):
+		var event struct {
+			Data struct {
+				Object StripeSubscriptionResponse `json:"object"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return nil, fmt.Errorf("failed to parse Stripe subscription event: %w", err)
+		}
+
+		subscription, err := toStripeSubscription(event.Data.Object)
+		if err != nil {
+			return nil, fmt.Errorf("error converting Stripe subscription: %w", err)
+		}
+
+		return &WebhookData{Subscription: subscription}, nil
+	case eventType == "payment_intent.succeeded":
+		var event struct {
+			Data struct {
+				Object StripePaymentIntentResponse `json:"object"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return nil, fmt.Errorf("failed to parse Stripe payment intent event: %w", err)
+		}
+
+		order, err := toStripeOrder(event.Data.Object)
+		if err != nil {
+			return nil, fmt.Errorf("error converting Stripe payment intent: %w", err)
+		}
+
+		return &WebhookData{Order: order}, nil
+	case eventType == "charge.refunded":
+		var event struct {
+			Data struct {
+				Object StripeChargeResponse `json:"object"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return nil, fmt.Errorf("failed to parse Stripe charge event: %w", err)
+		}
+
+		if event.Data.Object.PaymentIntent == "" {
+			return &WebhookData{}, nil
+		}
+
+		order, err := p.getOrder(ctx, event.Data.Object.PaymentIntent)
+		if err != nil {
+			return nil, err
+		}
+
+		return &WebhookData{Order: order}, nil
+	default:
+		return &WebhookData{}, nil
+	}
+}
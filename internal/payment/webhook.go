@@ -0,0 +1,119 @@
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidWebhookSignature is returned when a webhook is not signed by the
+// provider, or is signed too long ago to rule out a replay.
+var ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+
+// WebhookEvent is a verified webhook event of a payment provider. The ID is
+// unique per provider and is used to process each event only once.
+type WebhookEvent struct {
+	ID        string
+	Type      string
+	Timestamp time.Time
+}
+
+// signStandardWebhook returns the headers of a webhook signed as in the
+// Standard Webhooks specification, with the secret used as is.
+func signStandardWebhook(secret, id string, timestamp time.Time, body []byte) http.Header {
+	seconds := strconv.FormatInt(timestamp.Unix(), 10)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(id + "." + seconds + "."))
+	mac.Write(body)
+
+	header := http.Header{}
+	header.Set("webhook-id", id)
+	header.Set("webhook-timestamp", seconds)
+	header.Set("webhook-signature", "v1,"+base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	return header
+}
+
+// verifyStandardWebhook verifies a webhook signed as in the Standard Webhooks
+// specification, and parses the type from the "type" field of the payload.
+// The secret is either prefixed with "whsec_" and base64 encoded, or used as
+// is, like the secrets of Polar.
+func verifyStandardWebhook(secret string, header http.Header, body []byte, tolerance time.Duration) (*WebhookEvent, error) {
+	if secret == "" {
+		return nil, fmt.Errorf("webhook secret is not configured")
+	}
+
+	id := header.Get("webhook-id")
+	timestamp := header.Get("webhook-timestamp")
+	signatures := header.Get("webhook-signature")
+	if id == "" || timestamp == "" || signatures == "" {
+		return nil, fmt.Errorf("%w: missing webhook headers", ErrInvalidWebhookSignature)
+	}
+
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid timestamp", ErrInvalidWebhookSignature)
+	}
+
+	signedAt := time.Unix(seconds, 0)
+	if age := time.Since(signedAt); age > tolerance || age < -tolerance {
+		return nil, fmt.Errorf("%w: timestamp outside of tolerance", ErrInvalidWebhookSignature)
+	}
+
+	key := []byte(secret)
+	if encoded, ok := strings.CutPrefix(secret, "whsec_"); ok {
+		key, err = base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode webhook secret: %w", err)
+		}
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(id + "." + timestamp + "."))
+	mac.Write(body)
+	expected := mac.Sum(nil)
+
+	// There can be multiple space separated signatures, e.g. during secret
+	// rotation, of which one has to match
+	verified := false
+	for _, versionedSignature := range strings.Fields(signatures) {
+		version, signature, found := strings.Cut(versionedSignature, ",")
+		if !found || version != "v1" {
+			continue
+		}
+
+		decoded, err := base64.StdEncoding.DecodeString(signature)
+		if err != nil {
+			continue
+		}
+
+		if hmac.Equal(decoded, expected) {
+			verified = true
+			break
+		}
+	}
+
+	if !verified {
+		return nil, ErrInvalidWebhookSignature
+	}
+
+	var payload struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("failed to parse webhook payload: %w", err)
+	}
+
+	return &WebhookEvent{
+		ID:        id,
+		Type:      payload.Type,
+		Timestamp: signedAt,
+	}, nil
+}
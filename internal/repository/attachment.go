@@ -0,0 +1,93 @@
+package repository
+
+import "context"
+
+const createAttachment = `
+	INSERT INTO attachment (
+		id,
+		post_id,
+		user_id,
+		file_name,
+		content_type,
+		size_bytes,
+		width,
+		height,
+		storage_key,
+		thumbnail_storage_key,
+		created_at,
+		updated_at
+	) VALUES (
+		:id,
+		:post_id,
+		:user_id,
+		:file_name,
+		:content_type,
+		:size_bytes,
+		:width,
+		:height,
+		:storage_key,
+		:thumbnail_storage_key,
+		:created_at,
+		:updated_at
+	)
+`
+
+func (q *Queries) CreateAttachment(ctx context.Context, arg Attachment) error {
+	return NamedExecOneRowContext(ctx, q.db, createAttachment, arg)
+}
+
+const getAttachmentListByPostID = `
+	SELECT
+		*
+	FROM
+		attachment
+	WHERE
+		post_id = :post_id
+	ORDER BY
+		created_at ASC,
+		id ASC
+`
+
+type GetAttachmentListByPostIDParams struct {
+	PostID string `db:"post_id"`
+}
+
+func (q *Queries) GetAttachmentListByPostID(ctx context.Context, postID string) ([]Attachment, error) {
+	items := []Attachment{}
+	err := NamedSelectContext(ctx, q.db, &items, getAttachmentListByPostID, GetAttachmentListByPostIDParams{PostID: postID})
+	return items, err
+}
+
+const getAttachmentByID = `
+	SELECT
+		*
+	FROM
+		attachment
+	WHERE
+		id = :id
+`
+
+type GetAttachmentByIDParams struct {
+	ID string `db:"id"`
+}
+
+func (q *Queries) GetAttachmentByID(ctx context.Context, id string) ([]Attachment, error) {
+	items := []Attachment{}
+	err := NamedSelectContext(ctx, q.db, &items, getAttachmentByID, GetAttachmentByIDParams{ID: id})
+	return items, err
+}
+
+const deleteAttachmentByID = `
+	DELETE FROM
+		attachment
+	WHERE
+		id = :id
+`
+
+type DeleteAttachmentByIDParams struct {
+	ID string `db:"id"`
+}
+
+func (q *Queries) DeleteAttachmentByID(ctx context.Context, id string) error {
+	return NamedExecOneRowContext(ctx, q.db, deleteAttachmentByID, DeleteAttachmentByIDParams{ID: id})
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jljl1337/issho/internal/template"
+)
+
+const createBookmark = `
+	INSERT INTO bookmark (
+		user_id,
+		post_id,
+		created_at
+	) VALUES (
+		:user_id,
+		:post_id,
+		:created_at
+	)
+	ON CONFLICT (user_id, post_id) DO NOTHING
+`
+
+// CreateBookmark bookmarks the post for the user, keeping the existing
+// bookmark if the post is already bookmarked.
+func (q *Queries) CreateBookmark(ctx context.Context, arg Bookmark) error {
+	_, err := NamedExecRowsAffectedContext(ctx, q.db, createBookmark, arg)
+	return err
+}
+
+const getBookmarkedPostList = `
+	SELECT
+		post.*,
+		bookmark.created_at AS bookmarked_at
+	FROM
+		bookmark
+	INNER JOIN
+		post ON post.id = bookmark.post_id
+	WHERE
+		bookmark.user_id = :user_id AND
+		post.deleted_at IS NULL AND
+		post.published_at IS NOT NULL AND
+		post.published_at <= :now AND (
+			:cursor IS NULL OR :cursor_id IS NULL OR
+			bookmark.created_at {{if .Ascending}}>{{else}}<{{end}} :cursor OR (
+				bookmark.created_at = :cursor AND post.id {{if .Ascending}}>{{else}}<{{end}} :cursor_id
+			)
+		)
+	ORDER BY
+		bookmark.created_at {{if .Ascending}}ASC{{else}}DESC{{end}},
+		post.id {{if .Ascending}}ASC{{else}}DESC{{end}}
+	LIMIT
+		:page_size
+`
+
+type GetBookmarkedPostListParams struct {
+	UserID    string  `db:"user_id"`
+	Ascending bool    // not a db tag, used for formatting
+	Now       string  `db:"now"`
+	PageSize  int     `db:"page_size"`
+	Cursor    *string `db:"cursor"`
+	CursorID  *string `db:"cursor_id"`
+}
+
+// GetBookmarkedPostList returns the published posts bookmarked by the user,
+// ordered by the time they were bookmarked. The cursor is the time the post
+// was bookmarked, and the cursor ID is the post ID.
+func (q *Queries) GetBookmarkedPostList(ctx context.Context, arg GetBookmarkedPostListParams) ([]BookmarkedPost, error) {
+	query, err := template.RenderTemplate(getBookmarkedPostList, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render query template: %w", err)
+	}
+
+	items := []BookmarkedPost{}
+	err = NamedSelectContext(ctx, q.db, &items, query, arg)
+	return items, err
+}
+
+const deleteBookmark = `
+	DELETE FROM
+		bookmark
+	WHERE
+		user_id = :user_id AND
+		post_id = :post_id
+`
+
+type DeleteBookmarkParams struct {
+	UserID string `db:"user_id"`
+	PostID string `db:"post_id"`
+}
+
+func (q *Queries) DeleteBookmark(ctx context.Context, arg DeleteBookmarkParams) (int64, error) {
+	return NamedExecRowsAffectedContext(ctx, q.db, deleteBookmark, arg)
+}
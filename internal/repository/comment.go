@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jljl1337/issho/internal/template"
+)
+
+const createComment = `
+	INSERT INTO comment (
+		id,
+		post_id,
+		user_id,
+		parent_id,
+		content,
+		status,
+		deleted_at,
+		created_at,
+		updated_at
+	) VALUES (
+		:id,
+		:post_id,
+		:user_id,
+		:parent_id,
+		:content,
+		:status,
+		:deleted_at,
+		:created_at,
+		:updated_at
+	)
+`
+
+func (q *Queries) CreateComment(ctx context.Context, arg Comment) error {
+	return NamedExecOneRowContext(ctx, q.db, createComment, arg)
+}
+
+const getCommentList = `
+	SELECT
+		*
+	FROM
+		comment
+	WHERE
+		(:post_id IS NULL OR post_id = :post_id) AND
+		(:status IS NULL OR status = :status OR (
+			:viewer_id IS NOT NULL AND user_id = :viewer_id
+		)) AND (
+			:cursor IS NULL OR :cursor_id IS NULL OR
+			created_at {{if .Ascending}}>{{else}}<{{end}} :cursor OR (
+				created_at = :cursor AND id {{if .Ascending}}>{{else}}<{{end}} :cursor_id
+			)
+		)
+	ORDER BY
+		created_at {{if .Ascending}}ASC{{else}}DESC{{end}},
+		id {{if .Ascending}}ASC{{else}}DESC{{end}}
+	LIMIT
+		:page_size
+`
+
+type GetCommentListParams struct {
+	PostID    *string `db:"post_id"`
+	Status    *string `db:"status"`
+	ViewerID  *string `db:"viewer_id"`
+	Ascending bool    // not a db tag, used for formatting
+	PageSize  int     `db:"page_size"`
+	Cursor    *string `db:"cursor"`
+	CursorID  *string `db:"cursor_id"`
+}
+
+func (q *Queries) GetCommentList(ctx context.Context, arg GetCommentListParams) ([]Comment, error) {
+	query, err := template.RenderTemplate(getCommentList, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render query template: %w", err)
+	}
+
+	items := []Comment{}
+	err = NamedSelectContext(ctx, q.db, &items, query, arg)
+	return items, err
+}
+
+const getCommentByID = `
+	SELECT
+		*
+	FROM
+		comment
+	WHERE
+		id = :id
+`
+
+type GetCommentByIDParams struct {
+	ID string `db:"id"`
+}
+
+func (q *Queries) GetCommentByID(ctx context.Context, id string) ([]Comment, error) {
+	items := []Comment{}
+	err := NamedSelectContext(ctx, q.db, &items, getCommentByID, GetCommentByIDParams{ID: id})
+	return items, err
+}
+
+const updateCommentContentByID = `
+	UPDATE
+		comment
+	SET
+		content = :content,
+		status = :status,
+		updated_at = :updated_at
+	WHERE
+		id = :id AND
+		(:version IS NULL OR updated_at = :version)
+`
+
+type UpdateCommentContentByIDParams struct {
+	Content   string  `db:"content"`
+	Status    string  `db:"status"`
+	UpdatedAt string  `db:"updated_at"`
+	ID        string  `db:"id"`
+	Version   *string `db:"version"`
+}
+
+func (q *Queries) UpdateCommentContentByID(ctx context.Context, arg UpdateCommentContentByIDParams) (int64, error) {
+	return NamedExecRowsAffectedContext(ctx, q.db, updateCommentContentByID, arg)
+}
+
+const updateCommentStatusByID = `
+	UPDATE
+		comment
+	SET
+		status = :status,
+		updated_at = :updated_at
+	WHERE
+		id = :id AND
+		(:version IS NULL OR updated_at = :version)
+`
+
+type UpdateCommentStatusByIDParams struct {
+	Status    string  `db:"status"`
+	UpdatedAt string  `db:"updated_at"`
+	ID        string  `db:"id"`
+	Version   *string `db:"version"`
+}
+
+func (q *Queries) UpdateCommentStatusByID(ctx context.Context, arg UpdateCommentStatusByIDParams) (int64, error) {
+	return NamedExecRowsAffectedContext(ctx, q.db, updateCommentStatusByID, arg)
+}
+
+const softDeleteCommentByID = `
+	UPDATE
+		comment
+	SET
+		deleted_at = :deleted_at,
+		updated_at = :updated_at
+	WHERE
+		id = :id AND
+		deleted_at IS NULL
+`
+
+type SoftDeleteCommentByIDParams struct {
+	DeletedAt string `db:"deleted_at"`
+	UpdatedAt string `db:"updated_at"`
+	ID        string `db:"id"`
+}
+
+func (q *Queries) SoftDeleteCommentByID(ctx context.Context, arg SoftDeleteCommentByIDParams) error {
+	return NamedExecOneRowContext(ctx, q.db, softDeleteCommentByID, arg)
+}
@@ -61,3 +61,19 @@ func ExecRowsAffectedContext(ctx context.Context, q sqlx.ExtContext, query strin
 	}
 	return result.RowsAffected()
 }
+
+// NamedSelectInContext is like NamedSelectContext, but also expands slice
+// arguments so they can be used in IN clauses. The slices must not be empty.
+func NamedSelectInContext(ctx context.Context, q sqlx.ExtContext, dest any, query string, arg any) error {
+	query, args, err := sqlx.Named(query, arg)
+	if err != nil {
+		return err
+	}
+
+	query, args, err = sqlx.In(query, args...)
+	if err != nil {
+		return err
+	}
+
+	return sqlx.SelectContext(ctx, q, dest, q.Rebind(query), args...)
+}
@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"context"
+)
+
+const createDiscount = `
+	INSERT INTO discount (
+		id,
+		external_id,
+		code,
+		name,
+		type,
+		amount,
+		currency,
+		duration,
+		duration_in_months,
+		redemption_max,
+		redemption_count,
+		reserved_count,
+		expires_at,
+		is_active,
+		created_at,
+		updated_at
+	) VALUES (
+		:id,
+		:external_id,
+		:code,
+		:name,
+		:type,
+		:amount,
+		:currency,
+		:duration,
+		:duration_in_months,
+		:redemption_max,
+		:redemption_count,
+		:reserved_count,
+		:expires_at,
+		:is_active,
+		:created_at,
+		:updated_at
+	)
+`
+
+func (q *Queries) CreateDiscount(ctx context.Context, arg Discount) error {
+	return NamedExecOneRowContext(ctx, q.db, createDiscount, arg)
+}
+
+const getDiscountList = `
+	SELECT
+		*
+	FROM
+		discount
+	WHERE
+		:cursor IS NULL OR :cursor_id IS NULL OR
+		updated_at < :cursor OR (
+			updated_at = :cursor AND id < :cursor_id
+		)
+	ORDER BY
+		updated_at DESC,
+		id DESC
+	LIMIT
+		:page_size
+`
+
+type GetDiscountListParams struct {
+	Cursor   *string `db:"cursor"`
+	CursorID *string `db:"cursor_id"`
+	PageSize int     `db:"page_size"`
+}
+
+func (q *Queries) GetDiscountList(ctx context.Context, arg GetDiscountListParams) ([]Discount, error) {
+	items := []Discount{}
+	err := NamedSelectContext(ctx, q.db, &items, getDiscountList, arg)
+	return items, err
+}
+
+const getAllDiscountList = `
+	SELECT
+		*
+	FROM
+		discount
+	ORDER BY
+		created_at ASC,
+		id ASC
+`
+
+func (q *Queries) GetAllDiscountList(ctx context.Context) ([]Discount, error) {
+	items := []Discount{}
+	err := NamedSelectContext(ctx, q.db, &items, getAllDiscountList, struct{}{})
+	return items, err
+}
+
+const getDiscountByID = `
+	SELECT
+		*
+	FROM
+		discount
+	WHERE
+		id = :id
+`
+
+type GetDiscountByIDParams struct {
+	ID string `db:"id"`
+}
+
+func (q *Queries) GetDiscountByID(ctx context.Context, id string) ([]Discount, error) {
+	items := []Discount{}
+	err := NamedSelectContext(ctx, q.db, &items, getDiscountByID, GetDiscountByIDParams{ID: id})
+	return items, err
+}
+
+const getDiscountByCode = `
+	SELECT
+		*
+	FROM
+		discount
+	WHERE
+		code = :code
+`
+
+type GetDiscountByCodeParams struct {
+	Code string `db:"code"`
+}
+
+func (q *Queries) GetDiscountByCode(ctx context.Context, code string) ([]Discount, error) {
+	items := []Discount{}
+	err := NamedSelectContext(ctx, q.db, &items, getDiscountByCode, GetDiscountByCodeParams{Code: code})
+	return items, err
+}
+
+const getDiscountByExternalID = `
+	SELECT
+		*
+	FROM
+		discount
+	WHERE
+		external_id = :external_id
+`
+
+type GetDiscountByExternalIDParams struct {
+	ExternalID string `db:"external_id"`
+}
+
+func (q *Queries) GetDiscountByExternalID(ctx context.Context, externalID string) ([]Discount, error) {
+	items := []Discount{}
+	err := NamedSelectContext(ctx, q.db, &items, getDiscountByExternalID, GetDiscountByExternalIDParams{ExternalID: externalID})
+	return items, err
+}
+
+const updateDiscount = `
+	UPDATE
+		discount
+	SET
+		name = :name,
+		redemption_max = :redemption_max,
+		expires_at = :expires_at,
+		is_active = :is_active,
+		updated_at = :updated_at
+	WHERE
+		id = :id AND
+		(:version IS NULL OR updated_at = :version)
+`
+
+type UpdateDiscountParams struct {
+	Name          string  `db:"name"`
+	RedemptionMax *int    `db:"redemption_max"`
+	ExpiresAt     *string `db:"expires_at"`
+	IsActive      bool    `db:"is_active"`
+	UpdatedAt     string  `db:"updated_at"`
+	ID            string  `db:"id"`
+	Version       *string `db:"version"`
+}
+
+func (q *Queries) UpdateDiscount(ctx context.Context, arg UpdateDiscountParams) (int64, error) {
+	return NamedExecRowsAffectedContext(ctx, q.db, updateDiscount, arg)
+}
+
+const redeemDiscount = `
+	UPDATE
+		discount
+	SET
+		redemption_count = redemption_count + 1
+	WHERE
+		id = :id
+`
+
+type RedeemDiscountParams struct {
+	ID string `db:"id"`
+}
+
+// RedeemDiscount counts a redemption of the discount. The updated_at is kept,
+// as it is the version of the discount edited by the owner.
+func (q *Queries) RedeemDiscount(ctx context.Context, id string) error {
+	return NamedExecOneRowContext(ctx, q.db, redeemDiscount, RedeemDiscountParams{ID: id})
+}
+
+const reserveDiscount = `
+	UPDATE
+		discount
+	SET
+		reserved_count = reserved_count + 1
+	WHERE
+		id = :id AND (
+			redemption_max IS NULL OR redemption_count + reserved_count < redemption_max
+		)
+`
+
+type ReserveDiscountParams struct {
+	ID string `db:"id"`
+}
+
+// ReserveDiscount counts a reservation of the discount, unless it has been
+// redeemed or reserved the maximum number of times, in which case no row is
+// affected.
+func (q *Queries) ReserveDiscount(ctx context.Context, id string) (int64, error) {
+	return NamedExecRowsAffectedContext(ctx, q.db, reserveDiscount, ReserveDiscountParams{ID: id})
+}
+
+const releaseDiscount = `
+	UPDATE
+		discount
+	SET
+		reserved_count = reserved_count - 1
+	WHERE
+		id = :id
+`
+
+type ReleaseDiscountParams struct {
+	ID string `db:"id"`
+}
+
+// ReleaseDiscount uncounts a reservation of the discount.
+func (q *Queries) ReleaseDiscount(ctx context.Context, id string) error {
+	return NamedExecOneRowContext(ctx, q.db, releaseDiscount, ReleaseDiscountParams{ID: id})
+}
+
+const redeemDiscountReservation = `
+	UPDATE
+		discount
+	SET
+		reserved_count = reserved_count - 1,
+		redemption_count = redemption_count + 1
+	WHERE
+		id = :id
+`
+
+type RedeemDiscountReservationParams struct {
+	ID string `db:"id"`
+}
+
+// RedeemDiscountReservation turns a reservation of the discount into a
+// redemption.
+func (q *Queries) RedeemDiscountReservation(ctx context.Context, id string) error {
+	return NamedExecOneRowContext(ctx, q.db, redeemDiscountReservation, RedeemDiscountReservationParams{ID: id})
+}
+
+const deleteDiscountByID = `
+	DELETE FROM
+		discount
+	WHERE
+		id = :id
+`
+
+type DeleteDiscountByIDParams struct {
+	ID string `db:"id"`
+}
+
+func (q *Queries) DeleteDiscountByID(ctx context.Context, id string) error {
+	return NamedExecOneRowContext(ctx, q.db, deleteDiscountByID, DeleteDiscountByIDParams{ID: id})
+}
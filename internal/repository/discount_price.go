@@ -0,0 +1,60 @@
+package repository
+
+import "context"
+
+const createDiscountPrice = `
+	INSERT INTO discount_price (
+		discount_id,
+		price_id,
+		created_at
+	) VALUES (
+		:discount_id,
+		:price_id,
+		:created_at
+	)
+`
+
+func (q *Queries) CreateDiscountPrice(ctx context.Context, arg DiscountPrice) error {
+	return NamedExecOneRowContext(ctx, q.db, createDiscountPrice, arg)
+}
+
+const getDiscountPriceListByDiscountIDs = `
+	SELECT
+		*
+	FROM
+		discount_price
+	WHERE
+		discount_id IN (:discount_ids)
+	ORDER BY
+		created_at ASC,
+		price_id ASC
+`
+
+type GetDiscountPriceListByDiscountIDsParams struct {
+	DiscountIDs []string `db:"discount_ids"`
+}
+
+func (q *Queries) GetDiscountPriceListByDiscountIDs(ctx context.Context, discountIDs []string) ([]DiscountPrice, error) {
+	items := []DiscountPrice{}
+	if len(discountIDs) == 0 {
+		return items, nil
+	}
+
+	err := NamedSelectInContext(ctx, q.db, &items, getDiscountPriceListByDiscountIDs, GetDiscountPriceListByDiscountIDsParams{DiscountIDs: discountIDs})
+	return items, err
+}
+
+const deleteDiscountPriceByDiscountID = `
+	DELETE FROM
+		discount_price
+	WHERE
+		discount_id = :discount_id
+`
+
+type DeleteDiscountPriceByDiscountIDParams struct {
+	DiscountID string `db:"discount_id"`
+}
+
+func (q *Queries) DeleteDiscountPriceByDiscountID(ctx context.Context, discountID string) (int64, error) {
+	return NamedExecRowsAffectedContext(ctx, q.db, deleteDiscountPriceByDiscountID, DeleteDiscountPriceByDiscountIDParams{DiscountID: discountID})
+}
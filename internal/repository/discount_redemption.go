@@ -0,0 +1,25 @@
+package repository
+
+import "context"
+
+const createDiscountRedemption = `
+	INSERT INTO discount_redemption (
+		discount_id,
+		source,
+		source_id,
+		created_at
+	) VALUES (
+		:discount_id,
+		:source,
+		:source_id,
+		:created_at
+	)
+	ON CONFLICT (discount_id, source, source_id) DO NOTHING
+`
+
+// CreateDiscountRedemption records the redemption, returning whether it was
+// recorded. A purchase is only recorded once per discount.
+func (q *Queries) CreateDiscountRedemption(ctx context.Context, arg DiscountRedemption) (bool, error) {
+	rows, err := NamedExecRowsAffectedContext(ctx, q.db, createDiscountRedemption, arg)
+	return rows > 0, err
+}
@@ -0,0 +1,137 @@
+package repository
+
+import "context"
+
+const createDiscountReservation = `
+	INSERT INTO discount_reservation (
+		id,
+		discount_id,
+		user_id,
+		checkout_external_id,
+		expires_at,
+		created_at
+	) VALUES (
+		:id,
+		:discount_id,
+		:user_id,
+		:checkout_external_id,
+		:expires_at,
+		:created_at
+	)
+`
+
+func (q *Queries) CreateDiscountReservation(ctx context.Context, arg DiscountReservation) error {
+	return NamedExecOneRowContext(ctx, q.db, createDiscountReservation, arg)
+}
+
+const getDiscountReservationListByDiscountIDAndUserID = `
+	SELECT
+		*
+	FROM
+		discount_reservation
+	WHERE
+		discount_id = :discount_id AND
+		user_id = :user_id
+	ORDER BY
+		created_at ASC,
+		id ASC
+`
+
+type GetDiscountReservationListByDiscountIDAndUserIDParams struct {
+	DiscountID string `db:"discount_id"`
+	UserID     string `db:"user_id"`
+}
+
+// GetDiscountReservationListByDiscountIDAndUserID returns the reservations of
+// the discount by the user, oldest first.
+func (q *Queries) GetDiscountReservationListByDiscountIDAndUserID(ctx context.Context, arg GetDiscountReservationListByDiscountIDAndUserIDParams) ([]DiscountReservation, error) {
+	items := []DiscountReservation{}
+	err := NamedSelectContext(ctx, q.db, &items, getDiscountReservationListByDiscountIDAndUserID, arg)
+	return items, err
+}
+
+const getDiscountReservationByCheckoutExternalID = `
+	SELECT
+		*
+	FROM
+		discount_reservation
+	WHERE
+		checkout_external_id = :checkout_external_id
+`
+
+type GetDiscountReservationByCheckoutExternalIDParams struct {
+	CheckoutExternalID string `db:"checkout_external_id"`
+}
+
+func (q *Queries) GetDiscountReservationByCheckoutExternalID(ctx context.Context, checkoutExternalID string) ([]DiscountReservation, error) {
+	items := []DiscountReservation{}
+	err := NamedSelectContext(ctx, q.db, &items, getDiscountReservationByCheckoutExternalID, GetDiscountReservationByCheckoutExternalIDParams{CheckoutExternalID: checkoutExternalID})
+	return items, err
+}
+
+const getExpiredDiscountReservationList = `
+	SELECT
+		*
+	FROM
+		discount_reservation
+	WHERE
+		expires_at <= :now
+	ORDER BY
+		expires_at ASC,
+		id ASC
+	LIMIT
+		:page_size
+`
+
+type GetExpiredDiscountReservationListParams struct {
+	Now      string `db:"now"`
+	PageSize int    `db:"page_size"`
+}
+
+// GetExpiredDiscountReservationList returns the reservations that expired
+// before the given time, oldest first.
+func (q *Queries) GetExpiredDiscountReservationList(ctx context.Context, arg GetExpiredDiscountReservationListParams) ([]DiscountReservation, error) {
+	items := []DiscountReservation{}
+	err := NamedSelectContext(ctx, q.db, &items, getExpiredDiscountReservationList, arg)
+	return items, err
+}
+
+const updateDiscountReservationCheckoutByID = `
+	UPDATE
+		discount_reservation
+	SET
+		checkout_external_id = :checkout_external_id,
+		expires_at = :expires_at
+	WHERE
+		id = :id
+`
+
+type UpdateDiscountReservationCheckoutByIDParams struct {
+	CheckoutExternalID string `db:"checkout_external_id"`
+	ExpiresAt          string `db:"expires_at"`
+	ID                 string `db:"id"`
+}
+
+// UpdateDiscountReservationCheckoutByID links the reservation to the checkout
+// it was made for, and expires it with the checkout. No row is affected if the
+// reservation has been released meanwhile.
+func (q *Queries) UpdateDiscountReservationCheckoutByID(ctx context.Context, arg UpdateDiscountReservationCheckoutByIDParams) (int64, error) {
+	return NamedExecRowsAffectedContext(ctx, q.db, updateDiscountReservationCheckoutByID, arg)
+}
+
+const deleteDiscountReservationByID = `
+	DELETE FROM
+		discount_reservation
+	WHERE
+		id = :id
+`
+
+type DeleteDiscountReservationByIDParams struct {
+	ID string `db:"id"`
+}
+
+// DeleteDiscountReservationByID returns the number of rows deleted, which is 0
+// if the reservation has already been released or redeemed.
+func (q *Queries) DeleteDiscountReservationByID(ctx context.Context, id string) (int64, error) {
+	return NamedExecRowsAffectedContext(ctx, q.db, deleteDiscountReservationByID, DeleteDiscountReservationByIDParams{ID: id})
+}
@@ -14,7 +14,8 @@ const createEmail = `
 		body,
 		status,
 		created_at,
-		updated_at
+		updated_at,
+		list_unsubscribe_url
 	) VALUES (
 		:id,
 		:type,
@@ -26,7 +27,8 @@ const createEmail = `
 		:body,
 		:status,
 		:created_at,
-		:updated_at
+		:updated_at,
+		:list_unsubscribe_url
 	)
 	RETURNING
 		*
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jljl1337/issho/internal/template"
+)
+
+const createEntitlement = `
+	INSERT INTO entitlement (
+		id,
+		user_id,
+		product_id,
+		source,
+		source_id,
+		starts_at,
+		ends_at,
+		created_at,
+		updated_at
+	) VALUES (
+		:id,
+		:user_id,
+		:product_id,
+		:source,
+		:source_id,
+		:starts_at,
+		:ends_at,
+		:created_at,
+		:updated_at
+	)
+`
+
+func (q *Queries) CreateEntitlement(ctx context.Context, arg Entitlement) error {
+	return NamedExecOneRowContext(ctx, q.db, createEntitlement, arg)
+}
+
+const upsertEntitlementBySource = `
+	INSERT INTO entitlement (
+		id,
+		user_id,
+		product_id,
+		source,
+		source_id,
+		starts_at,
+		ends_at,
+		created_at,
+		updated_at
+	) VALUES (
+		:id,
+		:user_id,
+		:product_id,
+		:source,
+		:source_id,
+		:starts_at,
+		:ends_at,
+		:created_at,
+		:updated_at
+	)
+	ON CONFLICT (source, source_id) DO UPDATE SET
+		product_id = excluded.product_id,
+		starts_at = excluded.starts_at,
+		ends_at = excluded.ends_at,
+		updated_at = excluded.updated_at
+`
+
+// UpsertEntitlementBySource creates or updates the entitlement granted by the
+// source, e.g. a subscription, so that each source grants one entitlement.
+func (q *Queries) UpsertEntitlementBySource(ctx context.Context, arg Entitlement) error {
+	return NamedExecOneRowContext(ctx, q.db, upsertEntitlementBySource, arg)
+}
+
+const endEntitlementBySource = `
+	UPDATE
+		entitlement
+	SET
+		ends_at = :ends_at,
+		updated_at = :updated_at
+	WHERE
+		source = :source AND
+		source_id = :source_id AND
+		(ends_at IS NULL OR ends_at > :ends_at)
+`
+
+type EndEntitlementBySourceParams struct {
+	Source    string `db:"source"`
+	SourceID  string `db:"source_id"`
+	EndsAt    string `db:"ends_at"`
+	UpdatedAt string `db:"updated_at"`
+}
+
+// EndEntitlementBySource ends the entitlement granted by the source, unless it
+// has already ended earlier.
+func (q *Queries) EndEntitlementBySource(ctx context.Context, arg EndEntitlementBySourceParams) error {
+	_, err := NamedExecRowsAffectedContext(ctx, q.db, endEntitlementBySource, arg)
+	return err
+}
+
+const getEntitlementList = `
+	SELECT
+		*
+	FROM
+		entitlement
+	WHERE
+		(:user_id IS NULL OR user_id = :user_id) AND
+		(:product_id IS NULL OR product_id = :product_id) AND (
+			:cursor IS NULL OR :cursor_id IS NULL OR
+			created_at {{if .Ascending}}>{{else}}<{{end}} :cursor OR (
+				created_at = :cursor AND id {{if .Ascending}}>{{else}}<{{end}} :cursor_id
+			)
+		)
+	ORDER BY
+		created_at {{if .Ascending}}ASC{{else}}DESC{{end}},
+		id {{if .Ascending}}ASC{{else}}DESC{{end}}
+	LIMIT
+		:page_size
+`
+
+type GetEntitlementListParams struct {
+	UserID    *string `db:"user_id"`
+	ProductID *string `db:"product_id"`
+	Ascending bool    // not a db tag, used for formatting
+	PageSize  int     `db:"page_size"`
+	Cursor    *string `db:"cursor"`
+	CursorID  *string `db:"cursor_id"`
+}
+
+func (q *Queries) GetEntitlementList(ctx context.Context, arg GetEntitlementListParams) ([]Entitlement, error) {
+	query, err := template.RenderTemplate(getEntitlementList, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render query template: %w", err)
+	}
+
+	items := []Entitlement{}
+	err = NamedSelectContext(ctx, q.db, &items, query, arg)
+	return items, err
+}
+
+const getActiveEntitlementListByUserID = `
+	SELECT
+		*
+	FROM
+		entitlement
+	WHERE
+		user_id = :user_id AND
+		starts_at <= :now AND
+		(ends_at IS NULL OR ends_at > :now)
+`
+
+type GetActiveEntitlementListByUserIDParams struct {
+	UserID string `db:"user_id"`
+	Now    string `db:"now"`
+}
+
+func (q *Queries) GetActiveEntitlementListByUserID(ctx context.Context, arg GetActiveEntitlementListByUserIDParams) ([]Entitlement, error) {
+	items := []Entitlement{}
+	err := NamedSelectContext(ctx, q.db, &items, getActiveEntitlementListByUserID, arg)
+	return items, err
+}
+
+const getActiveEntitlementListByUserIDAndProductID = `
+	SELECT
+		*
+	FROM
+		entitlement
+	WHERE
+		user_id = :user_id AND
+		product_id = :product_id AND
+		starts_at <= :now AND
+		(ends_at IS NULL OR ends_at > :now)
+`
+
+type GetActiveEntitlementListByUserIDAndProductIDParams struct {
+	UserID    string `db:"user_id"`
+	ProductID string `db:"product_id"`
+	Now       string `db:"now"`
+}
+
+func (q *Queries) GetActiveEntitlementListByUserIDAndProductID(ctx context.Context, arg GetActiveEntitlementListByUserIDAndProductIDParams) ([]Entitlement, error) {
+	items := []Entitlement{}
+	err := NamedSelectContext(ctx, q.db, &items, getActiveEntitlementListByUserIDAndProductID, arg)
+	return items, err
+}
+
+const getEntitlementByID = `
+	SELECT
+		*
+	FROM
+		entitlement
+	WHERE
+		id = :id
+`
+
+type GetEntitlementByIDParams struct {
+	ID string `db:"id"`
+}
+
+func (q *Queries) GetEntitlementByID(ctx context.Context, id string) ([]Entitlement, error) {
+	items := []Entitlement{}
+	err := NamedSelectContext(ctx, q.db, &items, getEntitlementByID, GetEntitlementByIDParams{ID: id})
+	return items, err
+}
+
+const deleteEntitlementByID = `
+	DELETE FROM
+		entitlement
+	WHERE
+		id = :id
+`
+
+type DeleteEntitlementByIDParams struct {
+	ID string `db:"id"`
+}
+
+func (q *Queries) DeleteEntitlementByID(ctx context.Context, id string) error {
+	return NamedExecOneRowContext(ctx, q.db, deleteEntitlementByID, DeleteEntitlementByIDParams{ID: id})
+}
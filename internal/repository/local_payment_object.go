@@ -0,0 +1,131 @@
+package repository
+
+import "context"
+
+const createLocalPaymentObject = `
+	INSERT INTO local_payment_object (
+		id,
+		type,
+		customer_id,
+		data,
+		created_at,
+		updated_at
+	) VALUES (
+		:id,
+		:type,
+		:customer_id,
+		:data,
+		:created_at,
+		:updated_at
+	)
+`
+
+func (q *Queries) CreateLocalPaymentObject(ctx context.Context, arg LocalPaymentObject) error {
+	return NamedExecOneRowContext(ctx, q.db, createLocalPaymentObject, arg)
+}
+
+const getLocalPaymentObjectByTypeAndID = `
+	SELECT
+		*
+	FROM
+		local_payment_object
+	WHERE
+		type = :type AND
+		id = :id
+`
+
+type GetLocalPaymentObjectByTypeAndIDParams struct {
+	Type string `db:"type"`
+	ID   string `db:"id"`
+}
+
+func (q *Queries) GetLocalPaymentObjectByTypeAndID(ctx context.Context, arg GetLocalPaymentObjectByTypeAndIDParams) ([]LocalPaymentObject, error) {
+	items := []LocalPaymentObject{}
+	err := NamedSelectContext(ctx, q.db, &items, getLocalPaymentObjectByTypeAndID, arg)
+	return items, err
+}
+
+const getLocalPaymentObjectListByTypeAndCustomerID = `
+	SELECT
+		*
+	FROM
+		local_payment_object
+	WHERE
+		type = :type AND
+		customer_id = :customer_id
+	ORDER BY
+		created_at ASC,
+		id ASC
+`
+
+type GetLocalPaymentObjectListByTypeAndCustomerIDParams struct {
+	Type       string `db:"type"`
+	CustomerID string `db:"customer_id"`
+}
+
+func (q *Queries) GetLocalPaymentObjectListByTypeAndCustomerID(ctx context.Context, arg GetLocalPaymentObjectListByTypeAndCustomerIDParams) ([]LocalPaymentObject, error) {
+	items := []LocalPaymentObject{}
+	err := NamedSelectContext(ctx, q.db, &items, getLocalPaymentObjectListByTypeAndCustomerID, arg)
+	return items, err
+}
+
+const getLocalPaymentObjectListByType = `
+	SELECT
+		*
+	FROM
+		local_payment_object
+	WHERE
+		type = :type
+	ORDER BY
+		created_at ASC,
+		id ASC
+`
+
+type GetLocalPaymentObjectListByTypeParams struct {
+	Type string `db:"type"`
+}
+
+func (q *Queries) GetLocalPaymentObjectListByType(ctx context.Context, objectType string) ([]LocalPaymentObject, error) {
+	items := []LocalPaymentObject{}
+	err := NamedSelectContext(ctx, q.db, &items, getLocalPaymentObjectListByType, GetLocalPaymentObjectListByTypeParams{Type: objectType})
+	return items, err
+}
+
+const updateLocalPaymentObjectData = `
+	UPDATE
+		local_payment_object
+	SET
+		data = :data,
+		updated_at = :updated_at
+	WHERE
+		type = :type AND
+		id = :id
+`
+
+type UpdateLocalPaymentObjectDataParams struct {
+	Type      string `db:"type"`
+	ID        string `db:"id"`
+	Data      string `db:"data"`
+	UpdatedAt string `db:"updated_at"`
+}
+
+func (q *Queries) UpdateLocalPaymentObjectData(ctx context.Context, arg UpdateLocalPaymentObjectDataParams) error {
+	return NamedExecOneRowContext(ctx, q.db, updateLocalPaymentObjectData, arg)
+}
+
+const deleteLocalPaymentObject = `
+	DELETE FROM
+		local_payment_object
+	WHERE
+		type = :type AND
+		id = :id
+`
+
+type DeleteLocalPaymentObjectParams struct {
+	Type string `db:"type"`
+	ID   string `db:"id"`
+}
+
+func (q *Queries) DeleteLocalPaymentObject(ctx context.Context, arg DeleteLocalPaymentObjectParams) error {
+	return NamedExecOneRowContext(ctx, q.db, deleteLocalPaymentObject, arg)
+}
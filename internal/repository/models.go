@@ -8,16 +8,17 @@ type Migration struct {
 }
 
 type User struct {
-	ID           string  `json:"id" db:"id"`
-	ExternalID   *string `json:"externalId" db:"external_id"`
-	Username     string  `json:"username" db:"username"`
-	Email        string  `json:"email" db:"email"`
-	PasswordHash string  `json:"passwordHash" db:"password_hash"`
-	Role         string  `json:"role" db:"role"`
-	LanguageCode string  `json:"languageCode" db:"language_code"`
-	IsVerified   bool    `json:"isVerified" db:"is_verified"`
-	CreatedAt    string  `json:"createdAt" db:"created_at"`
-	UpdatedAt    string  `json:"updatedAt" db:"updated_at"`
+	ID                string  `json:"id" db:"id"`
+	ExternalID        *string `json:"externalId" db:"external_id"`
+	Username          string  `json:"username" db:"username"`
+	Email             string  `json:"email" db:"email"`
+	PasswordHash      string  `json:"passwordHash" db:"password_hash"`
+	Role              string  `json:"role" db:"role"`
+	LanguageCode      string  `json:"languageCode" db:"language_code"`
+	IsVerified        bool    `json:"isVerified" db:"is_verified"`
+	PaymentSyncStatus string  `json:"paymentSyncStatus" db:"payment_sync_status"`
+	CreatedAt         string  `json:"createdAt" db:"created_at"`
+	UpdatedAt         string  `json:"updatedAt" db:"updated_at"`
 }
 
 type Session struct {
@@ -31,14 +32,18 @@ type Session struct {
 }
 
 type Post struct {
-	ID          string  `json:"id" db:"id"`
-	UserID      *string `json:"userID" db:"user_id"`
-	Title       string  `json:"title" db:"title"`
-	Description string  `json:"description" db:"description"`
-	Content     string  `json:"content" db:"content"`
-	PublishedAt *string `json:"publishedAt" db:"published_at"`
-	CreatedAt   string  `json:"createdAt" db:"created_at"`
-	UpdatedAt   string  `json:"updatedAt" db:"updated_at"`
+	ID           string  `json:"id" db:"id"`
+	UserID       *string `json:"userID" db:"user_id"`
+	Title        string  `json:"title" db:"title"`
+	Description  string  `json:"description" db:"description"`
+	Content      string  `json:"content" db:"content"`
+	PublishedAt  *string `json:"publishedAt" db:"published_at"`
+	Visibility   string  `json:"visibility" db:"visibility"`
+	LanguageCode string  `json:"languageCode" db:"language_code"`
+	DeletedAt    *string `json:"deletedAt" db:"deleted_at"`
+	Slug         *string `json:"slug" db:"slug"`
+	CreatedAt    string  `json:"createdAt" db:"created_at"`
+	UpdatedAt    string  `json:"updatedAt" db:"updated_at"`
 }
 
 type Product struct {
@@ -63,17 +68,20 @@ type Price struct {
 	RecurringInterval      *string `json:"recurringInterval" db:"recurring_interval"`
 	RecurringIntervalCount *int    `json:"recurringIntervalCount" db:"recurring_interval_count"`
 	IsActive               bool    `json:"isActive" db:"is_active"`
+	PaymentSyncStatus      string  `json:"paymentSyncStatus" db:"payment_sync_status"`
 	CreatedAt              string  `json:"createdAt" db:"created_at"`
 	UpdatedAt              string  `json:"updatedAt" db:"updated_at"`
 }
 
 type QueueTask struct {
-	ID        string `json:"id" db:"id"`
-	Lane      string `json:"lane" db:"lane"`
-	Payload   string `json:"payload" db:"payload"`
-	Status    string `json:"status" db:"status"`
-	CreatedAt string `json:"createdAt" db:"created_at"`
-	UpdatedAt string `json:"updatedAt" db:"updated_at"`
+	ID           string  `json:"id" db:"id"`
+	Lane         string  `json:"lane" db:"lane"`
+	Payload      string  `json:"payload" db:"payload"`
+	Status       string  `json:"status" db:"status"`
+	AttemptCount int     `json:"attemptCount" db:"attempt_count"`
+	RunAt        *string `json:"runAt" db:"run_at"`
+	CreatedAt    string  `json:"createdAt" db:"created_at"`
+	UpdatedAt    string  `json:"updatedAt" db:"updated_at"`
 }
 
 type Email struct {
@@ -88,6 +96,8 @@ type Email struct {
 	Status      string `json:"status" db:"status"`
 	CreatedAt   string `json:"createdAt" db:"created_at"`
 	UpdatedAt   string `json:"updatedAt" db:"updated_at"`
+	// ListUnsubscribeURL is set for bulk emails that can be unsubscribed from
+	ListUnsubscribeURL *string `json:"listUnsubscribeURL" db:"list_unsubscribe_url"`
 }
 
 type EmailVerification struct {
@@ -101,3 +111,276 @@ type EmailVerification struct {
 	CreatedAt string `json:"createdAt" db:"created_at"`
 	UpdatedAt string `json:"updatedAt" db:"updated_at"`
 }
+
+type Comment struct {
+	ID        string  `json:"id" db:"id"`
+	PostID    string  `json:"postID" db:"post_id"`
+	UserID    *string `json:"userID" db:"user_id"`
+	ParentID  *string `json:"parentID" db:"parent_id"`
+	Content   string  `json:"content" db:"content"`
+	Status    string  `json:"status" db:"status"`
+	DeletedAt *string `json:"deletedAt" db:"deleted_at"`
+	CreatedAt string  `json:"createdAt" db:"created_at"`
+	UpdatedAt string  `json:"updatedAt" db:"updated_at"`
+}
+
+type PostTag struct {
+	PostID    string `json:"postID" db:"post_id"`
+	Tag       string `json:"tag" db:"tag"`
+	CreatedAt string `json:"createdAt" db:"created_at"`
+}
+
+type Attachment struct {
+	ID                  string  `json:"id" db:"id"`
+	PostID              string  `json:"postID" db:"post_id"`
+	UserID              *string `json:"userID" db:"user_id"`
+	FileName            string  `json:"fileName" db:"file_name"`
+	ContentType         string  `json:"contentType" db:"content_type"`
+	SizeBytes           int64   `json:"sizeBytes" db:"size_bytes"`
+	Width               *int    `json:"width" db:"width"`
+	Height              *int    `json:"height" db:"height"`
+	StorageKey          string  `json:"-" db:"storage_key"`
+	ThumbnailStorageKey *string `json:"-" db:"thumbnail_storage_key"`
+	CreatedAt           string  `json:"createdAt" db:"created_at"`
+	UpdatedAt           string  `json:"updatedAt" db:"updated_at"`
+}
+
+type PostProduct struct {
+	PostID    string `json:"postID" db:"post_id"`
+	ProductID string `json:"productID" db:"product_id"`
+	CreatedAt string `json:"createdAt" db:"created_at"`
+}
+
+type Entitlement struct {
+	ID        string  `json:"id" db:"id"`
+	UserID    string  `json:"userID" db:"user_id"`
+	ProductID string  `json:"productID" db:"product_id"`
+	Source    string  `json:"source" db:"source"`
+	SourceID  *string `json:"sourceID" db:"source_id"`
+	StartsAt  string  `json:"startsAt" db:"starts_at"`
+	EndsAt    *string `json:"endsAt" db:"ends_at"`
+	CreatedAt string  `json:"createdAt" db:"created_at"`
+	UpdatedAt string  `json:"updatedAt" db:"updated_at"`
+}
+
+type PostTranslation struct {
+	PostID       string `json:"postID" db:"post_id"`
+	LanguageCode string `json:"languageCode" db:"language_code"`
+	Title        string `json:"title" db:"title"`
+	Description  string `json:"description" db:"description"`
+	Content      string `json:"content" db:"content"`
+	CreatedAt    string `json:"createdAt" db:"created_at"`
+	UpdatedAt    string `json:"updatedAt" db:"updated_at"`
+}
+
+type PostCollaborator struct {
+	PostID    string `json:"postID" db:"post_id"`
+	UserID    string `json:"userID" db:"user_id"`
+	Role      string `json:"role" db:"role"`
+	CreatedAt string `json:"createdAt" db:"created_at"`
+	UpdatedAt string `json:"updatedAt" db:"updated_at"`
+}
+
+type Series struct {
+	ID          string  `json:"id" db:"id"`
+	UserID      *string `json:"userID" db:"user_id"`
+	Title       string  `json:"title" db:"title"`
+	Description string  `json:"description" db:"description"`
+	Slug        string  `json:"slug" db:"slug"`
+	CreatedAt   string  `json:"createdAt" db:"created_at"`
+	UpdatedAt   string  `json:"updatedAt" db:"updated_at"`
+}
+
+type SeriesPost struct {
+	SeriesID  string `json:"seriesID" db:"series_id"`
+	PostID    string `json:"postID" db:"post_id"`
+	Position  int    `json:"position" db:"position"`
+	CreatedAt string `json:"createdAt" db:"created_at"`
+}
+
+type PostView struct {
+	PostID      string `json:"postID" db:"post_id"`
+	ViewDate    string `json:"viewDate" db:"view_date"`
+	VisitorHash string `json:"-" db:"visitor_hash"`
+	CreatedAt   string `json:"createdAt" db:"created_at"`
+}
+
+type PostViewDaily struct {
+	PostID    string `json:"postID" db:"post_id"`
+	ViewDate  string `json:"viewDate" db:"view_date"`
+	ViewCount int64  `json:"viewCount" db:"view_count"`
+	CreatedAt string `json:"createdAt" db:"created_at"`
+	UpdatedAt string `json:"updatedAt" db:"updated_at"`
+}
+
+type PostViewCount struct {
+	ViewDate  string `json:"viewDate" db:"view_date"`
+	ViewCount int64  `json:"viewCount" db:"view_count"`
+}
+
+type PostViewTotal struct {
+	PostID    string `json:"postID" db:"post_id"`
+	ViewCount int64  `json:"viewCount" db:"view_count"`
+}
+
+type Bookmark struct {
+	UserID    string `json:"userID" db:"user_id"`
+	PostID    string `json:"postID" db:"post_id"`
+	CreatedAt string `json:"createdAt" db:"created_at"`
+}
+
+type BookmarkedPost struct {
+	Post
+	BookmarkedAt string `json:"bookmarkedAt" db:"bookmarked_at"`
+}
+
+type PostReaction struct {
+	PostID    string `json:"postID" db:"post_id"`
+	UserID    string `json:"userID" db:"user_id"`
+	Type      string `json:"type" db:"type"`
+	CreatedAt string `json:"createdAt" db:"created_at"`
+}
+
+type PostReactionCount struct {
+	PostID string `json:"postID" db:"post_id"`
+	Type   string `json:"type" db:"type"`
+	Count  int64  `json:"count" db:"count"`
+}
+
+type NewsletterSubscription struct {
+	ID        string  `json:"id" db:"id"`
+	UserID    *string `json:"userID" db:"user_id"`
+	Email     *string `json:"email" db:"email"`
+	Token     string  `json:"-" db:"token"`
+	Status    string  `json:"status" db:"status"`
+	CreatedAt string  `json:"createdAt" db:"created_at"`
+	UpdatedAt string  `json:"updatedAt" db:"updated_at"`
+}
+
+type NewsletterRecipient struct {
+	ID    string `json:"id" db:"id"`
+	Token string `json:"-" db:"token"`
+	Email string `json:"email" db:"email"`
+}
+
+type NewsletterIssue struct {
+	PostID         string `json:"postID" db:"post_id"`
+	RecipientCount int64  `json:"recipientCount" db:"recipient_count"`
+	CreatedAt      string `json:"createdAt" db:"created_at"`
+}
+
+type PaymentEvent struct {
+	ID        string  `json:"id" db:"id"`
+	Provider  string  `json:"provider" db:"provider"`
+	EventID   string  `json:"eventID" db:"event_id"`
+	Type      string  `json:"type" db:"type"`
+	Payload   string  `json:"payload" db:"payload"`
+	Status    string  `json:"status" db:"status"`
+	Error     *string `json:"error" db:"error"`
+	CreatedAt string  `json:"createdAt" db:"created_at"`
+	UpdatedAt string  `json:"updatedAt" db:"updated_at"`
+}
+
+type Subscription struct {
+	ID                 string  `json:"id" db:"id"`
+	ExternalID         string  `json:"externalId" db:"external_id"`
+	UserID             string  `json:"userID" db:"user_id"`
+	PriceID            string  `json:"priceID" db:"price_id"`
+	Status             string  `json:"status" db:"status"`
+	StartedAt          string  `json:"startedAt" db:"started_at"`
+	CurrentPeriodStart string  `json:"currentPeriodStart" db:"current_period_start"`
+	CurrentPeriodEnd   *string `json:"currentPeriodEnd" db:"current_period_end"`
+	CancelAtPeriodEnd  bool    `json:"cancelAtPeriodEnd" db:"cancel_at_period_end"`
+	CanceledAt         *string `json:"canceledAt" db:"canceled_at"`
+	EndedAt            *string `json:"endedAt" db:"ended_at"`
+	CreatedAt          string  `json:"createdAt" db:"created_at"`
+	UpdatedAt          string  `json:"updatedAt" db:"updated_at"`
+}
+
+type Order struct {
+	ID                     string  `json:"id" db:"id"`
+	ExternalID             string  `json:"externalId" db:"external_id"`
+	UserID                 string  `json:"userID" db:"user_id"`
+	PriceID                string  `json:"priceID" db:"price_id"`
+	SubscriptionExternalID *string `json:"subscriptionExternalId" db:"subscription_external_id"`
+	Status                 string  `json:"status" db:"status"`
+	Amount                 int     `json:"amount" db:"amount"`
+	Currency               string  `json:"currency" db:"currency"`
+	OrderedAt              string  `json:"orderedAt" db:"ordered_at"`
+	CreatedAt              string  `json:"createdAt" db:"created_at"`
+	UpdatedAt              string  `json:"updatedAt" db:"updated_at"`
+}
+
+type LocalPaymentObject struct {
+	ID         string  `json:"id" db:"id"`
+	Type       string  `json:"type" db:"type"`
+	CustomerID *string `json:"customerID" db:"customer_id"`
+	Data       string  `json:"data" db:"data"`
+	CreatedAt  string  `json:"createdAt" db:"created_at"`
+	UpdatedAt  string  `json:"updatedAt" db:"updated_at"`
+}
+
+type PaymentReconciliationIssue struct {
+	ID         string  `json:"id" db:"id"`
+	ObjectType string  `json:"objectType" db:"object_type"`
+	Code       string  `json:"code" db:"code"`
+	LocalID    *string `json:"localId" db:"local_id"`
+	ExternalID *string `json:"externalId" db:"external_id"`
+	Message    string  `json:"message" db:"message"`
+	IsFixed    bool    `json:"isFixed" db:"is_fixed"`
+	CreatedAt  string  `json:"createdAt" db:"created_at"`
+}
+
+type PaymentOutbox struct {
+	ID                 string  `json:"id" db:"id"`
+	Type               string  `json:"type" db:"type"`
+	UserID             *string `json:"userID" db:"user_id"`
+	PriceID            *string `json:"priceID" db:"price_id"`
+	CustomerExternalID *string `json:"customerExternalId" db:"customer_external_id"`
+	IdempotencyKey     string  `json:"idempotencyKey" db:"idempotency_key"`
+	Status             string  `json:"status" db:"status"`
+	Error              *string `json:"error" db:"error"`
+	CreatedAt          string  `json:"createdAt" db:"created_at"`
+	UpdatedAt          string  `json:"updatedAt" db:"updated_at"`
+}
+
+type Discount struct {
+	ID               string  `json:"id" db:"id"`
+	ExternalID       string  `json:"externalId" db:"external_id"`
+	Code             string  `json:"code" db:"code"`
+	Name             string  `json:"name" db:"name"`
+	Type             string  `json:"type" db:"type"`
+	Amount           int     `json:"amount" db:"amount"`
+	Currency         *string `json:"currency" db:"currency"`
+	Duration         string  `json:"duration" db:"duration"`
+	DurationInMonths *int    `json:"durationInMonths" db:"duration_in_months"`
+	RedemptionMax    *int    `json:"redemptionMax" db:"redemption_max"`
+	RedemptionCount  int     `json:"redemptionCount" db:"redemption_count"`
+	ReservedCount    int     `json:"reservedCount" db:"reserved_count"`
+	ExpiresAt        *string `json:"expiresAt" db:"expires_at"`
+	IsActive         bool    `json:"isActive" db:"is_active"`
+	CreatedAt        string  `json:"createdAt" db:"created_at"`
+	UpdatedAt        string  `json:"updatedAt" db:"updated_at"`
+}
+
+type DiscountPrice struct {
+	DiscountID string `json:"discountId" db:"discount_id"`
+	PriceID    string `json:"priceId" db:"price_id"`
+	CreatedAt  string `json:"createdAt" db:"created_at"`
+}
+
+type DiscountReservation struct {
+	ID                 string  `json:"id" db:"id"`
+	DiscountID         string  `json:"discountId" db:"discount_id"`
+	UserID             string  `json:"userId" db:"user_id"`
+	CheckoutExternalID *string `json:"checkoutExternalId" db:"checkout_external_id"`
+	ExpiresAt          string  `json:"expiresAt" db:"expires_at"`
+	CreatedAt          string  `json:"createdAt" db:"created_at"`
+}
+
+type DiscountRedemption struct {
+	DiscountID string `json:"discountId" db:"discount_id"`
+	Source     string `json:"source" db:"source"`
+	SourceID   string `json:"sourceId" db:"source_id"`
+	CreatedAt  string `json:"createdAt" db:"created_at"`
+}
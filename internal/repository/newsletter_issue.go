@@ -0,0 +1,52 @@
+package repository
+
+import "context"
+
+const createNewsletterIssue = `
+	INSERT INTO newsletter_issue (
+		post_id,
+		recipient_count,
+		created_at
+	) VALUES (
+		:post_id,
+		:recipient_count,
+		:created_at
+	)
+`
+
+func (q *Queries) CreateNewsletterIssue(ctx context.Context, arg NewsletterIssue) error {
+	return NamedExecOneRowContext(ctx, q.db, createNewsletterIssue, arg)
+}
+
+const getNewsletterPendingPostList = `
+	SELECT
+		post.*
+	FROM
+		post
+	LEFT JOIN
+		newsletter_issue ON newsletter_issue.post_id = post.id
+	WHERE
+		newsletter_issue.post_id IS NULL AND
+		post.deleted_at IS NULL AND
+		post.published_at IS NOT NULL AND
+		post.published_at > :published_after AND
+		post.published_at <= :now
+	ORDER BY
+		post.published_at ASC,
+		post.id ASC
+`
+
+type GetNewsletterPendingPostListParams struct {
+	PublishedAfter string `db:"published_after"`
+	Now            string `db:"now"`
+}
+
+// GetNewsletterPendingPostList returns the posts published in the period that
+// have not been sent to the newsletter subscribers yet.
+func (q *Queries) GetNewsletterPendingPostList(ctx context.Context, arg GetNewsletterPendingPostListParams) ([]Post, error) {
+	items := []Post{}
+
+	err := NamedSelectContext(ctx, q.db, &items, getNewsletterPendingPostList, arg)
+
+	return items, err
+}
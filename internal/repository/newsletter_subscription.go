@@ -0,0 +1,160 @@
+package repository
+
+import "context"
+
+const createNewsletterSubscription = `
+	INSERT INTO newsletter_subscription (
+		id,
+		user_id,
+		email,
+		token,
+		status,
+		created_at,
+		updated_at
+	) VALUES (
+		:id,
+		:user_id,
+		:email,
+		:token,
+		:status,
+		:created_at,
+		:updated_at
+	)
+`
+
+func (q *Queries) CreateNewsletterSubscription(ctx context.Context, arg NewsletterSubscription) error {
+	return NamedExecOneRowContext(ctx, q.db, createNewsletterSubscription, arg)
+}
+
+const getNewsletterSubscriptionByUserID = `
+	SELECT
+		*
+	FROM
+		newsletter_subscription
+	WHERE
+		user_id = :user_id
+`
+
+type GetNewsletterSubscriptionByUserIDParams struct {
+	UserID string `db:"user_id"`
+}
+
+func (q *Queries) GetNewsletterSubscriptionByUserID(ctx context.Context, userID string) ([]NewsletterSubscription, error) {
+	items := []NewsletterSubscription{}
+
+	err := NamedSelectContext(ctx, q.db, &items, getNewsletterSubscriptionByUserID, GetNewsletterSubscriptionByUserIDParams{UserID: userID})
+
+	return items, err
+}
+
+const getNewsletterSubscriptionByEmail = `
+	SELECT
+		*
+	FROM
+		newsletter_subscription
+	WHERE
+		email = :email
+`
+
+type GetNewsletterSubscriptionByEmailParams struct {
+	Email string `db:"email"`
+}
+
+func (q *Queries) GetNewsletterSubscriptionByEmail(ctx context.Context, email string) ([]NewsletterSubscription, error) {
+	items := []NewsletterSubscription{}
+
+	err := NamedSelectContext(ctx, q.db, &items, getNewsletterSubscriptionByEmail, GetNewsletterSubscriptionByEmailParams{Email: email})
+
+	return items, err
+}
+
+const getNewsletterSubscriptionByToken = `
+	SELECT
+		*
+	FROM
+		newsletter_subscription
+	WHERE
+		token = :token
+`
+
+type GetNewsletterSubscriptionByTokenParams struct {
+	Token string `db:"token"`
+}
+
+func (q *Queries) GetNewsletterSubscriptionByToken(ctx context.Context, token string) ([]NewsletterSubscription, error) {
+	items := []NewsletterSubscription{}
+
+	err := NamedSelectContext(ctx, q.db, &items, getNewsletterSubscriptionByToken, GetNewsletterSubscriptionByTokenParams{Token: token})
+
+	return items, err
+}
+
+const getNewsletterRecipientList = `
+	SELECT
+		newsletter_subscription.id,
+		newsletter_subscription.token,
+		COALESCE("user".email, newsletter_subscription.email) AS email
+	FROM
+		newsletter_subscription
+	LEFT JOIN
+		"user" ON "user".id = newsletter_subscription.user_id
+	WHERE
+		newsletter_subscription.status = :status AND
+		newsletter_subscription.id > :cursor_id
+	ORDER BY
+		newsletter_subscription.id ASC
+	LIMIT
+		:page_size
+`
+
+type GetNewsletterRecipientListParams struct {
+	Status   string `db:"status"`
+	CursorID string `db:"cursor_id"`
+	PageSize int    `db:"page_size"`
+}
+
+// GetNewsletterRecipientList returns the subscriptions with the status, and
+// the address to send to, which is the current email of the user for user
+// subscriptions. Pass an empty cursor ID for the first page.
+func (q *Queries) GetNewsletterRecipientList(ctx context.Context, arg GetNewsletterRecipientListParams) ([]NewsletterRecipient, error) {
+	items := []NewsletterRecipient{}
+
+	err := NamedSelectContext(ctx, q.db, &items, getNewsletterRecipientList, arg)
+
+	return items, err
+}
+
+const updateNewsletterSubscriptionStatusByID = `
+	UPDATE
+		newsletter_subscription
+	SET
+		status = :status,
+		updated_at = :updated_at
+	WHERE
+		id = :id
+`
+
+type UpdateNewsletterSubscriptionStatusByIDParams struct {
+	ID        string `db:"id"`
+	Status    string `db:"status"`
+	UpdatedAt string `db:"updated_at"`
+}
+
+func (q *Queries) UpdateNewsletterSubscriptionStatusByID(ctx context.Context, arg UpdateNewsletterSubscriptionStatusByIDParams) error {
+	return NamedExecOneRowContext(ctx, q.db, updateNewsletterSubscriptionStatusByID, arg)
+}
+
+const deleteNewsletterSubscriptionByID = `
+	DELETE FROM
+		newsletter_subscription
+	WHERE
+		id = :id
+`
+
+type DeleteNewsletterSubscriptionByIDParams struct {
+	ID string `db:"id"`
+}
+
+func (q *Queries) DeleteNewsletterSubscriptionByID(ctx context.Context, id string) error {
+	return NamedExecOneRowContext(ctx, q.db, deleteNewsletterSubscriptionByID, DeleteNewsletterSubscriptionByIDParams{ID: id})
+}
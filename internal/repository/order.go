@@ -0,0 +1,96 @@
+package repository
+
+import "context"
+
+const upsertOrder = `
+	INSERT INTO "order" (
+		id,
+		external_id,
+		user_id,
+		price_id,
+		subscription_external_id,
+		status,
+		amount,
+		currency,
+		ordered_at,
+		created_at,
+		updated_at
+	) VALUES (
+		:id,
+		:external_id,
+		:user_id,
+		:price_id,
+		:subscription_external_id,
+		:status,
+		:amount,
+		:currency,
+		:ordered_at,
+		:created_at,
+		:updated_at
+	)
+	ON CONFLICT (external_id) DO UPDATE SET
+		price_id = excluded.price_id,
+		subscription_external_id = excluded.subscription_external_id,
+		status = excluded.status,
+		amount = excluded.amount,
+		currency = excluded.currency,
+		ordered_at = excluded.ordered_at,
+		updated_at = excluded.updated_at
+`
+
+// UpsertOrder creates the order, or updates it to the latest state from the
+// payment provider if it already exists.
+func (q *Queries) UpsertOrder(ctx context.Context, arg Order) error {
+	return NamedExecOneRowContext(ctx, q.db, upsertOrder, arg)
+}
+
+const getOrderByExternalID = `
+	SELECT
+		*
+	FROM
+		"order"
+	WHERE
+		external_id = :external_id
+`
+
+type GetOrderByExternalIDParams struct {
+	ExternalID string `db:"external_id"`
+}
+
+func (q *Queries) GetOrderByExternalID(ctx context.Context, externalID string) ([]Order, error) {
+	items := []Order{}
+	err := NamedSelectContext(ctx, q.db, &items, getOrderByExternalID, GetOrderByExternalIDParams{ExternalID: externalID})
+	return items, err
+}
+
+const getOrderListByUserID = `
+	SELECT
+		*
+	FROM
+		"order"
+	WHERE
+		user_id = :user_id AND (
+			:cursor IS NULL OR :cursor_id IS NULL OR
+			ordered_at < :cursor OR (
+				ordered_at = :cursor AND id < :cursor_id
+			)
+		)
+	ORDER BY
+		ordered_at DESC,
+		id DESC
+	LIMIT
+		:page_size
+`
+
+type GetOrderListByUserIDParams struct {
+	UserID   string  `db:"user_id"`
+	Cursor   *string `db:"cursor"`
+	CursorID *string `db:"cursor_id"`
+	PageSize int     `db:"page_size"`
+}
+
+func (q *Queries) GetOrderListByUserID(ctx context.Context, arg GetOrderListByUserIDParams) ([]Order, error) {
+	items := []Order{}
+	err := NamedSelectContext(ctx, q.db, &items, getOrderListByUserID, arg)
+	return items, err
+}
@@ -0,0 +1,78 @@
+package repository
+
+import "context"
+
+const createPaymentEvent = `
+	INSERT INTO payment_event (
+		id,
+		provider,
+		event_id,
+		type,
+		payload,
+		status,
+		error,
+		created_at,
+		updated_at
+	) VALUES (
+		:id,
+		:provider,
+		:event_id,
+		:type,
+		:payload,
+		:status,
+		:error,
+		:created_at,
+		:updated_at
+	)
+	ON CONFLICT (provider, event_id) DO NOTHING
+`
+
+// CreatePaymentEvent stores the event, and returns false if an event with the
+// same ID from the provider is already stored.
+func (q *Queries) CreatePaymentEvent(ctx context.Context, arg PaymentEvent) (bool, error) {
+	rows, err := NamedExecRowsAffectedContext(ctx, q.db, createPaymentEvent, arg)
+	return rows > 0, err
+}
+
+const getPaymentEventByID = `
+	SELECT
+		*
+	FROM
+		payment_event
+	WHERE
+		id = :id
+`
+
+type GetPaymentEventByIDParams struct {
+	ID string `db:"id"`
+}
+
+func (q *Queries) GetPaymentEventByID(ctx context.Context, id string) ([]PaymentEvent, error) {
+	items := []PaymentEvent{}
+
+	err := NamedSelectContext(ctx, q.db, &items, getPaymentEventByID, GetPaymentEventByIDParams{ID: id})
+
+	return items, err
+}
+
+const updatePaymentEventStatusByID = `
+	UPDATE
+		payment_event
+	SET
+		status = :status,
+		error = :error,
+		updated_at = :updated_at
+	WHERE
+		id = :id
+`
+
+type UpdatePaymentEventStatusByIDParams struct {
+	ID        string  `db:"id"`
+	Status    string  `db:"status"`
+	Error     *string `db:"error"`
+	UpdatedAt string  `db:"updated_at"`
+}
+
+func (q *Queries) UpdatePaymentEventStatusByID(ctx context.Context, arg UpdatePaymentEventStatusByIDParams) error {
+	return NamedExecOneRowContext(ctx, q.db, updatePaymentEventStatusByID, arg)
+}
@@ -0,0 +1,116 @@
+package repository
+
+import "context"
+
+const createPaymentOutbox = `
+	INSERT INTO payment_outbox (
+		id,
+		type,
+		user_id,
+		price_id,
+		customer_external_id,
+		idempotency_key,
+		status,
+		error,
+		created_at,
+		updated_at
+	) VALUES (
+		:id,
+		:type,
+		:user_id,
+		:price_id,
+		:customer_external_id,
+		:idempotency_key,
+		:status,
+		:error,
+		:created_at,
+		:updated_at
+	)
+`
+
+func (q *Queries) CreatePaymentOutbox(ctx context.Context, arg PaymentOutbox) error {
+	return NamedExecOneRowContext(ctx, q.db, createPaymentOutbox, arg)
+}
+
+const getPaymentOutboxByID = `
+	SELECT
+		*
+	FROM
+		payment_outbox
+	WHERE
+		id = :id
+`
+
+type GetPaymentOutboxByIDParams struct {
+	ID string `db:"id"`
+}
+
+func (q *Queries) GetPaymentOutboxByID(ctx context.Context, id string) ([]PaymentOutbox, error) {
+	items := []PaymentOutbox{}
+	err := NamedSelectContext(ctx, q.db, &items, getPaymentOutboxByID, GetPaymentOutboxByIDParams{ID: id})
+	return items, err
+}
+
+const getPaymentOutboxCountByUserIDAndStatus = `
+	SELECT
+		COUNT(*)
+	FROM
+		payment_outbox
+	WHERE
+		user_id = :user_id AND
+		status = :status
+`
+
+type GetPaymentOutboxCountByUserIDAndStatusParams struct {
+	UserID string `db:"user_id"`
+	Status string `db:"status"`
+}
+
+func (q *Queries) GetPaymentOutboxCountByUserIDAndStatus(ctx context.Context, arg GetPaymentOutboxCountByUserIDAndStatusParams) (int, error) {
+	var count int
+	err := NamedGetContext(ctx, q.db, &count, getPaymentOutboxCountByUserIDAndStatus, arg)
+	return count, err
+}
+
+const getPaymentOutboxCountByPriceIDAndStatus = `
+	SELECT
+		COUNT(*)
+	FROM
+		payment_outbox
+	WHERE
+		price_id = :price_id AND
+		status = :status
+`
+
+type GetPaymentOutboxCountByPriceIDAndStatusParams struct {
+	PriceID string `db:"price_id"`
+	Status  string `db:"status"`
+}
+
+func (q *Queries) GetPaymentOutboxCountByPriceIDAndStatus(ctx context.Context, arg GetPaymentOutboxCountByPriceIDAndStatusParams) (int, error) {
+	var count int
+	err := NamedGetContext(ctx, q.db, &count, getPaymentOutboxCountByPriceIDAndStatus, arg)
+	return count, err
+}
+
+const updatePaymentOutboxStatusByID = `
+	UPDATE
+		payment_outbox
+	SET
+		status = :status,
+		error = :error,
+		updated_at = :updated_at
+	WHERE
+		id = :id
+`
+
+type UpdatePaymentOutboxStatusByIDParams struct {
+	ID        string  `db:"id"`
+	Status    string  `db:"status"`
+	Error     *string `db:"error"`
+	UpdatedAt string  `db:"updated_at"`
+}
+
+func (q *Queries) UpdatePaymentOutboxStatusByID(ctx context.Context, arg UpdatePaymentOutboxStatusByIDParams) error {
+	return NamedExecOneRowContext(ctx, q.db, updatePaymentOutboxStatusByID, arg)
+}
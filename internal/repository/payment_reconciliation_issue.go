@@ -0,0 +1,54 @@
+package repository
+
+import "context"
+
+const createPaymentReconciliationIssue = `
+	INSERT INTO payment_reconciliation_issue (
+		id,
+		object_type,
+		code,
+		local_id,
+		external_id,
+		message,
+		is_fixed,
+		created_at
+	) VALUES (
+		:id,
+		:object_type,
+		:code,
+		:local_id,
+		:external_id,
+		:message,
+		:is_fixed,
+		:created_at
+	)
+`
+
+func (q *Queries) CreatePaymentReconciliationIssue(ctx context.Context, arg PaymentReconciliationIssue) error {
+	return NamedExecOneRowContext(ctx, q.db, createPaymentReconciliationIssue, arg)
+}
+
+const getPaymentReconciliationIssueList = `
+	SELECT
+		*
+	FROM
+		payment_reconciliation_issue
+	ORDER BY
+		created_at ASC,
+		id ASC
+`
+
+func (q *Queries) GetPaymentReconciliationIssueList(ctx context.Context) ([]PaymentReconciliationIssue, error) {
+	items := []PaymentReconciliationIssue{}
+	err := NamedSelectContext(ctx, q.db, &items, getPaymentReconciliationIssueList, struct{}{})
+	return items, err
+}
+
+const deletePaymentReconciliationIssue = `
+	DELETE FROM
+		payment_reconciliation_issue
+`
+
+func (q *Queries) DeletePaymentReconciliationIssue(ctx context.Context) (int64, error) {
+	return NamedExecRowsAffectedContext(ctx, q.db, deletePaymentReconciliationIssue, struct{}{})
+}
@@ -15,6 +15,9 @@ const createPost = `
 		description,
 		content,
 		published_at,
+		visibility,
+		language_code,
+		slug,
 		created_at,
 		updated_at
 	) VALUES (
@@ -24,6 +27,9 @@ const createPost = `
 		:description,
 		:content,
 		:published_at,
+		:visibility,
+		:language_code,
+		:slug,
 		:created_at,
 		:updated_at
 	)
@@ -39,11 +45,18 @@ const getPostList = `
 	FROM
 		post
 	WHERE
+		deleted_at IS NULL AND
 		(:user_id IS NULL OR user_id = :user_id) AND
+		(:tag IS NULL OR id IN (
+			SELECT post_id FROM post_tag WHERE tag = :tag
+		)) AND
 		(:search_query IS NULL OR (
 			title LIKE '%%' || :search_query || '%%' OR
 			description LIKE '%%' || :search_query || '%%' OR
-			content LIKE '%%' || :search_query || '%%'
+			(
+				(:gated_visibility IS NULL OR visibility != :gated_visibility) AND
+				content LIKE '%%' || :search_query || '%%'
+			)
 		)) AND
 		(:include_all = TRUE OR (published_at IS NOT NULL AND published_at <= :now)) AND (
 			:cursor IS NULL OR :cursor_id IS NULL OR 
@@ -60,14 +73,17 @@ const getPostList = `
 
 type GetPostListParams struct {
 	UserID      *string `db:"user_id"`
+	Tag         *string `db:"tag"`
 	SearchQuery *string `db:"search_query"`
-	OrderBy     string  // not a db tag, used for formatting
-	Ascending   bool    // not a db tag, used for formatting
-	IncludeAll  bool    `db:"include_all"`
-	Now         string  `db:"now"`
-	PageSize    int     `db:"page_size"`
-	Cursor      *string `db:"cursor"`
-	CursorID    *string `db:"cursor_id"`
+	// Content of posts with this visibility is not searched, if set
+	GatedVisibility *string `db:"gated_visibility"`
+	OrderBy         string  // not a db tag, used for formatting
+	Ascending       bool    // not a db tag, used for formatting
+	IncludeAll      bool    `db:"include_all"`
+	Now             string  `db:"now"`
+	PageSize        int     `db:"page_size"`
+	Cursor          *string `db:"cursor"`
+	CursorID        *string `db:"cursor_id"`
 }
 
 func (q *Queries) GetPostList(ctx context.Context, arg GetPostListParams) ([]Post, error) {
@@ -85,13 +101,41 @@ func (q *Queries) GetPostList(ctx context.Context, arg GetPostListParams) ([]Pos
 	return items, err
 }
 
+const getAllPostList = `
+	SELECT
+		*
+	FROM
+		post
+	WHERE
+		deleted_at IS NULL AND
+		(:cursor_id IS NULL OR id > :cursor_id)
+	ORDER BY
+		id ASC
+	LIMIT
+		:page_size
+`
+
+type GetAllPostListParams struct {
+	CursorID *string `db:"cursor_id"`
+	PageSize int     `db:"page_size"`
+}
+
+// GetAllPostList returns the posts not in the trash, including the drafts, in
+// the order they were created.
+func (q *Queries) GetAllPostList(ctx context.Context, arg GetAllPostListParams) ([]Post, error) {
+	items := []Post{}
+	err := NamedSelectContext(ctx, q.db, &items, getAllPostList, arg)
+	return items, err
+}
+
 const getPostByID = `
 	SELECT
 		*
 	FROM
 		post
 	WHERE
-		id = :id
+		id = :id AND
+		deleted_at IS NULL
 `
 
 type GetPostByIDParams struct {
@@ -104,6 +148,80 @@ func (q *Queries) GetPostByID(ctx context.Context, id string) ([]Post, error) {
 	return items, err
 }
 
+const getPostListByIDs = `
+	SELECT
+		*
+	FROM
+		post
+	WHERE
+		id IN (:ids) AND
+		deleted_at IS NULL
+`
+
+type GetPostListByIDsParams struct {
+	IDs []string `db:"ids"`
+}
+
+func (q *Queries) GetPostListByIDs(ctx context.Context, ids []string) ([]Post, error) {
+	items := []Post{}
+	if len(ids) == 0 {
+		return items, nil
+	}
+
+	err := NamedSelectInContext(ctx, q.db, &items, getPostListByIDs, GetPostListByIDsParams{IDs: ids})
+	return items, err
+}
+
+const getPostListByIDsWithTrashed = `
+	SELECT
+		*
+	FROM
+		post
+	WHERE
+		id IN (:ids)
+`
+
+type GetPostListByIDsWithTrashedParams struct {
+	IDs []string `db:"ids"`
+}
+
+// GetPostListByIDsWithTrashed returns the posts with the IDs, including the
+// posts in the trash, which still hold their IDs.
+func (q *Queries) GetPostListByIDsWithTrashed(ctx context.Context, ids []string) ([]Post, error) {
+	items := []Post{}
+	if len(ids) == 0 {
+		return items, nil
+	}
+
+	err := NamedSelectInContext(ctx, q.db, &items, getPostListByIDsWithTrashed, GetPostListByIDsWithTrashedParams{IDs: ids})
+	return items, err
+}
+
+const getPostListBySlugs = `
+	SELECT
+		*
+	FROM
+		post
+	WHERE
+		slug IN (:slugs)
+`
+
+type GetPostListBySlugsParams struct {
+	Slugs []string `db:"slugs"`
+}
+
+// GetPostListBySlugs returns the posts with the slugs, including the posts in
+// the trash, which still hold their slugs.
+func (q *Queries) GetPostListBySlugs(ctx context.Context, slugs []string) ([]Post, error) {
+	items := []Post{}
+	if len(slugs) == 0 {
+		return items, nil
+	}
+
+	err := NamedSelectInContext(ctx, q.db, &items, getPostListBySlugs, GetPostListBySlugsParams{Slugs: slugs})
+	return items, err
+}
+
 const updatePostByID = `
 	UPDATE
 		post
@@ -112,22 +230,165 @@ const updatePostByID = `
 		description = :description,
 		content = :content,
 		published_at = :published_at,
+		visibility = :visibility,
+		language_code = :language_code,
+		slug = :slug,
 		updated_at = :updated_at
 	WHERE
-		id = :id
+		id = :id AND
+		(:version IS NULL OR updated_at = :version)
 `
 
 type UpdatePostByIDParams struct {
-	Title       string  `db:"title"`
-	Description string  `db:"description"`
-	Content     string  `db:"content"`
-	PublishedAt *string `db:"published_at"`
-	UpdatedAt   string  `db:"updated_at"`
-	ID          string  `db:"id"`
+	Title        string  `db:"title"`
+	Description  string  `db:"description"`
+	Content      string  `db:"content"`
+	PublishedAt  *string `db:"published_at"`
+	Visibility   string  `db:"visibility"`
+	LanguageCode string  `db:"language_code"`
+	Slug         *string `db:"slug"`
+	UpdatedAt    string  `db:"updated_at"`
+	ID           string  `db:"id"`
+	Version      *string `db:"version"`
+}
+
+func (q *Queries) UpdatePostByID(ctx context.Context, arg UpdatePostByIDParams) (int64, error) {
+	return NamedExecRowsAffectedContext(ctx, q.db, updatePostByID, arg)
+}
+
+const getTrashedPostList = `
+	SELECT
+		*
+	FROM
+		post
+	WHERE
+		deleted_at IS NOT NULL AND (
+			:user_id IS NULL OR id IN (
+				SELECT
+					post_id
+				FROM
+					post_collaborator
+				WHERE
+					user_id = :user_id AND
+					role = :role
+			)
+		) AND (
+			:cursor IS NULL OR :cursor_id IS NULL OR
+			deleted_at < :cursor OR (
+				deleted_at = :cursor AND id < :cursor_id
+			)
+		)
+	ORDER BY
+		deleted_at DESC,
+		id DESC
+	LIMIT
+		:page_size
+`
+
+type GetTrashedPostListParams struct {
+	UserID   *string `db:"user_id"`
+	Role     string  `db:"role"`
+	PageSize int     `db:"page_size"`
+	Cursor   *string `db:"cursor"`
+	CursorID *string `db:"cursor_id"`
+}
+
+// GetTrashedPostList returns the soft deleted posts, most recently deleted
+// first. If the user is given, only the posts the user collaborates on with
+// the role are returned.
+func (q *Queries) GetTrashedPostList(ctx context.Context, arg GetTrashedPostListParams) ([]Post, error) {
+	items := []Post{}
+	err := NamedSelectContext(ctx, q.db, &items, getTrashedPostList, arg)
+	return items, err
+}
+
+const getTrashedPostByID = `
+	SELECT
+		*
+	FROM
+		post
+	WHERE
+		id = :id AND
+		deleted_at IS NOT NULL
+`
+
+type GetTrashedPostByIDParams struct {
+	ID string `db:"id"`
+}
+
+func (q *Queries) GetTrashedPostByID(ctx context.Context, id string) ([]Post, error) {
+	items := []Post{}
+	err := NamedSelectContext(ctx, q.db, &items, getTrashedPostByID, GetTrashedPostByIDParams{ID: id})
+	return items, err
+}
+
+const getPurgeablePostList = `
+	SELECT
+		*
+	FROM
+		post
+	WHERE
+		deleted_at IS NOT NULL AND
+		deleted_at < :deleted_before
+	ORDER BY
+		deleted_at ASC,
+		id ASC
+	LIMIT
+		:page_size
+`
+
+type GetPurgeablePostListParams struct {
+	DeletedBefore string `db:"deleted_before"`
+	PageSize      int    `db:"page_size"`
+}
+
+// GetPurgeablePostList returns the posts that were soft deleted before the
+// given time, oldest first.
+func (q *Queries) GetPurgeablePostList(ctx context.Context, arg GetPurgeablePostListParams) ([]Post, error) {
+	items := []Post{}
+	err := NamedSelectContext(ctx, q.db, &items, getPurgeablePostList, arg)
+	return items, err
+}
+
+const softDeletePostByID = `
+	UPDATE
+		post
+	SET
+		deleted_at = :deleted_at,
+		updated_at = :updated_at
+	WHERE
+		id = :id AND
+		deleted_at IS NULL
+`
+
+type SoftDeletePostByIDParams struct {
+	DeletedAt string `db:"deleted_at"`
+	UpdatedAt string `db:"updated_at"`
+	ID        string `db:"id"`
+}
+
+func (q *Queries) SoftDeletePostByID(ctx context.Context, arg SoftDeletePostByIDParams) error {
+	return NamedExecOneRowContext(ctx, q.db, softDeletePostByID, arg)
+}
+
+const restorePostByID = `
+	UPDATE
+		post
+	SET
+		deleted_at = NULL,
+		updated_at = :updated_at
+	WHERE
+		id = :id AND
+		deleted_at IS NOT NULL
+`
+
+type RestorePostByIDParams struct {
+	UpdatedAt string `db:"updated_at"`
+	ID        string `db:"id"`
 }
 
-func (q *Queries) UpdatePostByID(ctx context.Context, arg UpdatePostByIDParams) error {
-	return NamedExecOneRowContext(ctx, q.db, updatePostByID, arg)
+func (q *Queries) RestorePostByID(ctx context.Context, arg RestorePostByIDParams) error {
+	return NamedExecOneRowContext(ctx, q.db, restorePostByID, arg)
 }
 
 const deletePostByID = `
@@ -144,3 +405,41 @@ type DeletePostByIDParams struct {
 func (q *Queries) DeletePostByID(ctx context.Context, id string) error {
 	return NamedExecOneRowContext(ctx, q.db, deletePostByID, DeletePostByIDParams{ID: id})
 }
+
+const updatePostUpdatedAtByID = `
+	UPDATE
+		post
+	SET
+		updated_at = :updated_at
+	WHERE
+		id = :id
+`
+
+type UpdatePostUpdatedAtByIDParams struct {
+	UpdatedAt string `db:"updated_at"`
+	ID        string `db:"id"`
+}
+
+func (q *Queries) UpdatePostUpdatedAtByID(ctx context.Context, arg UpdatePostUpdatedAtByIDParams) error {
+	return NamedExecOneRowContext(ctx, q.db, updatePostUpdatedAtByID, arg)
+}
+
+const updatePostUserIDByID = `
+	UPDATE
+		post
+	SET
+		user_id = :user_id,
+		updated_at = :updated_at
+	WHERE
+		id = :id
+`
+
+type UpdatePostUserIDByIDParams struct {
+	UserID    string `db:"user_id"`
+	UpdatedAt string `db:"updated_at"`
+	ID        string `db:"id"`
+}
+
+func (q *Queries) UpdatePostUserIDByID(ctx context.Context, arg UpdatePostUserIDByIDParams) error {
+	return NamedExecOneRowContext(ctx, q.db, updatePostUserIDByID, arg)
+}
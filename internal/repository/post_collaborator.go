@@ -0,0 +1,107 @@
+package repository
+
+import "context"
+
+const upsertPostCollaborator = `
+	INSERT INTO post_collaborator (
+		post_id,
+		user_id,
+		role,
+		created_at,
+		updated_at
+	) VALUES (
+		:post_id,
+		:user_id,
+		:role,
+		:created_at,
+		:updated_at
+	)
+	ON CONFLICT (post_id, user_id) DO UPDATE SET
+		role = excluded.role,
+		updated_at = excluded.updated_at
+`
+
+// UpsertPostCollaborator adds the collaborator, or changes the role of the
+// existing collaborator.
+func (q *Queries) UpsertPostCollaborator(ctx context.Context, arg PostCollaborator) error {
+	return NamedExecOneRowContext(ctx, q.db, upsertPostCollaborator, arg)
+}
+
+const getPostCollaboratorListByPostID = `
+	SELECT
+		*
+	FROM
+		post_collaborator
+	WHERE
+		post_id = :post_id
+	ORDER BY
+		created_at ASC,
+		user_id ASC
+`
+
+type GetPostCollaboratorListByPostIDParams struct {
+	PostID string `db:"post_id"`
+}
+
+func (q *Queries) GetPostCollaboratorListByPostID(ctx context.Context, postID string) ([]PostCollaborator, error) {
+	items := []PostCollaborator{}
+	err := NamedSelectContext(ctx, q.db, &items, getPostCollaboratorListByPostID, GetPostCollaboratorListByPostIDParams{PostID: postID})
+	return items, err
+}
+
+const getPostCollaborator = `
+	SELECT
+		*
+	FROM
+		post_collaborator
+	WHERE
+		post_id = :post_id AND
+		user_id = :user_id
+`
+
+type GetPostCollaboratorParams struct {
+	PostID string `db:"post_id"`
+	UserID string `db:"user_id"`
+}
+
+func (q *Queries) GetPostCollaborator(ctx context.Context, arg GetPostCollaboratorParams) ([]PostCollaborator, error) {
+	items := []PostCollaborator{}
+	err := NamedSelectContext(ctx, q.db, &items, getPostCollaborator, arg)
+	return items, err
+}
+
+const getPostCollaboratorListByUserID = `
+	SELECT
+		*
+	FROM
+		post_collaborator
+	WHERE
+		user_id = :user_id
+`
+
+type GetPostCollaboratorListByUserIDParams struct {
+	UserID string `db:"user_id"`
+}
+
+func (q *Queries) GetPostCollaboratorListByUserID(ctx context.Context, userID string) ([]PostCollaborator, error) {
+	items := []PostCollaborator{}
+	err := NamedSelectContext(ctx, q.db, &items, getPostCollaboratorListByUserID, GetPostCollaboratorListByUserIDParams{UserID: userID})
+	return items, err
+}
+
+const deletePostCollaborator = `
+	DELETE FROM
+		post_collaborator
+	WHERE
+		post_id = :post_id AND
+		user_id = :user_id
+`
+
+type DeletePostCollaboratorParams struct {
+	PostID string `db:"post_id"`
+	UserID string `db:"user_id"`
+}
+
+func (q *Queries) DeletePostCollaborator(ctx context.Context, arg DeletePostCollaboratorParams) (int64, error) {
+	return NamedExecRowsAffectedContext(ctx, q.db, deletePostCollaborator, arg)
+}
@@ -0,0 +1,60 @@
+package repository
+
+import "context"
+
+const createPostProduct = `
+	INSERT INTO post_product (
+		post_id,
+		product_id,
+		created_at
+	) VALUES (
+		:post_id,
+		:product_id,
+		:created_at
+	)
+`
+
+func (q *Queries) CreatePostProduct(ctx context.Context, arg PostProduct) error {
+	return NamedExecOneRowContext(ctx, q.db, createPostProduct, arg)
+}
+
+const getPostProductListByPostIDs = `
+	SELECT
+		*
+	FROM
+		post_product
+	WHERE
+		post_id IN (:post_ids)
+	ORDER BY
+		post_id ASC,
+		product_id ASC
+`
+
+type GetPostProductListByPostIDsParams struct {
+	PostIDs []string `db:"post_ids"`
+}
+
+func (q *Queries) GetPostProductListByPostIDs(ctx context.Context, postIDs []string) ([]PostProduct, error) {
+	items := []PostProduct{}
+	if len(postIDs) == 0 {
+		return items, nil
+	}
+
+	err := NamedSelectInContext(ctx, q.db, &items, getPostProductListByPostIDs, GetPostProductListByPostIDsParams{PostIDs: postIDs})
+	return items, err
+}
+
+const deletePostProductByPostID = `
+	DELETE FROM
+		post_product
+	WHERE
+		post_id = :post_id
+`
+
+type DeletePostProductByPostIDParams struct {
+	PostID string `db:"post_id"`
+}
+
+func (q *Queries) DeletePostProductByPostID(ctx context.Context, postID string) (int64, error) {
+	return NamedExecRowsAffectedContext(ctx, q.db, deletePostProductByPostID, DeletePostProductByPostIDParams{PostID: postID})
+}
@@ -0,0 +1,97 @@
+package repository
+
+import "context"
+
+const createPostReaction = `
+	INSERT INTO post_reaction (
+		post_id,
+		user_id,
+		type,
+		created_at
+	) VALUES (
+		:post_id,
+		:user_id,
+		:type,
+		:created_at
+	)
+	ON CONFLICT (post_id, user_id, type) DO NOTHING
+`
+
+// CreatePostReaction adds the reaction, keeping the existing reaction if the
+// user already reacted to the post with the type.
+func (q *Queries) CreatePostReaction(ctx context.Context, arg PostReaction) error {
+	_, err := NamedExecRowsAffectedContext(ctx, q.db, createPostReaction, arg)
+	return err
+}
+
+const getPostReactionCountListByPostIDs = `
+	SELECT
+		post_id,
+		type,
+		COUNT(*) AS count
+	FROM
+		post_reaction
+	WHERE
+		post_id IN (:post_ids)
+	GROUP BY
+		post_id,
+		type
+`
+
+type GetPostReactionCountListByPostIDsParams struct {
+	PostIDs []string `db:"post_ids"`
+}
+
+func (q *Queries) GetPostReactionCountListByPostIDs(ctx context.Context, postIDs []string) ([]PostReactionCount, error) {
+	items := []PostReactionCount{}
+	if len(postIDs) == 0 {
+		return items, nil
+	}
+
+	err := NamedSelectInContext(ctx, q.db, &items, getPostReactionCountListByPostIDs, GetPostReactionCountListByPostIDsParams{PostIDs: postIDs})
+	return items, err
+}
+
+const getPostReactionListByPostIDsAndUserID = `
+	SELECT
+		*
+	FROM
+		post_reaction
+	WHERE
+		post_id IN (:post_ids) AND
+		user_id = :user_id
+`
+
+type GetPostReactionListByPostIDsAndUserIDParams struct {
+	PostIDs []string `db:"post_ids"`
+	UserID  string   `db:"user_id"`
+}
+
+func (q *Queries) GetPostReactionListByPostIDsAndUserID(ctx context.Context, arg GetPostReactionListByPostIDsAndUserIDParams) ([]PostReaction, error) {
+	items := []PostReaction{}
+	if len(arg.PostIDs) == 0 {
+		return items, nil
+	}
+
+	err := NamedSelectInContext(ctx, q.db, &items, getPostReactionListByPostIDsAndUserID, arg)
+	return items, err
+}
+
+const deletePostReaction = `
+	DELETE FROM
+		post_reaction
+	WHERE
+		post_id = :post_id AND
+		user_id = :user_id AND
+		type = :type
+`
+
+type DeletePostReactionParams struct {
+	PostID string `db:"post_id"`
+	UserID string `db:"user_id"`
+	Type   string `db:"type"`
+}
+
+func (q *Queries) DeletePostReaction(ctx context.Context, arg DeletePostReactionParams) (int64, error) {
+	return NamedExecRowsAffectedContext(ctx, q.db, deletePostReaction, arg)
+}
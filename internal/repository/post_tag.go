@@ -0,0 +1,60 @@
+package repository
+
+import "context"
+
+const createPostTag = `
+	INSERT INTO post_tag (
+		post_id,
+		tag,
+		created_at
+	) VALUES (
+		:post_id,
+		:tag,
+		:created_at
+	)
+`
+
+func (q *Queries) CreatePostTag(ctx context.Context, arg PostTag) error {
+	return NamedExecOneRowContext(ctx, q.db, createPostTag, arg)
+}
+
+const getPostTagListByPostIDs = `
+	SELECT
+		*
+	FROM
+		post_tag
+	WHERE
+		post_id IN (:post_ids)
+	ORDER BY
+		post_id ASC,
+		tag ASC
+`
+
+type GetPostTagListByPostIDsParams struct {
+	PostIDs []string `db:"post_ids"`
+}
+
+func (q *Queries) GetPostTagListByPostIDs(ctx context.Context, postIDs []string) ([]PostTag, error) {
+	items := []PostTag{}
+	if len(postIDs) == 0 {
+		return items, nil
+	}
+
+	err := NamedSelectInContext(ctx, q.db, &items, getPostTagListByPostIDs, GetPostTagListByPostIDsParams{PostIDs: postIDs})
+	return items, err
+}
+
+const deletePostTagByPostID = `
+	DELETE FROM
+		post_tag
+	WHERE
+		post_id = :post_id
+`
+
+type DeletePostTagByPostIDParams struct {
+	PostID string `db:"post_id"`
+}
+
+func (q *Queries) DeletePostTagByPostID(ctx context.Context, postID string) (int64, error) {
+	return NamedExecRowsAffectedContext(ctx, q.db, deletePostTagByPostID, DeletePostTagByPostIDParams{PostID: postID})
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jljl1337/issho/internal/template"
+)
+
+const upsertPostTranslation = `
+	INSERT INTO post_translation (
+		post_id,
+		language_code,
+		title,
+		description,
+		content,
+		created_at,
+		updated_at
+	) VALUES (
+		:post_id,
+		:language_code,
+		:title,
+		:description,
+		:content,
+		:created_at,
+		:updated_at
+	)
+	ON CONFLICT (post_id, language_code) DO UPDATE SET
+		title = excluded.title,
+		description = excluded.description,
+		content = excluded.content,
+		updated_at = excluded.updated_at
+`
+
+// UpsertPostTranslation creates the translation, or replaces the text of the
+// existing translation in the same language.
+func (q *Queries) UpsertPostTranslation(ctx context.Context, arg PostTranslation) error {
+	return NamedExecOneRowContext(ctx, q.db, upsertPostTranslation, arg)
+}
+
+const getPostTranslationListByPostIDs = `
+	SELECT
+		*
+	FROM
+		post_translation
+	WHERE
+		post_id IN (:post_ids)
+	ORDER BY
+		post_id ASC,
+		language_code ASC
+`
+
+type GetPostTranslationListByPostIDsParams struct {
+	PostIDs []string `db:"post_ids"`
+}
+
+func (q *Queries) GetPostTranslationListByPostIDs(ctx context.Context, postIDs []string) ([]PostTranslation, error) {
+	items := []PostTranslation{}
+	if len(postIDs) == 0 {
+		return items, nil
+	}
+
+	err := NamedSelectInContext(ctx, q.db, &items, getPostTranslationListByPostIDs, GetPostTranslationListByPostIDsParams{PostIDs: postIDs})
+	return items, err
+}
+
+const deletePostTranslation = `
+	DELETE FROM
+		post_translation
+	WHERE
+		post_id = :post_id AND
+		language_code = :language_code
+`
+
+type DeletePostTranslationParams struct {
+	PostID       string `db:"post_id"`
+	LanguageCode string `db:"language_code"`
+}
+
+func (q *Queries) DeletePostTranslation(ctx context.Context, arg DeletePostTranslationParams) (int64, error) {
+	return NamedExecRowsAffectedContext(ctx, q.db, deletePostTranslation, arg)
+}
+
+const getPostListMissingTranslation = `
+	SELECT
+		*
+	FROM
+		post
+	WHERE
+		deleted_at IS NULL AND (
+			:user_id IS NULL OR id IN (
+				SELECT
+					post_id
+				FROM
+					post_collaborator
+				WHERE
+					user_id = :user_id AND
+					role IN (:roles)
+			)
+		) AND
+		(
+			SELECT
+				COUNT(*)
+			FROM
+				post_translation
+			WHERE
+				post_translation.post_id = post.id AND
+				post_translation.language_code IN (:language_codes)
+		) + (
+			CASE WHEN language_code IN (:language_codes) THEN 1 ELSE 0 END
+		) < :language_code_count AND (
+			:cursor IS NULL OR :cursor_id IS NULL OR
+			updated_at {{if .Ascending}}>{{else}}<{{end}} :cursor OR (
+				updated_at = :cursor AND id {{if .Ascending}}>{{else}}<{{end}} :cursor_id
+			)
+		)
+	ORDER BY
+		updated_at {{if .Ascending}}ASC{{else}}DESC{{end}},
+		id {{if .Ascending}}ASC{{else}}DESC{{end}}
+	LIMIT
+		:page_size
+`
+
+type GetPostListMissingTranslationParams struct {
+	UserID            *string  `db:"user_id"`
+	Roles             []string `db:"roles"`
+	LanguageCodes     []string `db:"language_codes"`
+	LanguageCodeCount int      `db:"language_code_count"`
+	Ascending         bool     // not a db tag, used for formatting
+	PageSize          int      `db:"page_size"`
+	Cursor            *string  `db:"cursor"`
+	CursorID          *string  `db:"cursor_id"`
+}
+
+// GetPostListMissingTranslation returns the posts that are neither written in
+// nor translated to at least one of the language codes.
+func (q *Queries) GetPostListMissingTranslation(ctx context.Context, arg GetPostListMissingTranslationParams) ([]Post, error) {
+	if len(arg.LanguageCodes) == 0 {
+		return nil, fmt.Errorf("language codes must not be empty")
+	}
+
+	query, err := template.RenderTemplate(getPostListMissingTranslation, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render query template: %w", err)
+	}
+
+	items := []Post{}
+	err = NamedSelectInContext(ctx, q.db, &items, query, arg)
+	return items, err
+}
@@ -0,0 +1,167 @@
+package repository
+
+import "context"
+
+const createPostView = `
+	INSERT INTO post_view (
+		post_id,
+		view_date,
+		visitor_hash,
+		created_at
+	) VALUES (
+		:post_id,
+		:view_date,
+		:visitor_hash,
+		:created_at
+	)
+	ON CONFLICT (post_id, view_date, visitor_hash) DO NOTHING
+`
+
+// CreatePostView records the view, returning whether it was recorded. A
+// visitor is only counted once per post per day.
+func (q *Queries) CreatePostView(ctx context.Context, arg PostView) (bool, error) {
+	rows, err := NamedExecRowsAffectedContext(ctx, q.db, createPostView, arg)
+	return rows > 0, err
+}
+
+const rollupPostView = `
+	INSERT INTO post_view_daily (
+		post_id,
+		view_date,
+		view_count,
+		created_at,
+		updated_at
+	)
+	SELECT
+		post_id,
+		view_date,
+		COUNT(*),
+		:now,
+		:now
+	FROM
+		post_view
+	WHERE
+		view_date < :view_date_before
+	GROUP BY
+		post_id,
+		view_date
+	ON CONFLICT (post_id, view_date) DO UPDATE SET
+		view_count = post_view_daily.view_count + excluded.view_count,
+		updated_at = excluded.updated_at
+`
+
+type RollupPostViewParams struct {
+	ViewDateBefore string `db:"view_date_before"`
+	Now            string `db:"now"`
+}
+
+// RollupPostView adds the views before the date to the daily view counts. The
+// views are not deleted, see DeletePostViewByViewDateBefore.
+func (q *Queries) RollupPostView(ctx context.Context, arg RollupPostViewParams) (int64, error) {
+	return NamedExecRowsAffectedContext(ctx, q.db, rollupPostView, arg)
+}
+
+const deletePostViewByViewDateBefore = `
+	DELETE FROM
+		post_view
+	WHERE
+		view_date < :view_date_before
+`
+
+type DeletePostViewByViewDateBeforeParams struct {
+	ViewDateBefore string `db:"view_date_before"`
+}
+
+func (q *Queries) DeletePostViewByViewDateBefore(ctx context.Context, viewDateBefore string) (int64, error) {
+	return NamedExecRowsAffectedContext(ctx, q.db, deletePostViewByViewDateBefore, DeletePostViewByViewDateBeforeParams{ViewDateBefore: viewDateBefore})
+}
+
+const getPostViewCountListByPostID = `
+	SELECT
+		view_date,
+		SUM(view_count) AS view_count
+	FROM (
+		SELECT
+			view_date,
+			view_count
+		FROM
+			post_view_daily
+		WHERE
+			post_id = :post_id AND
+			view_date >= :view_date_from
+		UNION ALL
+		SELECT
+			view_date,
+			1 AS view_count
+		FROM
+			post_view
+		WHERE
+			post_id = :post_id AND
+			view_date >= :view_date_from
+	) AS v
+	GROUP BY
+		view_date
+	ORDER BY
+		view_date ASC
+`
+
+type GetPostViewCountListByPostIDParams struct {
+	PostID       string `db:"post_id"`
+	ViewDateFrom string `db:"view_date_from"`
+}
+
+// GetPostViewCountListByPostID returns the daily view counts of the post from
+// the date, including the views that are not rolled up yet. Days without
+// views are omitted.
+func (q *Queries) GetPostViewCountListByPostID(ctx context.Context, arg GetPostViewCountListByPostIDParams) ([]PostViewCount, error) {
+	items := []PostViewCount{}
+	err := NamedSelectContext(ctx, q.db, &items, getPostViewCountListByPostID, arg)
+	return items, err
+}
+
+const getTopPostViewTotalList = `
+	SELECT
+		v.post_id,
+		SUM(v.view_count) AS view_count
+	FROM (
+		SELECT
+			post_id,
+			view_count
+		FROM
+			post_view_daily
+		WHERE
+			view_date >= :view_date_from
+		UNION ALL
+		SELECT
+			post_id,
+			1 AS view_count
+		FROM
+			post_view
+		WHERE
+			view_date >= :view_date_from
+	) AS v
+	INNER JOIN
+		post ON post.id = v.post_id
+	WHERE
+		post.deleted_at IS NULL
+	GROUP BY
+		v.post_id
+	ORDER BY
+		SUM(v.view_count) DESC,
+		v.post_id ASC
+	LIMIT
+		:page_size
+`
+
+type GetTopPostViewTotalListParams struct {
+	ViewDateFrom string `db:"view_date_from"`
+	PageSize     int    `db:"page_size"`
+}
+
+// GetTopPostViewTotalList returns the posts not in the trash with the most
+// views from the date, most viewed first.
+func (q *Queries) GetTopPostViewTotalList(ctx context.Context, arg GetTopPostViewTotalListParams) ([]PostViewTotal, error) {
+	items := []PostViewTotal{}
+	err := NamedSelectContext(ctx, q.db, &items, getTopPostViewTotalList, arg)
+	return items, err
+}
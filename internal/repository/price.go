@@ -88,6 +88,63 @@ func (q *Queries) GetPriceByID(ctx context.Context, id string) ([]Price, error)
 	return items, err
 }
 
+const getAllPriceList = `
+	SELECT
+		*
+	FROM
+		price
+	ORDER BY
+		created_at ASC,
+		id ASC
+`
+
+func (q *Queries) GetAllPriceList(ctx context.Context) ([]Price, error) {
+	items := []Price{}
+	err := NamedSelectContext(ctx, q.db, &items, getAllPriceList, struct{}{})
+	return items, err
+}
+
+const getPriceListByProductID = `
+	SELECT
+		*
+	FROM
+		price
+	WHERE
+		product_id = :product_id
+	ORDER BY
+		created_at ASC,
+		id ASC
+`
+
+type GetPriceListByProductIDParams struct {
+	ProductID string `db:"product_id"`
+}
+
+func (q *Queries) GetPriceListByProductID(ctx context.Context, productID string) ([]Price, error) {
+	items := []Price{}
+	err := NamedSelectContext(ctx, q.db, &items, getPriceListByProductID, GetPriceListByProductIDParams{ProductID: productID})
+	return items, err
+}
+
+const getPriceByExternalID = `
+	SELECT
+		*
+	FROM
+		price
+	WHERE
+		external_id = :external_id
+`
+
+type GetPriceByExternalIDParams struct {
+	ExternalID string `db:"external_id"`
+}
+
+func (q *Queries) GetPriceByExternalID(ctx context.Context, externalID string) ([]Price, error) {
+	items := []Price{}
+	err := NamedSelectContext(ctx, q.db, &items, getPriceByExternalID, GetPriceByExternalIDParams{ExternalID: externalID})
+	return items, err
+}
+
 const updatePrice = `
 	UPDATE
 		price
@@ -102,7 +159,8 @@ const updatePrice = `
 		is_active = :is_active,
 		updated_at = :updated_at
 	WHERE
-		id = :id
+		id = :id AND
+		(:version IS NULL OR updated_at = :version)
 `
 
 type UpdatePriceParams struct {
@@ -116,8 +174,27 @@ type UpdatePriceParams struct {
 	IsActive               bool    `db:"is_active"`
 	UpdatedAt              string  `db:"updated_at"`
 	ID                     string  `db:"id"`
+	Version                *string `db:"version"`
+}
+
+func (q *Queries) UpdatePrice(ctx context.Context, arg UpdatePriceParams) (int64, error) {
+	return NamedExecRowsAffectedContext(ctx, q.db, updatePrice, arg)
+}
+
+const updatePricePaymentSyncStatus = `
+	UPDATE
+		price
+	SET
+		payment_sync_status = :payment_sync_status
+	WHERE
+		id = :id
+`
+
+type UpdatePricePaymentSyncStatusParams struct {
+	PaymentSyncStatus string `db:"payment_sync_status"`
+	ID                string `db:"id"`
 }
 
-func (q *Queries) UpdatePrice(ctx context.Context, arg UpdatePriceParams) error {
-	return NamedExecOneRowContext(ctx, q.db, updatePrice, arg)
+func (q *Queries) UpdatePricePaymentSyncStatus(ctx context.Context, arg UpdatePricePaymentSyncStatusParams) error {
+	return NamedExecOneRowContext(ctx, q.db, updatePricePaymentSyncStatus, arg)
 }
@@ -88,17 +88,98 @@ const updateProductByID = `
 		is_active = :is_active,
 		updated_at = :updated_at
 	WHERE
-		id = :id
+		id = :id AND
+		(:version IS NULL OR updated_at = :version)
 `
 
 type UpdateProductByIDParams struct {
-	ID          string `db:"id"`
-	Name        string `db:"name"`
-	Description string `db:"description"`
-	IsActive    bool   `db:"is_active"`
-	UpdatedAt   string `db:"updated_at"`
+	ID          string  `db:"id"`
+	Version     *string `db:"version"`
+	Name        string  `db:"name"`
+	Description string  `db:"description"`
+	IsActive    bool    `db:"is_active"`
+	UpdatedAt   string  `db:"updated_at"`
+}
+
+func (q *Queries) UpdateProductByID(ctx context.Context, arg UpdateProductByIDParams) (int64, error) {
+	return NamedExecRowsAffectedContext(ctx, q.db, updateProductByID, arg)
+}
+
+const updateProductExternalIDByID = `
+	UPDATE
+		product
+	SET
+		external_id = :external_id,
+		updated_at = :updated_at
+	WHERE
+		id = :id
+`
+
+type UpdateProductExternalIDByIDParams struct {
+	ID         string `db:"id"`
+	ExternalID string `db:"external_id"`
+	UpdatedAt  string `db:"updated_at"`
 }
 
-func (q *Queries) UpdateProductByID(ctx context.Context, arg UpdateProductByIDParams) error {
-	return NamedExecOneRowContext(ctx, q.db, updateProductByID, arg)
+func (q *Queries) UpdateProductExternalIDByID(ctx context.Context, arg UpdateProductExternalIDByIDParams) error {
+	return NamedExecOneRowContext(ctx, q.db, updateProductExternalIDByID, arg)
+}
+
+const getProductListWithoutExternalID = `
+	SELECT
+		*
+	FROM
+		product
+	WHERE
+		external_id IS NULL
+	ORDER BY
+		created_at ASC,
+		id ASC
+`
+
+func (q *Queries) GetProductListWithoutExternalID(ctx context.Context) ([]Product, error) {
+	items := []Product{}
+	err := NamedSelectContext(ctx, q.db, &items, getProductListWithoutExternalID, struct{}{})
+	return items, err
+}
+
+const getProductListWithExternalID = `
+	SELECT
+		*
+	FROM
+		product
+	WHERE
+		external_id IS NOT NULL
+	ORDER BY
+		created_at ASC,
+		id ASC
+`
+
+func (q *Queries) GetProductListWithExternalID(ctx context.Context) ([]Product, error) {
+	items := []Product{}
+	err := NamedSelectContext(ctx, q.db, &items, getProductListWithExternalID, struct{}{})
+	return items, err
+}
+
+const getProductListByIDs = `
+	SELECT
+		*
+	FROM
+		product
+	WHERE
+		id IN (:ids)
+`
+
+type GetProductListByIDsParams struct {
+	IDs []string `db:"ids"`
+}
+
+func (q *Queries) GetProductListByIDs(ctx context.Context, ids []string) ([]Product, error) {
+	items := []Product{}
+	if len(ids) == 0 {
+		return items, nil
+	}
+
+	err := NamedSelectInContext(ctx, q.db, &items, getProductListByIDs, GetProductListByIDsParams{IDs: ids})
+	return items, err
 }
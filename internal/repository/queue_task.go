@@ -8,6 +8,8 @@ const createQueueTask = `
 		lane,
 		payload,
 		status,
+		attempt_count,
+		run_at,
 		created_at,
 		updated_at
 	) VALUES (
@@ -15,6 +17,8 @@ const createQueueTask = `
 		:lane,
 		:payload,
 		:status,
+		:attempt_count,
+		:run_at,
 		:created_at,
 		:updated_at
 	)
@@ -31,7 +35,8 @@ const getQueueTask = `
 		queue_task
 	WHERE
 		lane = :lane AND
-		status = :status
+		status = :status AND
+		(run_at IS NULL OR run_at <= :now)
 	ORDER BY
 		created_at ASC,
 		id ASC
@@ -41,6 +46,7 @@ const getQueueTask = `
 type GetQueueTaskParams struct {
 	Lane   string `db:"lane"`
 	Status string `db:"status"`
+	Now    string `db:"now"`
 }
 
 func (q *Queries) GetQueueTask(ctx context.Context, arg GetQueueTaskParams) ([]QueueTask, error) {
@@ -70,3 +76,27 @@ type UpdateQueueTaskStatusByIDParams struct {
 func (q *Queries) UpdateQueueTaskStatusByID(ctx context.Context, arg UpdateQueueTaskStatusByIDParams) error {
 	return NamedExecOneRowContext(ctx, q.db, updateQueueTaskStatusByID, arg)
 }
+
+const updateQueueTaskRetryByID = `
+	UPDATE
+		queue_task
+	SET
+		attempt_count = :attempt_count,
+		run_at = :run_at,
+		updated_at = :updated_at
+	WHERE
+		id = :id
+`
+
+type UpdateQueueTaskRetryByIDParams struct {
+	ID           string  `db:"id"`
+	AttemptCount int     `db:"attempt_count"`
+	RunAt        *string `db:"run_at"`
+	UpdatedAt    string  `db:"updated_at"`
+}
+
+// UpdateQueueTaskRetryByID records a failed attempt of a pending task, which
+// is retried at the run time.
+func (q *Queries) UpdateQueueTaskRetryByID(ctx context.Context, arg UpdateQueueTaskRetryByIDParams) error {
+	return NamedExecOneRowContext(ctx, q.db, updateQueueTaskRetryByID, arg)
+}
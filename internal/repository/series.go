@@ -0,0 +1,155 @@
+package repository
+
+import "context"
+
+const createSeries = `
+	INSERT INTO series (
+		id,
+		user_id,
+		title,
+		description,
+		slug,
+		created_at,
+		updated_at
+	) VALUES (
+		:id,
+		:user_id,
+		:title,
+		:description,
+		:slug,
+		:created_at,
+		:updated_at
+	)
+`
+
+func (q *Queries) CreateSeries(ctx context.Context, arg Series) error {
+	return NamedExecOneRowContext(ctx, q.db, createSeries, arg)
+}
+
+const getSeriesList = `
+	SELECT
+		*
+	FROM
+		series
+	WHERE
+		:cursor IS NULL OR :cursor_id IS NULL OR
+		updated_at < :cursor OR (
+			updated_at = :cursor AND id < :cursor_id
+		)
+	ORDER BY
+		updated_at DESC,
+		id DESC
+	LIMIT
+		:page_size
+`
+
+type GetSeriesListParams struct {
+	Cursor   *string `db:"cursor"`
+	CursorID *string `db:"cursor_id"`
+	PageSize int     `db:"page_size"`
+}
+
+func (q *Queries) GetSeriesList(ctx context.Context, arg GetSeriesListParams) ([]Series, error) {
+	items := []Series{}
+	err := NamedSelectContext(ctx, q.db, &items, getSeriesList, arg)
+	return items, err
+}
+
+const getSeriesByID = `
+	SELECT
+		*
+	FROM
+		series
+	WHERE
+		id = :id
+`
+
+type GetSeriesByIDParams struct {
+	ID string `db:"id"`
+}
+
+func (q *Queries) GetSeriesByID(ctx context.Context, id string) ([]Series, error) {
+	items := []Series{}
+	err := NamedSelectContext(ctx, q.db, &items, getSeriesByID, GetSeriesByIDParams{ID: id})
+	return items, err
+}
+
+const getSeriesBySlug = `
+	SELECT
+		*
+	FROM
+		series
+	WHERE
+		slug = :slug
+`
+
+type GetSeriesBySlugParams struct {
+	Slug string `db:"slug"`
+}
+
+func (q *Queries) GetSeriesBySlug(ctx context.Context, slug string) ([]Series, error) {
+	items := []Series{}
+	err := NamedSelectContext(ctx, q.db, &items, getSeriesBySlug, GetSeriesBySlugParams{Slug: slug})
+	return items, err
+}
+
+const updateSeriesByID = `
+	UPDATE
+		series
+	SET
+		title = :title,
+		description = :description,
+		slug = :slug,
+		updated_at = :updated_at
+	WHERE
+		id = :id AND
+		(:version IS NULL OR updated_at = :version)
+`
+
+type UpdateSeriesByIDParams struct {
+	Title       string  `db:"title"`
+	Description string  `db:"description"`
+	Slug        string  `db:"slug"`
+	UpdatedAt   string  `db:"updated_at"`
+	ID          string  `db:"id"`
+	Version     *string `db:"version"`
+}
+
+func (q *Queries) UpdateSeriesByID(ctx context.Context, arg UpdateSeriesByIDParams) (int64, error) {
+	return NamedExecRowsAffectedContext(ctx, q.db, updateSeriesByID, arg)
+}
+
+const updateSeriesUpdatedAtByID = `
+	UPDATE
+		series
+	SET
+		updated_at = :updated_at
+	WHERE
+		id = :id AND
+		(:version IS NULL OR updated_at = :version)
+`
+
+type UpdateSeriesUpdatedAtByIDParams struct {
+	UpdatedAt string  `db:"updated_at"`
+	ID        string  `db:"id"`
+	Version   *string `db:"version"`
+}
+
+func (q *Queries) UpdateSeriesUpdatedAtByID(ctx context.Context, arg UpdateSeriesUpdatedAtByIDParams) (int64, error) {
+	return NamedExecRowsAffectedContext(ctx, q.db, updateSeriesUpdatedAtByID, arg)
+}
+
+const deleteSeriesByID = `
+	DELETE FROM
+		series
+	WHERE
+		id = :id
+`
+
+type DeleteSeriesByIDParams struct {
+	ID string `db:"id"`
+}
+
+func (q *Queries) DeleteSeriesByID(ctx context.Context, id string) error {
+	return NamedExecOneRowContext(ctx, q.db, deleteSeriesByID, DeleteSeriesByIDParams{ID: id})
+}
@@ -0,0 +1,130 @@
+package repository
+
+import "context"
+
+const createSeriesPost = `
+	INSERT INTO series_post (
+		series_id,
+		post_id,
+		position,
+		created_at
+	) VALUES (
+		:series_id,
+		:post_id,
+		:position,
+		:created_at
+	)
+`
+
+func (q *Queries) CreateSeriesPost(ctx context.Context, arg SeriesPost) error {
+	return NamedExecOneRowContext(ctx, q.db, createSeriesPost, arg)
+}
+
+const getSeriesPostListBySeriesID = `
+	SELECT
+		*
+	FROM
+		series_post
+	WHERE
+		series_id = :series_id
+	ORDER BY
+		position ASC
+`
+
+type GetSeriesPostListBySeriesIDParams struct {
+	SeriesID string `db:"series_id"`
+}
+
+func (q *Queries) GetSeriesPostListBySeriesID(ctx context.Context, seriesID string) ([]SeriesPost, error) {
+	items := []SeriesPost{}
+	err := NamedSelectContext(ctx, q.db, &items, getSeriesPostListBySeriesID, GetSeriesPostListBySeriesIDParams{SeriesID: seriesID})
+	return items, err
+}
+
+const getSeriesPostListByPostIDs = `
+	SELECT
+		*
+	FROM
+		series_post
+	WHERE
+		post_id IN (:post_ids)
+`
+
+type GetSeriesPostListByPostIDsParams struct {
+	PostIDs []string `db:"post_ids"`
+}
+
+func (q *Queries) GetSeriesPostListByPostIDs(ctx context.Context, postIDs []string) ([]SeriesPost, error) {
+	items := []SeriesPost{}
+	if len(postIDs) == 0 {
+		return items, nil
+	}
+
+	err := NamedSelectInContext(ctx, q.db, &items, getSeriesPostListByPostIDs, GetSeriesPostListByPostIDsParams{PostIDs: postIDs})
+	return items, err
+}
+
+const getPostListBySeriesID = `
+	SELECT
+		post.*
+	FROM
+		post
+		JOIN series_post ON series_post.post_id = post.id
+	WHERE
+		series_post.series_id = :series_id AND
+		post.deleted_at IS NULL
+	ORDER BY
+		series_post.position ASC
+`
+
+type GetPostListBySeriesIDParams struct {
+	SeriesID string `db:"series_id"`
+}
+
+// GetPostListBySeriesID returns the posts in the series in order, excluding
+// the posts in the trash.
+func (q *Queries) GetPostListBySeriesID(ctx context.Context, seriesID string) ([]Post, error) {
+	items := []Post{}
+	err := NamedSelectContext(ctx, q.db, &items, getPostListBySeriesID, GetPostListBySeriesIDParams{SeriesID: seriesID})
+	return items, err
+}
+
+const getTrashedPostListBySeriesID = `
+	SELECT
+		post.*
+	FROM
+		post
+		JOIN series_post ON series_post.post_id = post.id
+	WHERE
+		series_post.series_id = :series_id AND
+		post.deleted_at IS NOT NULL
+	ORDER BY
+		series_post.position ASC
+`
+
+type GetTrashedPostListBySeriesIDParams struct {
+	SeriesID string `db:"series_id"`
+}
+
+// GetTrashedPostListBySeriesID returns the posts in the series that are in the
+// trash, in order.
+func (q *Queries) GetTrashedPostListBySeriesID(ctx context.Context, seriesID string) ([]Post, error) {
+	items := []Post{}
+	err := NamedSelectContext(ctx, q.db, &items, getTrashedPostListBySeriesID, GetTrashedPostListBySeriesIDParams{SeriesID: seriesID})
+	return items, err
+}
+
+const deleteSeriesPostBySeriesID = `
+	DELETE FROM
+		series_post
+	WHERE
+		series_id = :series_id
+`
+
+type DeleteSeriesPostBySeriesIDParams struct {
+	SeriesID string `db:"series_id"`
+}
+
+func (q *Queries) DeleteSeriesPostBySeriesID(ctx context.Context, seriesID string) (int64, error) {
+	return NamedExecRowsAffectedContext(ctx, q.db, deleteSeriesPostBySeriesID, DeleteSeriesPostBySeriesIDParams{SeriesID: seriesID})
+}
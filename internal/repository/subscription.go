@@ -0,0 +1,102 @@
+package repository
+
+import "context"
+
+const upsertSubscription = `
+	INSERT INTO subscription (
+		id,
+		external_id,
+		user_id,
+		price_id,
+		status,
+		started_at,
+		current_period_start,
+		current_period_end,
+		cancel_at_period_end,
+		canceled_at,
+		ended_at,
+		created_at,
+		updated_at
+	) VALUES (
+		:id,
+		:external_id,
+		:user_id,
+		:price_id,
+		:status,
+		:started_at,
+		:current_period_start,
+		:current_period_end,
+		:cancel_at_period_end,
+		:canceled_at,
+		:ended_at,
+		:created_at,
+		:updated_at
+	)
+	ON CONFLICT (external_id) DO UPDATE SET
+		price_id = excluded.price_id,
+		status = excluded.status,
+		started_at = excluded.started_at,
+		current_period_start = excluded.current_period_start,
+		current_period_end = excluded.current_period_end,
+		cancel_at_period_end = excluded.cancel_at_period_end,
+		canceled_at = excluded.canceled_at,
+		ended_at = excluded.ended_at,
+		updated_at = excluded.updated_at
+`
+
+// UpsertSubscription creates the subscription, or updates it to the latest
+// state from the payment provider if it already exists.
+func (q *Queries) UpsertSubscription(ctx context.Context, arg Subscription) error {
+	return NamedExecOneRowContext(ctx, q.db, upsertSubscription, arg)
+}
+
+const getSubscriptionByExternalID = `
+	SELECT
+		*
+	FROM
+		subscription
+	WHERE
+		external_id = :external_id
+`
+
+type GetSubscriptionByExternalIDParams struct {
+	ExternalID string `db:"external_id"`
+}
+
+func (q *Queries) GetSubscriptionByExternalID(ctx context.Context, externalID string) ([]Subscription, error) {
+	items := []Subscription{}
+	err := NamedSelectContext(ctx, q.db, &items, getSubscriptionByExternalID, GetSubscriptionByExternalIDParams{ExternalID: externalID})
+	return items, err
+}
+
+const getSubscriptionListByUserID = `
+	SELECT
+		*
+	FROM
+		subscription
+	WHERE
+		user_id = :user_id AND (
+			:cursor IS NULL OR :cursor_id IS NULL OR
+			started_at < :cursor OR (
+				started_at = :cursor AND id < :cursor_id
+			)
+		)
+	ORDER BY
+		started_at DESC,
+		id DESC
+	LIMIT
+		:page_size
+`
+
+type GetSubscriptionListByUserIDParams struct {
+	UserID   string  `db:"user_id"`
+	Cursor   *string `db:"cursor"`
+	CursorID *string `db:"cursor_id"`
+	PageSize int     `db:"page_size"`
+}
+
+func (q *Queries) GetSubscriptionListByUserID(ctx context.Context, arg GetSubscriptionListByUserIDParams) ([]Subscription, error) {
+	items := []Subscription{}
+	err := NamedSelectContext(ctx, q.db, &items, getSubscriptionListByUserID, arg)
+	return items, err
+}
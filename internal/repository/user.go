@@ -108,6 +108,25 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) ([]User, err
 	return items, err
 }
 
+const getUserByExternalID = `
+	SELECT
+		*
+	FROM
+		"user"
+	WHERE
+		external_id = :external_id
+`
+
+type GetUserByExternalIDParams struct {
+	ExternalID string `db:"external_id"`
+}
+
+func (q *Queries) GetUserByExternalID(ctx context.Context, externalID string) ([]User, error) {
+	items := []User{}
+	err := NamedSelectContext(ctx, q.db, &items, getUserByExternalID, GetUserByExternalIDParams{ExternalID: externalID})
+	return items, err
+}
+
 const updateUserPassword = `
 	UPDATE
 		"user"
@@ -192,7 +211,6 @@ const verifyUser = `
 	UPDATE
 		"user"
 	SET
-		external_id = :external_id,
 		is_verified = TRUE,
 		updated_at = :updated_at
 	WHERE
@@ -200,13 +218,68 @@ const verifyUser = `
 `
 
 type VerifyUserParams struct {
+	UpdatedAt string `db:"updated_at"`
+	ID        string `db:"id"`
+}
+
+func (q *Queries) VerifyUser(ctx context.Context, arg VerifyUserParams) error {
+	return NamedExecOneRowContext(ctx, q.db, verifyUser, arg)
+}
+
+const updateUserExternalID = `
+	UPDATE
+		"user"
+	SET
+		external_id = :external_id,
+		updated_at = :updated_at
+	WHERE
+		id = :id
+`
+
+type UpdateUserExternalIDParams struct {
 	ExternalID string `db:"external_id"`
 	UpdatedAt  string `db:"updated_at"`
 	ID         string `db:"id"`
 }
 
-func (q *Queries) VerifyUser(ctx context.Context, arg VerifyUserParams) error {
-	return NamedExecOneRowContext(ctx, q.db, verifyUser, arg)
+func (q *Queries) UpdateUserExternalID(ctx context.Context, arg UpdateUserExternalIDParams) error {
+	return NamedExecOneRowContext(ctx, q.db, updateUserExternalID, arg)
+}
+
+const updateUserPaymentSyncStatus = `
+	UPDATE
+		"user"
+	SET
+		payment_sync_status = :payment_sync_status
+	WHERE
+		id = :id
+`
+
+type UpdateUserPaymentSyncStatusParams struct {
+	PaymentSyncStatus string `db:"payment_sync_status"`
+	ID                string `db:"id"`
+}
+
+func (q *Queries) UpdateUserPaymentSyncStatus(ctx context.Context, arg UpdateUserPaymentSyncStatusParams) error {
+	return NamedExecOneRowContext(ctx, q.db, updateUserPaymentSyncStatus, arg)
+}
+
+const getUserListWithExternalID = `
+	SELECT
+		*
+	FROM
+		"user"
+	WHERE
+		external_id IS NOT NULL
+	ORDER BY
+		created_at ASC,
+		id ASC
+`
+
+func (q *Queries) GetUserListWithExternalID(ctx context.Context) ([]User, error) {
+	items := []User{}
+	err := NamedSelectContext(ctx, q.db, &items, getUserListWithExternalID, struct{}{})
+	return items, err
 }
 
 const deleteUser = `
@@ -223,3 +296,26 @@ type DeleteUserParams struct {
 func (q *Queries) DeleteUser(ctx context.Context, id string) error {
 	return NamedExecOneRowContext(ctx, q.db, deleteUser, DeleteUserParams{ID: id})
 }
+
+const getUserListByIDs = `
+	SELECT
+		*
+	FROM
+		"user"
+	WHERE
+		id IN (:ids)
+`
+
+type GetUserListByIDsParams struct {
+	IDs []string `db:"ids"`
+}
+
+func (q *Queries) GetUserListByIDs(ctx context.Context, ids []string) ([]User, error) {
+	items := []User{}
+	if len(ids) == 0 {
+		return items, nil
+	}
+
+	err := NamedSelectInContext(ctx, q.db, &items, getUserListByIDs, GetUserListByIDsParams{IDs: ids})
+	return items, err
+}
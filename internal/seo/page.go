@@ -0,0 +1,170 @@
+package seo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Page is the metadata of a page of the site, used for the title, meta
+// description, OpenGraph and Twitter card tags, and the JSON-LD of the page.
+type Page struct {
+	Title       string
+	Description string
+	URL         string
+	SiteName    string
+	// LanguageCode is a BCP 47 language code such as en-US
+	LanguageCode string
+	ImageURL     string
+	Article      *Article
+}
+
+// Article is the metadata of a page that is a blog post.
+type Article struct {
+	AuthorName  string
+	Tags        []string
+	PublishedAt time.Time
+	UpdatedAt   time.Time
+}
+
+var titlePattern = regexp.MustCompile(`(?is)<title>.*?</title>`)
+
+// InjectMeta replaces the title of the HTML document and adds the meta tags of
+// the page to the end of its head. The document is returned unchanged if it
+// has no head.
+func InjectMeta(document []byte, page Page) []byte {
+	i := bytes.Index(bytes.ToLower(document), []byte("</head>"))
+	if i < 0 {
+		return document
+	}
+
+	var result bytes.Buffer
+	result.Write(titlePattern.ReplaceAllLiteral(document[:i], nil))
+	result.WriteString(renderMeta(page))
+	result.Write(document[i:])
+
+	return result.Bytes()
+}
+
+func renderMeta(page Page) string {
+	var b strings.Builder
+
+	meta := func(attribute, key, value string) {
+		if value == "" {
+			return
+		}
+		fmt.Fprintf(&b, `<meta %s="%s" content="%s">`, attribute, html.EscapeString(key), html.EscapeString(value))
+	}
+
+	// The document title also names the site, unlike the title for sharing
+	documentTitle := page.Title
+	if page.SiteName != "" && page.SiteName != page.Title {
+		documentTitle += " - " + page.SiteName
+	}
+
+	fmt.Fprintf(&b, "<title>%s</title>", html.EscapeString(documentTitle))
+	meta("name", "description", page.Description)
+	fmt.Fprintf(&b, `<link rel="canonical" href="%s">`, html.EscapeString(page.URL))
+
+	ogType := "website"
+	if page.Article != nil {
+		ogType = "article"
+	}
+
+	meta("property", "og:type", ogType)
+	meta("property", "og:title", page.Title)
+	meta("property", "og:description", page.Description)
+	meta("property", "og:url", page.URL)
+	meta("property", "og:site_name", page.SiteName)
+	meta("property", "og:locale", strings.ReplaceAll(page.LanguageCode, "-", "_"))
+	meta("property", "og:image", page.ImageURL)
+
+	if page.Article != nil {
+		meta("property", "article:published_time", page.Article.PublishedAt.UTC().Format(time.RFC3339))
+		meta("property", "article:modified_time", page.Article.UpdatedAt.UTC().Format(time.RFC3339))
+		meta("property", "article:author", page.Article.AuthorName)
+		for _, tag := range page.Article.Tags {
+			meta("property", "article:tag", tag)
+		}
+	}
+
+	twitterCard := "summary"
+	if page.ImageURL != "" {
+		twitterCard = "summary_large_image"
+	}
+
+	meta("name", "twitter:card", twitterCard)
+	meta("name", "twitter:title", page.Title)
+	meta("name", "twitter:description", page.Description)
+	meta("name", "twitter:image", page.ImageURL)
+
+	// The JSON encoder escapes <, > and &, so the script cannot be closed by
+	// the values
+	jsonLD, err := json.Marshal(toJSONLD(page))
+	if err == nil {
+		fmt.Fprintf(&b, `<script type="application/ld+json">%s</script>`, jsonLD)
+	}
+
+	return b.String()
+}
+
+type jsonLDPerson struct {
+	Type string `json:"@type"`
+	Name string `json:"name"`
+}
+
+type jsonLDDocument struct {
+	Context          string        `json:"@context"`
+	Type             string        `json:"@type"`
+	Name             string        `json:"name,omitempty"`
+	Headline         string        `json:"headline,omitempty"`
+	Description      string        `json:"description,omitempty"`
+	URL              string        `json:"url"`
+	MainEntityOfPage string        `json:"mainEntityOfPage,omitempty"`
+	Image            string        `json:"image,omitempty"`
+	InLanguage       string        `json:"inLanguage,omitempty"`
+	Author           *jsonLDPerson `json:"author,omitempty"`
+	Keywords         string        `json:"keywords,omitempty"`
+	DatePublished    string        `json:"datePublished,omitempty"`
+	DateModified     string        `json:"dateModified,omitempty"`
+}
+
+func toJSONLD(page Page) jsonLDDocument {
+	if page.Article == nil {
+		return jsonLDDocument{
+			Context:     "https://schema.org",
+			Type:        "WebSite",
+			Name:        page.Title,
+			Description: page.Description,
+			URL:         page.URL,
+			InLanguage:  page.LanguageCode,
+		}
+	}
+
+	doc := jsonLDDocument{
+		Context:          "https://schema.org",
+		Type:             "BlogPosting",
+		Headline:         page.Title,
+		Description:      page.Description,
+		URL:              page.URL,
+		MainEntityOfPage: page.URL,
+		Image:            page.ImageURL,
+		InLanguage:       page.LanguageCode,
+		Keywords:         strings.Join(page.Article.Tags, ", "),
+		DatePublished:    page.Article.PublishedAt.UTC().Format(time.RFC3339),
+		DateModified:     page.Article.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+
+	if page.Article.AuthorName != "" {
+		doc.Author = &jsonLDPerson{
+			Type: "Person",
+			Name: page.Article.AuthorName,
+		}
+	}
+
+	return doc
+}
@@ -0,0 +1,61 @@
+package seo
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// URL is a page of the site listed in the sitemap.
+type URL struct {
+	Loc          string
+	LastModified time.Time
+}
+
+type urlSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// Sitemap renders the URLs as a sitemap in the sitemaps.org protocol. The last
+// modification time is left out if it is zero.
+func Sitemap(urls []URL) ([]byte, error) {
+	doc := urlSet{
+		XMLNS: "http://www.sitemaps.org/schemas/sitemap/0.9",
+		URLs:  make([]sitemapURL, len(urls)),
+	}
+
+	for i, url := range urls {
+		doc.URLs[i] = sitemapURL{
+			Loc: url.Loc,
+		}
+
+		if !url.LastModified.IsZero() {
+			doc.URLs[i].LastMod = url.LastModified.UTC().Format(time.RFC3339)
+		}
+	}
+
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), body...), nil
+}
+
+// Robots renders a robots.txt that allows crawling the site except for the
+// API and the pages that require signing in, and points to the sitemap.
+func Robots(sitemapURL string) []byte {
+	return []byte("User-agent: *\n" +
+		"Disallow: /api/\n" +
+		"Disallow: /account/\n" +
+		"Disallow: /admin/\n" +
+		"Disallow: /auth/\n" +
+		"\n" +
+		"Sitemap: " + sitemapURL + "\n")
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/jljl1337/issho/internal/http/middleware"
 	"github.com/jljl1337/issho/internal/payment"
 	"github.com/jljl1337/issho/internal/service"
+	"github.com/jljl1337/issho/internal/storage"
 )
 
 type Server struct {
@@ -44,14 +45,16 @@ func NewServer() (*Server, error) {
 		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
 
-	paymentProvider := payment.NewPaymentProvider(env.PaymentProvider)
+	paymentProvider := payment.NewPaymentProvider(env.PaymentProvider, dbInstance)
+
+	storageProvider := storage.NewStorage(env.StorageProvider)
 
 	// Serve the API
 	mux := http.NewServeMux()
 
 	apiMux := http.NewServeMux()
 
-	endpointService := service.NewEndpointService(dbInstance, paymentProvider)
+	endpointService := service.NewEndpointService(dbInstance, paymentProvider, storageProvider)
 	endpointHandler := handler.NewEndpointHandler(endpointService)
 	endpointHandler.RegisterRoutes(apiMux)
 
@@ -66,12 +69,16 @@ func NewServer() (*Server, error) {
 
 	mux.Handle("/api/", http.StripPrefix("/api", stack(apiMux)))
 
-	// Serve the static site
-	webHandler := handler.NewWebHandler()
-	mux.HandleFunc("/", webHandler.ServeSite)
+	// Serve the syndication feeds
+	feedHandler := handler.NewFeedHandler(endpointService)
+	feedHandler.RegisterRoutes(mux)
+
+	// Serve the static site, with the sitemap and robots.txt
+	webHandler := handler.NewWebHandler(endpointService)
+	webHandler.RegisterRoutes(mux)
 
 	// Create the scheduler
-	scheduler, err := cron.NewScheduler(dbInstance, emailClient)
+	scheduler, err := cron.NewScheduler(dbInstance, emailClient, storageProvider, endpointService)
 	if err != nil {
 		dbInstance.Close()
 		return nil, fmt.Errorf("failed to create scheduler: %w", err)
@@ -2,8 +2,11 @@ package service
 
 import (
 	"regexp"
+	"strings"
 )
 
+const slugLengthMax = 100
+
 var mapLanguageCodeAllowed = map[string]bool{
 	"en-US": true,
 	"zh-HK": true,
@@ -40,3 +43,31 @@ func checkEmail(email string) (bool, error) {
 
 	return r.MatchString(email), nil
 }
+
+func checkSlug(slug string) (bool, error) {
+	if len(slug) > slugLengthMax {
+		return false, nil
+	}
+
+	r, err := regexp.Compile("^[a-z0-9]+(-[a-z0-9]+)*$")
+	if err != nil {
+		return false, err
+	}
+
+	return r.MatchString(slug), nil
+}
+
+// slugify derives a slug from the text, keeping only ASCII letters and digits
+// separated by hyphens. The slug is empty if the text has no such characters.
+func slugify(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+
+	slug := strings.Join(words, "-")
+	if len(slug) > slugLengthMax {
+		slug = strings.TrimRight(slug[:slugLengthMax], "-")
+	}
+
+	return slug
+}
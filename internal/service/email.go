@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/generator"
+	"github.com/jljl1337/issho/internal/repository"
+)
+
+type queueEmailParams struct {
+	Type      string
+	ToAddress string
+	Subject   string
+	Body      string
+}
+
+// queueEmail creates a pending email and a queue task for the email lane, so
+// it is sent by the email cron job. Call it with queries bound to the same
+// transaction as the change that triggers the email.
+func queueEmail(ctx context.Context, queries *repository.Queries, arg queueEmailParams) error {
+	now := generator.NowISO8601()
+
+	email, err := queries.CreateEmail(ctx, repository.Email{
+		ID:          generator.NewULID(),
+		Type:        arg.Type,
+		ToAddress:   arg.ToAddress,
+		CcAddress:   "",
+		BccAddress:  "",
+		FromAddress: env.EmailFromAddress,
+		Subject:     arg.Subject,
+		Body:        arg.Body,
+		Status:      env.EmailStatusPending,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create email: %w", err)
+	}
+
+	err = queries.CreateQueueTask(ctx, repository.QueueTask{
+		ID:        generator.NewULID(),
+		Lane:      env.QueueTaskLaneEmail,
+		Payload:   email.ID,
+		Status:    env.QueueTaskStatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create queue task: %w", err)
+	}
+
+	return nil
+}
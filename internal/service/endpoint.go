@@ -4,16 +4,19 @@ import (
 	"github.com/jmoiron/sqlx"
 
 	"github.com/jljl1337/issho/internal/payment"
+	"github.com/jljl1337/issho/internal/storage"
 )
 
 type EndpointService struct {
 	db              *sqlx.DB
 	paymentProvider payment.PaymentProvider
+	storage         storage.Storage
 }
 
-func NewEndpointService(db *sqlx.DB, paymentProvider payment.PaymentProvider) *EndpointService {
+func NewEndpointService(db *sqlx.DB, paymentProvider payment.PaymentProvider, storage storage.Storage) *EndpointService {
 	return &EndpointService{
 		db:              db,
 		paymentProvider: paymentProvider,
+		storage:         storage,
 	}
 }
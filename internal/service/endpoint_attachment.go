@@ -0,0 +1,334 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/generator"
+	"github.com/jljl1337/issho/internal/media"
+	"github.com/jljl1337/issho/internal/repository"
+	"github.com/jljl1337/issho/internal/storage"
+)
+
+const attachmentFileNameLengthMax = 255
+
+type CreateAttachmentParams struct {
+	User     repository.User
+	PostID   string
+	FileName string
+	Data     []byte
+}
+
+func (s *EndpointService) CreateAttachment(ctx context.Context, arg CreateAttachmentParams) (*repository.Attachment, error) {
+	if arg.User.Role == env.UserRole {
+		return nil, NewServiceError(ErrCodeForbidden, "insufficient permissions to upload attachment")
+	}
+
+	if len(arg.Data) == 0 {
+		return nil, NewServiceError(ErrCodeUnprocessable, "attachment is empty")
+	}
+
+	if int64(len(arg.Data)) > env.AttachmentSizeMaxBytes {
+		return nil, NewServiceErrorf(ErrCodeAttachmentTooLarge, "attachment must be at most %d bytes", env.AttachmentSizeMaxBytes)
+	}
+
+	// Detect the content type from the data instead of trusting the client
+	contentType, _, err := mime.ParseMediaType(http.DetectContentType(arg.Data))
+	if err != nil || !env.AttachmentContentTypeAllowed[contentType] {
+		return nil, NewServiceError(ErrCodeAttachmentContentTypeNotAllowed, "attachment content type is not allowed")
+	}
+
+	queries := repository.New(s.db)
+
+	postList, err := queries.GetPostByID(ctx, arg.PostID)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get post by ID: %v", err)
+	}
+
+	if len(postList) == 0 {
+		return nil, NewServiceError(ErrCodeNotFound, "post not found")
+	}
+
+	post := postList[0]
+
+	err = checkPostRole(ctx, queries, arg.User, post.ID, env.PostCollaboratorRoleEditor, "upload attachment")
+	if err != nil {
+		return nil, err
+	}
+
+	now := generator.NowISO8601()
+
+	attachment := repository.Attachment{
+		ID:                  generator.NewULID(),
+		PostID:              post.ID,
+		UserID:              &arg.User.ID,
+		FileName:            sanitizeFileName(arg.FileName),
+		ContentType:         contentType,
+		SizeBytes:           int64(len(arg.Data)),
+		Width:               nil,
+		Height:              nil,
+		StorageKey:          "",
+		ThumbnailStorageKey: nil,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}
+	attachment.StorageKey = "attachments/" + attachment.ID
+
+	var thumbnail []byte
+	var thumbnailContentType string
+	if _, width, height, ok := media.ProbeImage(arg.Data); ok {
+		attachment.Width = &width
+		attachment.Height = &height
+
+		// A missing thumbnail is not fatal, the original is served instead
+		thumbnail, thumbnailContentType, err = media.Thumbnail(arg.Data, env.AttachmentThumbnailSizeMax)
+		if err != nil {
+			slog.Warn("Failed to create thumbnail for attachment " + attachment.ID + ": " + err.Error())
+		}
+	}
+
+	if err := s.storage.Put(ctx, attachment.StorageKey, arg.Data, attachment.ContentType); err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to store attachment: %v", err)
+	}
+
+	if thumbnail != nil {
+		thumbnailStorageKey := attachment.StorageKey + "-thumbnail"
+		if err := s.storage.Put(ctx, thumbnailStorageKey, thumbnail, thumbnailContentType); err != nil {
+			s.deleteAttachmentObjects(ctx, attachment)
+			return nil, NewServiceErrorf(ErrCodeInternal, "failed to store attachment thumbnail: %v", err)
+		}
+		attachment.ThumbnailStorageKey = &thumbnailStorageKey
+	}
+
+	err = queries.CreateAttachment(ctx, attachment)
+	if err != nil {
+		s.deleteAttachmentObjects(ctx, attachment)
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to create attachment: %v", err)
+	}
+
+	return &attachment, nil
+}
+
+type GetAttachmentListByPostIDParams struct {
+	User   repository.User
+	PostID string
+}
+
+func (s *EndpointService) GetAttachmentListByPostID(ctx context.Context, arg GetAttachmentListByPostIDParams) ([]repository.Attachment, error) {
+	queries := repository.New(s.db)
+
+	postList, err := queries.GetPostByID(ctx, arg.PostID)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get post by ID: %v", err)
+	}
+
+	if len(postList) == 0 {
+		return nil, NewServiceError(ErrCodeNotFound, "post not found")
+	}
+
+	canView, err := canViewPostAttachments(ctx, queries, &arg.User, postList[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if !canView {
+		return nil, NewServiceError(ErrCodeNotFound, "post not found")
+	}
+
+	attachments, err := queries.GetAttachmentListByPostID(ctx, arg.PostID)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get attachment list: %v", err)
+	}
+
+	return attachments, nil
+}
+
+type GetAttachmentContentParams struct {
+	// User is nil for anonymous requests
+	User         *repository.User
+	AttachmentID string
+	Thumbnail    bool
+}
+
+type AttachmentContent struct {
+	Attachment  repository.Attachment
+	ContentType string
+	Body        io.ReadCloser
+	// IsPublic reports whether the attachment can be viewed without signing in
+	IsPublic bool
+}
+
+// GetAttachmentContent opens the stored attachment, or its thumbnail if
+// requested and available. The caller must close the body.
+func (s *EndpointService) GetAttachmentContent(ctx context.Context, arg GetAttachmentContentParams) (*AttachmentContent, error) {
+	queries := repository.New(s.db)
+
+	attachment, err := getAttachmentByID(ctx, queries, arg.AttachmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	postList, err := queries.GetPostByID(ctx, attachment.PostID)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get post by ID: %v", err)
+	}
+
+	if len(postList) == 0 {
+		return nil, NewServiceError(ErrCodeNotFound, "attachment not found")
+	}
+
+	post := postList[0]
+
+	canView, err := canViewPostAttachments(ctx, queries, arg.User, post)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canView {
+		return nil, NewServiceError(ErrCodeNotFound, "attachment not found")
+	}
+
+	storageKey := attachment.StorageKey
+	contentType := attachment.ContentType
+	if arg.Thumbnail && attachment.ThumbnailStorageKey != nil {
+		storageKey = *attachment.ThumbnailStorageKey
+
+		// Thumbnails of JPEG images are JPEG, the others are PNG
+		if contentType != "image/jpeg" {
+			contentType = "image/png"
+		}
+	}
+
+	body, err := s.storage.Get(ctx, storageKey)
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, NewServiceError(ErrCodeNotFound, "attachment not found")
+	}
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get attachment from storage: %v", err)
+	}
+
+	return &AttachmentContent{
+		Attachment:  *attachment,
+		ContentType: contentType,
+		Body:        body,
+		IsPublic:    isPostPublished(post, generator.NowISO8601()) && post.Visibility == env.PostVisibilityPublic,
+	}, nil
+}
+
+type DeleteAttachmentByIDParams struct {
+	User         repository.User
+	AttachmentID string
+}
+
+func (s *EndpointService) DeleteAttachmentByID(ctx context.Context, arg DeleteAttachmentByIDParams) error {
+	if arg.User.Role == env.UserRole {
+		return NewServiceError(ErrCodeForbidden, "insufficient permissions to delete attachment")
+	}
+
+	queries := repository.New(s.db)
+
+	attachment, err := getAttachmentByID(ctx, queries, arg.AttachmentID)
+	if err != nil {
+		return err
+	}
+
+	postList, err := queries.GetPostByID(ctx, attachment.PostID)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get post by ID: %v", err)
+	}
+
+	if len(postList) == 0 {
+		return NewServiceError(ErrCodeForbidden, "insufficient permissions to delete attachment")
+	}
+
+	err = checkPostRole(ctx, queries, arg.User, attachment.PostID, env.PostCollaboratorRoleEditor, "delete attachment")
+	if err != nil {
+		return err
+	}
+
+	err = queries.DeleteAttachmentByID(ctx, attachment.ID)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to delete attachment: %v", err)
+	}
+
+	s.deleteAttachmentObjects(ctx, *attachment)
+
+	return nil
+}
+
+func getAttachmentByID(ctx context.Context, queries *repository.Queries, attachmentID string) (*repository.Attachment, error) {
+	attachmentList, err := queries.GetAttachmentByID(ctx, attachmentID)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get attachment by ID: %v", err)
+	}
+
+	if len(attachmentList) == 0 {
+		return nil, NewServiceError(ErrCodeNotFound, "attachment not found")
+	}
+
+	if len(attachmentList) > 1 {
+		return nil, NewServiceError(ErrCodeInternal, "multiple attachments found with the same ID")
+	}
+
+	return &attachmentList[0], nil
+}
+
+// canViewPostAttachments reports whether the user, which is nil for anonymous
+// requests, can view the attachments of the post. Attachments follow the
+// visibility of their post, so the attachments of a paid post are only
+// available to entitled users.
+func canViewPostAttachments(ctx context.Context, queries *repository.Queries, user *repository.User, post repository.Post) (bool, error) {
+	canView, err := canViewPost(ctx, queries, user, post)
+	if err != nil || !canView {
+		return false, err
+	}
+
+	return canReadPostContent(ctx, queries, user, post)
+}
+
+// deleteAttachmentObjects removes the stored objects of the attachment. The
+// database is the source of truth, so failures are only logged and leave
+// orphaned objects behind at worst.
+func (s *EndpointService) deleteAttachmentObjects(ctx context.Context, attachment repository.Attachment) {
+	keys := []string{attachment.StorageKey}
+	if attachment.ThumbnailStorageKey != nil {
+		keys = append(keys, *attachment.ThumbnailStorageKey)
+	}
+
+	for _, key := range keys {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			slog.Error("Failed to delete attachment object " + key + ": " + err.Error())
+		}
+	}
+}
+
+// sanitizeFileName keeps only the base name of the uploaded file, which is
+// used for the download file name.
+func sanitizeFileName(fileName string) string {
+	fileName = strings.ReplaceAll(fileName, "\\", "/")
+	fileName = strings.TrimSpace(path.Base(fileName))
+	fileName = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '"' {
+			return -1
+		}
+		return r
+	}, fileName)
+
+	if fileName == "" || fileName == "." || fileName == "/" {
+		return "file"
+	}
+
+	if utf8.RuneCountInString(fileName) > attachmentFileNameLengthMax {
+		fileName = string([]rune(fileName)[:attachmentFileNameLengthMax])
+	}
+
+	return fileName
+}
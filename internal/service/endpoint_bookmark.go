@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/generator"
+	"github.com/jljl1337/issho/internal/repository"
+)
+
+type CreateBookmarkParams struct {
+	User   repository.User
+	PostID string
+}
+
+func (s *EndpointService) CreateBookmark(ctx context.Context, arg CreateBookmarkParams) error {
+	queries := repository.New(s.db)
+
+	postList, err := queries.GetPostByID(ctx, arg.PostID)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get post by ID: %v", err)
+	}
+
+	if len(postList) == 0 {
+		return NewServiceError(ErrCodeNotFound, "post not found")
+	}
+
+	canView, err := canViewPost(ctx, queries, &arg.User, postList[0])
+	if err != nil {
+		return err
+	}
+
+	if !canView {
+		return NewServiceError(ErrCodeNotFound, "post not found")
+	}
+
+	err = queries.CreateBookmark(ctx, repository.Bookmark{
+		UserID:    arg.User.ID,
+		PostID:    arg.PostID,
+		CreatedAt: generator.NowISO8601(),
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to create bookmark: %v", err)
+	}
+
+	return nil
+}
+
+type GetBookmarkListParams struct {
+	User           repository.User
+	AcceptLanguage string
+	Cursor         *string
+	CursorID       *string
+	Ascending      bool
+	PageSize       int
+}
+
+// BookmarkView is a bookmarked post with the time it was bookmarked, which is
+// the cursor of the next page.
+type BookmarkView struct {
+	PostView
+	BookmarkedAt string `json:"bookmarkedAt"`
+}
+
+// GetBookmarkList returns the posts bookmarked by the user. Bookmarks of posts
+// that are unpublished or in the trash are left out, but kept in case the post
+// is published or restored again.
+func (s *EndpointService) GetBookmarkList(ctx context.Context, arg GetBookmarkListParams) ([]BookmarkView, error) {
+	if arg.PageSize <= 0 || arg.PageSize > env.PageSizeMax {
+		arg.PageSize = env.PageSizeDefault
+	}
+
+	queries := repository.New(s.db)
+
+	bookmarkedPosts, err := queries.GetBookmarkedPostList(ctx, repository.GetBookmarkedPostListParams{
+		UserID:    arg.User.ID,
+		Ascending: arg.Ascending,
+		Now:       generator.NowISO8601(),
+		PageSize:  arg.PageSize,
+		Cursor:    arg.Cursor,
+		CursorID:  arg.CursorID,
+	})
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get bookmarked post list: %v", err)
+	}
+
+	posts := make([]repository.Post, len(bookmarkedPosts))
+	for i, bookmarkedPost := range bookmarkedPosts {
+		posts[i] = bookmarkedPost.Post
+	}
+
+	postViews, err := toPostViews(ctx, queries, posts, &arg.User, preferredLanguageCodes(&arg.User, arg.AcceptLanguage))
+	if err != nil {
+		return nil, err
+	}
+
+	bookmarkViews := make([]BookmarkView, len(postViews))
+	for i, postView := range postViews {
+		bookmarkViews[i] = BookmarkView{
+			PostView:     postView,
+			BookmarkedAt: bookmarkedPosts[i].BookmarkedAt,
+		}
+	}
+
+	return bookmarkViews, nil
+}
+
+type DeleteBookmarkParams struct {
+	User   repository.User
+	PostID string
+}
+
+func (s *EndpointService) DeleteBookmark(ctx context.Context, arg DeleteBookmarkParams) error {
+	queries := repository.New(s.db)
+
+	rows, err := queries.DeleteBookmark(ctx, repository.DeleteBookmarkParams{
+		UserID: arg.User.ID,
+		PostID: arg.PostID,
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to delete bookmark: %v", err)
+	}
+
+	if rows == 0 {
+		return NewServiceError(ErrCodeNotFound, "bookmark not found")
+	}
+
+	return nil
+}
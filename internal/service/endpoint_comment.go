@@ -0,0 +1,477 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/format"
+	"github.com/jljl1337/issho/internal/generator"
+	"github.com/jljl1337/issho/internal/repository"
+)
+
+var mapCommentModerationStatusAllowed = map[string]bool{
+	env.CommentStatusApproved: true,
+	env.CommentStatusRejected: true,
+	env.CommentStatusSpam:     true,
+}
+
+var mapCommentStatusAllowed = map[string]bool{
+	env.CommentStatusPending:  true,
+	env.CommentStatusApproved: true,
+	env.CommentStatusRejected: true,
+	env.CommentStatusSpam:     true,
+}
+
+type CreateCommentParams struct {
+	User     repository.User
+	PostID   string
+	ParentID *string
+	Content  string
+}
+
+func (s *EndpointService) CreateComment(ctx context.Context, arg CreateCommentParams) error {
+	if !arg.User.IsVerified {
+		return NewServiceError(ErrCodeForbidden, "only verified users can comment")
+	}
+
+	content, err := checkCommentContent(arg.Content)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to begin transaction: %v", err)
+	}
+
+	defer tx.Rollback()
+
+	queries := repository.New(tx)
+
+	now := generator.NowISO8601()
+
+	postList, err := queries.GetPostByID(ctx, arg.PostID)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get post by ID: %v", err)
+	}
+
+	// Comments are only allowed on published posts, regardless of role
+	if len(postList) == 0 || !isPostPublished(postList[0], now) {
+		return NewServiceError(ErrCodeNotFound, "post not found")
+	}
+
+	post := postList[0]
+
+	if arg.ParentID != nil {
+		parentList, err := queries.GetCommentByID(ctx, *arg.ParentID)
+		if err != nil {
+			return NewServiceErrorf(ErrCodeInternal, "failed to get parent comment by ID: %v", err)
+		}
+
+		if len(parentList) == 0 || parentList[0].PostID != post.ID {
+			return NewServiceError(ErrCodeNotFound, "parent comment not found")
+		}
+
+		parent := parentList[0]
+
+		if parent.DeletedAt != nil || parent.Status != env.CommentStatusApproved {
+			return NewServiceError(ErrCodeUnprocessable, "cannot reply to a comment that is not visible")
+		}
+	}
+
+	// Comments from owners skip the moderation queue
+	status := env.CommentStatusPending
+	if arg.User.Role == env.OwnerRole {
+		status = env.CommentStatusApproved
+	}
+
+	err = queries.CreateComment(ctx, repository.Comment{
+		ID:        generator.NewULID(),
+		PostID:    post.ID,
+		UserID:    &arg.User.ID,
+		ParentID:  arg.ParentID,
+		Content:   content,
+		Status:    status,
+		DeletedAt: nil,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to create comment: %v", err)
+	}
+
+	// Held comments are notified once approved, so that unmoderated content
+	// never reaches the author's inbox
+	if status == env.CommentStatusApproved {
+		err = queueCommentNotification(ctx, queries, post, arg.User, content)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+type GetCommentListParams struct {
+	User      repository.User
+	PostID    string
+	Cursor    *string
+	CursorID  *string
+	Ascending bool
+	PageSize  int
+}
+
+// GetCommentList returns the comments of a post that the user can see, i.e.
+// approved comments and the user's own comments. Deleted comments are kept in
+// the list so that replies can still be threaded, but their content is hidden.
+func (s *EndpointService) GetCommentList(ctx context.Context, arg GetCommentListParams) ([]repository.Comment, error) {
+	if arg.PageSize <= 0 || arg.PageSize > env.PageSizeMax {
+		arg.PageSize = env.PageSizeDefault
+	}
+
+	queries := repository.New(s.db)
+
+	postList, err := queries.GetPostByID(ctx, arg.PostID)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get post by ID: %v", err)
+	}
+
+	if len(postList) == 0 || !isPostPublished(postList[0], generator.NowISO8601()) {
+		return nil, NewServiceError(ErrCodeNotFound, "post not found")
+	}
+
+	status := env.CommentStatusApproved
+
+	comments, err := queries.GetCommentList(ctx, repository.GetCommentListParams{
+		PostID:    &arg.PostID,
+		Status:    &status,
+		ViewerID:  &arg.User.ID,
+		Ascending: arg.Ascending,
+		PageSize:  arg.PageSize,
+		Cursor:    arg.Cursor,
+		CursorID:  arg.CursorID,
+	})
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get comment list: %v", err)
+	}
+
+	for i := range comments {
+		hideDeletedCommentContent(&comments[i])
+	}
+
+	return comments, nil
+}
+
+type GetModerationCommentListParams struct {
+	User      repository.User
+	Status    string
+	Cursor    *string
+	CursorID  *string
+	Ascending bool
+	PageSize  int
+}
+
+// GetModerationCommentList returns comments across all posts with the given
+// status, which defaults to pending, for owners to moderate.
+func (s *EndpointService) GetModerationCommentList(ctx context.Context, arg GetModerationCommentListParams) ([]repository.Comment, error) {
+	if arg.User.Role == env.UserRole {
+		return nil, NewServiceError(ErrCodeForbidden, "insufficient permissions to moderate comments")
+	}
+
+	if arg.Status == "" {
+		arg.Status = env.CommentStatusPending
+	}
+
+	if !mapCommentStatusAllowed[arg.Status] {
+		return nil, NewServiceError(ErrCodeUnprocessable, "invalid comment status")
+	}
+
+	if arg.PageSize <= 0 || arg.PageSize > env.PageSizeMax {
+		arg.PageSize = env.PageSizeDefault
+	}
+
+	queries := repository.New(s.db)
+
+	comments, err := queries.GetCommentList(ctx, repository.GetCommentListParams{
+		PostID:    nil,
+		Status:    &arg.Status,
+		ViewerID:  nil,
+		Ascending: arg.Ascending,
+		PageSize:  arg.PageSize,
+		Cursor:    arg.Cursor,
+		CursorID:  arg.CursorID,
+	})
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get comment list: %v", err)
+	}
+
+	return comments, nil
+}
+
+type UpdateCommentByIDParams struct {
+	User      repository.User
+	CommentID string
+	Content   string
+	Versions  []string
+}
+
+func (s *EndpointService) UpdateCommentByID(ctx context.Context, arg UpdateCommentByIDParams) error {
+	content, err := checkCommentContent(arg.Content)
+	if err != nil {
+		return err
+	}
+
+	queries := repository.New(s.db)
+
+	comment, err := getCommentByID(ctx, queries, arg.CommentID)
+	if err != nil {
+		return err
+	}
+
+	if comment.UserID == nil || *comment.UserID != arg.User.ID {
+		return NewServiceError(ErrCodeForbidden, "insufficient permissions to update comment")
+	}
+
+	if comment.DeletedAt != nil {
+		return NewServiceError(ErrCodeNotFound, "comment not found")
+	}
+
+	err = checkVersion("comment", comment.UpdatedAt, arg.Versions)
+	if err != nil {
+		return err
+	}
+
+	createdAt, err := format.ISO8601ToTime(comment.CreatedAt)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to parse comment creation time: %v", err)
+	}
+
+	editWindow := time.Duration(env.CommentEditWindowMin) * time.Minute
+	if time.Since(createdAt) > editWindow {
+		return NewServiceError(ErrCodeCommentEditWindowExpired, "comment can no longer be edited")
+	}
+
+	// Edited comments from regular users go through moderation again
+	status := comment.Status
+	if arg.User.Role == env.UserRole {
+		status = env.CommentStatusPending
+	}
+
+	rowsAffected, err := queries.UpdateCommentContentByID(ctx, repository.UpdateCommentContentByIDParams{
+		Content:   content,
+		Status:    status,
+		UpdatedAt: generator.NowISO8601(),
+		ID:        comment.ID,
+		Version:   versionCondition(comment.UpdatedAt, arg.Versions),
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to update comment: %v", err)
+	}
+
+	err = checkVersionUpdated("comment", rowsAffected)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+type ModerateCommentByIDParams struct {
+	User      repository.User
+	CommentID string
+	Status    string
+	Versions  []string
+}
+
+func (s *EndpointService) ModerateCommentByID(ctx context.Context, arg ModerateCommentByIDParams) error {
+	if arg.User.Role == env.UserRole {
+		return NewServiceError(ErrCodeForbidden, "insufficient permissions to moderate comments")
+	}
+
+	if !mapCommentModerationStatusAllowed[arg.Status] {
+		return NewServiceError(ErrCodeUnprocessable, "invalid comment status")
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to begin transaction: %v", err)
+	}
+
+	defer tx.Rollback()
+
+	queries := repository.New(tx)
+
+	comment, err := getCommentByID(ctx, queries, arg.CommentID)
+	if err != nil {
+		return err
+	}
+
+	if comment.DeletedAt != nil {
+		return NewServiceError(ErrCodeNotFound, "comment not found")
+	}
+
+	err = checkVersion("comment", comment.UpdatedAt, arg.Versions)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := queries.UpdateCommentStatusByID(ctx, repository.UpdateCommentStatusByIDParams{
+		Status:    arg.Status,
+		UpdatedAt: generator.NowISO8601(),
+		ID:        comment.ID,
+		Version:   versionCondition(comment.UpdatedAt, arg.Versions),
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to update comment status: %v", err)
+	}
+
+	err = checkVersionUpdated("comment", rowsAffected)
+	if err != nil {
+		return err
+	}
+
+	// Notify the post author of a comment held for moderation once it is approved
+	if arg.Status == env.CommentStatusApproved && comment.Status == env.CommentStatusPending && comment.UserID != nil {
+		postList, err := queries.GetPostByID(ctx, comment.PostID)
+		if err != nil {
+			return NewServiceErrorf(ErrCodeInternal, "failed to get post by ID: %v", err)
+		}
+
+		if len(postList) == 0 {
+			return NewServiceError(ErrCodeNotFound, "post not found")
+		}
+
+		commenter, err := queries.GetUserByID(ctx, *comment.UserID)
+		if err != nil {
+			return NewServiceErrorf(ErrCodeInternal, "failed to get commenter: %v", err)
+		}
+
+		err = queueCommentNotification(ctx, queries, postList[0], commenter, comment.Content)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+type DeleteCommentByIDParams struct {
+	User      repository.User
+	CommentID string
+}
+
+func (s *EndpointService) DeleteCommentByID(ctx context.Context, arg DeleteCommentByIDParams) error {
+	queries := repository.New(s.db)
+
+	comment, err := getCommentByID(ctx, queries, arg.CommentID)
+	if err != nil {
+		return err
+	}
+
+	isCommenter := comment.UserID != nil && *comment.UserID == arg.User.ID
+	if !isCommenter && arg.User.Role == env.UserRole {
+		return NewServiceError(ErrCodeForbidden, "insufficient permissions to delete comment")
+	}
+
+	if comment.DeletedAt != nil {
+		return NewServiceError(ErrCodeNotFound, "comment not found")
+	}
+
+	now := generator.NowISO8601()
+
+	err = queries.SoftDeleteCommentByID(ctx, repository.SoftDeleteCommentByIDParams{
+		DeletedAt: now,
+		UpdatedAt: now,
+		ID:        comment.ID,
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to delete comment: %v", err)
+	}
+
+	return nil
+}
+
+// queueCommentNotification queues an email notifying the post author of an
+// approved comment, unless they are commenting on their own post.
+func queueCommentNotification(ctx context.Context, queries *repository.Queries, post repository.Post, commenter repository.User, content string) error {
+	if post.UserID == nil || *post.UserID == commenter.ID {
+		return nil
+	}
+
+	author, err := queries.GetUserByID(ctx, *post.UserID)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get post author: %v", err)
+	}
+
+	err = queueEmail(ctx, queries, queueEmailParams{
+		Type:      env.EmailTypeNewComment,
+		ToAddress: author.Email,
+		Subject:   "New comment on your post",
+		Body: fmt.Sprintf(
+			"%s commented on \"%s\":<br><br>%s",
+			html.EscapeString(commenter.Username),
+			html.EscapeString(post.Title),
+			html.EscapeString(content),
+		),
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to queue comment notification: %v", err)
+	}
+
+	return nil
+}
+
+func getCommentByID(ctx context.Context, queries *repository.Queries, commentID string) (*repository.Comment, error) {
+	commentList, err := queries.GetCommentByID(ctx, commentID)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get comment by ID: %v", err)
+	}
+
+	if len(commentList) == 0 {
+		return nil, NewServiceError(ErrCodeNotFound, "comment not found")
+	}
+
+	if len(commentList) > 1 {
+		return nil, NewServiceError(ErrCodeInternal, "multiple comments found with the same ID")
+	}
+
+	return &commentList[0], nil
+}
+
+// checkCommentContent trims the content and validates its length, returning
+// the trimmed content.
+func checkCommentContent(content string) (string, error) {
+	content = strings.TrimSpace(content)
+
+	if content == "" {
+		return "", NewServiceError(ErrCodeUnprocessable, "comment content is required")
+	}
+
+	if utf8.RuneCountInString(content) > env.CommentContentLengthMax {
+		return "", NewServiceErrorf(ErrCodeUnprocessable, "comment content must be at most %d characters", env.CommentContentLengthMax)
+	}
+
+	return content, nil
+}
+
+func hideDeletedCommentContent(comment *repository.Comment) {
+	if comment.DeletedAt != nil {
+		comment.Content = ""
+		comment.UserID = nil
+	}
+}
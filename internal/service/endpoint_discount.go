@@ -0,0 +1,705 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/format"
+	"github.com/jljl1337/issho/internal/generator"
+	"github.com/jljl1337/issho/internal/payment"
+	"github.com/jljl1337/issho/internal/repository"
+)
+
+var discountCodePattern = regexp.MustCompile(`^[A-Z0-9_-]{3,32}$`)
+
+// discountReservationGrace is how long a reservation of a discount outlives
+// its checkout, so that a purchase completed right before the checkout expires
+// can still be synced with its reservation.
+const discountReservationGrace = 10 * time.Minute
+
+// DiscountView is a discount with the IDs of the prices it can be redeemed
+// for, which is every price if there are none.
+type DiscountView struct {
+	repository.Discount
+	PriceIDs []string `json:"priceIds"`
+}
+
+type CreateDiscountParams struct {
+	User             repository.User
+	Code             string
+	Name             string
+	Type             string
+	Amount           int
+	Currency         *string
+	Duration         string
+	DurationInMonths *int
+	RedemptionMax    *int
+	ExpiresAt        *string
+	PriceIDs         []string
+}
+
+// CreateDiscount creates a discount with the payment provider. Codes are case
+// insensitive, and stored in upper case. The discount is created at the
+// provider first, which gives it its external ID, so a discount failing to be
+// stored afterwards is only reported by the payment reconciliation.
+func (s *EndpointService) CreateDiscount(ctx context.Context, arg CreateDiscountParams) error {
+	if arg.User.Role != env.OwnerRole {
+		return NewServiceError(ErrCodeForbidden, "insufficient permissions to create discount")
+	}
+
+	code := strings.ToUpper(strings.TrimSpace(arg.Code))
+	if !discountCodePattern.MatchString(code) {
+		return NewServiceError(ErrCodeUnprocessable, "code must be 3 to 32 letters, digits, hyphens or underscores")
+	}
+
+	if arg.Name == "" {
+		return NewServiceError(ErrCodeUnprocessable, "name is required")
+	}
+
+	err := checkDiscountAmount(arg.Type, arg.Amount, arg.Currency)
+	if err != nil {
+		return err
+	}
+
+	err = checkDiscountDuration(arg.Duration, arg.DurationInMonths)
+	if err != nil {
+		return err
+	}
+
+	err = checkDiscountLimits(arg.RedemptionMax, 0, arg.ExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	queries := repository.New(s.db)
+
+	discountList, err := queries.GetDiscountByCode(ctx, code)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get discount by code: %v", err)
+	}
+
+	if len(discountList) > 0 {
+		return NewServiceError(ErrCodeDiscountCodeTaken, "code already exists")
+	}
+
+	err = checkDiscountPrices(ctx, queries, arg.PriceIDs)
+	if err != nil {
+		return err
+	}
+
+	externalID, err := s.paymentProvider.CreateDiscount(ctx, payment.CreateDiscountParams{
+		Code:             code,
+		Name:             arg.Name,
+		Type:             arg.Type,
+		Amount:           arg.Amount,
+		Currency:         arg.Currency,
+		Duration:         arg.Duration,
+		DurationInMonths: arg.DurationInMonths,
+		RedemptionMax:    arg.RedemptionMax,
+		ExpiresAt:        arg.ExpiresAt,
+	})
+	if err != nil {
+		return newPaymentProviderError(err, "failed to create discount in payment provider")
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to begin transaction: %v", err)
+	}
+
+	defer tx.Rollback()
+
+	queries = repository.New(tx)
+
+	now := generator.NowISO8601()
+	id := generator.NewULID()
+
+	err = queries.CreateDiscount(ctx, repository.Discount{
+		ID:               id,
+		ExternalID:       externalID,
+		Code:             code,
+		Name:             arg.Name,
+		Type:             arg.Type,
+		Amount:           arg.Amount,
+		Currency:         arg.Currency,
+		Duration:         arg.Duration,
+		DurationInMonths: arg.DurationInMonths,
+		RedemptionMax:    arg.RedemptionMax,
+		RedemptionCount:  0,
+		ReservedCount:    0,
+		ExpiresAt:        arg.ExpiresAt,
+		IsActive:         true,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to create discount: %v", err)
+	}
+
+	err = createDiscountPrices(ctx, queries, id, arg.PriceIDs, now)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+type GetDiscountListParams struct {
+	User     repository.User
+	Cursor   *string
+	CursorID *string
+	PageSize int
+}
+
+func (s *EndpointService) GetDiscountList(ctx context.Context, arg GetDiscountListParams) ([]DiscountView, error) {
+	if arg.User.Role != env.OwnerRole {
+		return nil, NewServiceError(ErrCodeForbidden, "insufficient permissions to get discounts")
+	}
+
+	if arg.PageSize <= 0 || arg.PageSize > env.PageSizeMax {
+		arg.PageSize = env.PageSizeDefault
+	}
+
+	queries := repository.New(s.db)
+
+	discounts, err := queries.GetDiscountList(ctx, repository.GetDiscountListParams{
+		Cursor:   arg.Cursor,
+		CursorID: arg.CursorID,
+		PageSize: arg.PageSize,
+	})
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get discount list: %v", err)
+	}
+
+	return getDiscountViewList(ctx, queries, discounts)
+}
+
+type GetDiscountByIDParams struct {
+	User       repository.User
+	DiscountID string
+}
+
+func (s *EndpointService) GetDiscountByID(ctx context.Context, arg GetDiscountByIDParams) (*DiscountView, error) {
+	if arg.User.Role != env.OwnerRole {
+		return nil, NewServiceError(ErrCodeForbidden, "insufficient permissions to get discount")
+	}
+
+	queries := repository.New(s.db)
+
+	discount, err := getDiscountByID(ctx, queries, arg.DiscountID)
+	if err != nil {
+		return nil, err
+	}
+
+	views, err := getDiscountViewList(ctx, queries, []repository.Discount{*discount})
+	if err != nil {
+		return nil, err
+	}
+
+	return &views[0], nil
+}
+
+type UpdateDiscountByIDParams struct {
+	User          repository.User
+	DiscountID    string
+	Name          string
+	RedemptionMax *int
+	ExpiresAt     *string
+	IsActive      bool
+	PriceIDs      []string
+	Versions      []string
+}
+
+// UpdateDiscountByID updates the name, limits, active status and eligible
+// prices of the discount. The amount and duration of a discount cannot be
+// changed, as checkouts already created may have applied it. The discount is
+// updated before the payment provider, so that a version conflict fails before
+// the provider is changed, and is restored if the provider fails to update it.
+func (s *EndpointService) UpdateDiscountByID(ctx context.Context, arg UpdateDiscountByIDParams) error {
+	if arg.User.Role != env.OwnerRole {
+		return NewServiceError(ErrCodeForbidden, "insufficient permissions to update discount")
+	}
+
+	if arg.Name == "" {
+		return NewServiceError(ErrCodeUnprocessable, "name is required")
+	}
+
+	queries := repository.New(s.db)
+
+	discount, err := getDiscountByID(ctx, queries, arg.DiscountID)
+	if err != nil {
+		return err
+	}
+
+	err = checkVersion("discount", discount.UpdatedAt, arg.Versions)
+	if err != nil {
+		return err
+	}
+
+	// An unchanged expiry is not checked, as it may have passed already
+	newExpiresAt := arg.ExpiresAt
+	if newExpiresAt != nil && discount.ExpiresAt != nil && *newExpiresAt == *discount.ExpiresAt {
+		newExpiresAt = nil
+	}
+
+	err = checkDiscountLimits(arg.RedemptionMax, discount.RedemptionCount, newExpiresAt)
+	if err != nil {
+		return err
+	}
+
+	err = checkDiscountPrices(ctx, queries, arg.PriceIDs)
+	if err != nil {
+		return err
+	}
+
+	discountPriceList, err := queries.GetDiscountPriceListByDiscountIDs(ctx, []string{discount.ID})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get discount prices: %v", err)
+	}
+
+	priceIDs := []string{}
+	for _, discountPrice := range discountPriceList {
+		priceIDs = append(priceIDs, discountPrice.PriceID)
+	}
+
+	now := generator.NowISO8601()
+
+	err = s.saveDiscount(ctx, repository.UpdateDiscountParams{
+		Name:          arg.Name,
+		RedemptionMax: arg.RedemptionMax,
+		ExpiresAt:     arg.ExpiresAt,
+		IsActive:      arg.IsActive,
+		UpdatedAt:     now,
+		ID:            discount.ID,
+		Version:       versionCondition(discount.UpdatedAt, arg.Versions),
+	}, arg.PriceIDs)
+	if err != nil {
+		return err
+	}
+
+	err = s.paymentProvider.UpdateDiscount(ctx, payment.UpdateDiscountParams{
+		ExternalID:    discount.ExternalID,
+		Name:          arg.Name,
+		RedemptionMax: arg.RedemptionMax,
+		ExpiresAt:     arg.ExpiresAt,
+	})
+	if err != nil {
+		// Changes made to the discount meanwhile are kept
+		restoreErr := s.saveDiscount(ctx, repository.UpdateDiscountParams{
+			Name:          discount.Name,
+			RedemptionMax: discount.RedemptionMax,
+			ExpiresAt:     discount.ExpiresAt,
+			IsActive:      discount.IsActive,
+			UpdatedAt:     generator.NowISO8601(),
+			ID:            discount.ID,
+			Version:       &now,
+		}, priceIDs)
+		if restoreErr != nil {
+			slog.Error("Failed to restore discount ID " + discount.ID + ": " + restoreErr.Error())
+		}
+
+		return newPaymentProviderError(err, "failed to update discount in payment provider")
+	}
+
+	return nil
+}
+
+// saveDiscount updates the discount and replaces its eligible prices in one
+// transaction.
+func (s *EndpointService) saveDiscount(ctx context.Context, arg repository.UpdateDiscountParams, priceIDs []string) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to begin transaction: %v", err)
+	}
+
+	defer tx.Rollback()
+
+	queries := repository.New(tx)
+
+	rowsAffected, err := queries.UpdateDiscount(ctx, arg)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to update discount: %v", err)
+	}
+
+	err = checkVersionUpdated("discount", rowsAffected)
+	if err != nil {
+		return err
+	}
+
+	_, err = queries.DeleteDiscountPriceByDiscountID(ctx, arg.ID)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to delete discount prices: %v", err)
+	}
+
+	err = createDiscountPrices(ctx, queries, arg.ID, priceIDs, arg.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+type DeleteDiscountByIDParams struct {
+	User       repository.User
+	DiscountID string
+}
+
+// DeleteDiscountByID deletes the discount with the payment provider. Purchases
+// already made with it are kept. A discount deleted at the provider but failing
+// to be deleted afterwards is only deactivated by the payment reconciliation.
+func (s *EndpointService) DeleteDiscountByID(ctx context.Context, arg DeleteDiscountByIDParams) error {
+	if arg.User.Role != env.OwnerRole {
+		return NewServiceError(ErrCodeForbidden, "insufficient permissions to delete discount")
+	}
+
+	queries := repository.New(s.db)
+
+	discount, err := getDiscountByID(ctx, queries, arg.DiscountID)
+	if err != nil {
+		return err
+	}
+
+	err = s.paymentProvider.DeleteDiscount(ctx, discount.ExternalID)
+	if err != nil {
+		return newPaymentProviderError(err, "failed to delete discount in payment provider")
+	}
+
+	err = queries.DeleteDiscountByID(ctx, discount.ID)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to delete discount: %v", err)
+	}
+
+	return nil
+}
+
+// getRedeemableDiscount returns the active discount of the code, if it can be
+// redeemed for the price.
+func getRedeemableDiscount(ctx context.Context, queries *repository.Queries, code string, price repository.Price) (*repository.Discount, error) {
+	discountList, err := queries.GetDiscountByCode(ctx, strings.ToUpper(strings.TrimSpace(code)))
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get discount by code: %v", err)
+	}
+
+	if len(discountList) == 0 || !discountList[0].IsActive {
+		return nil, NewServiceError(ErrCodeNotFound, "discount not found")
+	}
+
+	discount := discountList[0]
+
+	if discount.ExpiresAt != nil && *discount.ExpiresAt <= generator.NowISO8601() {
+		return nil, NewServiceError(ErrCodeDiscountExpired, "discount has expired")
+	}
+
+	if discount.RedemptionMax != nil && discount.RedemptionCount+discount.ReservedCount >= *discount.RedemptionMax {
+		return nil, NewServiceError(ErrCodeDiscountExhausted, "discount has been fully redeemed")
+	}
+
+	if discount.Currency != nil && *discount.Currency != price.PriceCurrency {
+		return nil, NewServiceError(ErrCodeDiscountIneligible, "discount is not in the currency of the price")
+	}
+
+	discountPriceList, err := queries.GetDiscountPriceListByDiscountIDs(ctx, []string{discount.ID})
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get discount prices: %v", err)
+	}
+
+	if len(discountPriceList) > 0 && !slices.ContainsFunc(discountPriceList, func(discountPrice repository.DiscountPrice) bool {
+		return discountPrice.PriceID == price.ID
+	}) {
+		return nil, NewServiceError(ErrCodeDiscountIneligible, "discount cannot be redeemed for the price")
+	}
+
+	return &discount, nil
+}
+
+// reserveDiscount reserves a redemption of the discount for a checkout of the
+// user, so that no more checkouts can be created with the discount than its
+// redemption max. The reservation expires after the checkout lifetime until it
+// is linked to the checkout, and is redeemed once the purchase is paid.
+func (s *EndpointService) reserveDiscount(ctx context.Context, discount repository.Discount, userID string) (*repository.DiscountReservation, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to begin transaction: %v", err)
+	}
+
+	defer tx.Rollback()
+
+	queries := repository.New(tx)
+
+	rowsAffected, err := queries.ReserveDiscount(ctx, discount.ID)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to reserve discount: %v", err)
+	}
+
+	// The discount may have been fully reserved by another checkout meanwhile
+	if rowsAffected == 0 {
+		return nil, NewServiceError(ErrCodeDiscountExhausted, "discount has been fully redeemed")
+	}
+
+	reservation := repository.DiscountReservation{
+		ID:                 generator.NewULID(),
+		DiscountID:         discount.ID,
+		UserID:             userID,
+		CheckoutExternalID: nil,
+		ExpiresAt:          generator.DurationFromNowISO8601(time.Duration(env.CheckoutLifetimeMin)*time.Minute + discountReservationGrace),
+		CreatedAt:          generator.NowISO8601(),
+	}
+
+	err = queries.CreateDiscountReservation(ctx, reservation)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to create discount reservation: %v", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to commit transaction: %v", err)
+	}
+
+	return &reservation, nil
+}
+
+// linkDiscountReservation links the reservation to the checkout it was made
+// for, and expires it with the checkout.
+func linkDiscountReservation(ctx context.Context, queries *repository.Queries, reservation repository.DiscountReservation, checkout payment.Checkout) error {
+	checkoutExpiresAt, err := format.ISO8601ToTime(checkout.ExpiresAt)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to parse checkout expiry: %v", err)
+	}
+
+	// A reservation released meanwhile is not linked, as the discount may have
+	// been reserved again by another checkout
+	_, err = queries.UpdateDiscountReservationCheckoutByID(ctx, repository.UpdateDiscountReservationCheckoutByIDParams{
+		CheckoutExternalID: checkout.ExternalID,
+		ExpiresAt:          format.TimeToISO8601(checkoutExpiresAt.Add(discountReservationGrace)),
+		ID:                 reservation.ID,
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to update discount reservation: %v", err)
+	}
+
+	return nil
+}
+
+// releaseDiscountReservation releases the reservation, unless it has already
+// been released or redeemed.
+func (s *EndpointService) releaseDiscountReservation(ctx context.Context, reservation repository.DiscountReservation) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to begin transaction: %v", err)
+	}
+
+	defer tx.Rollback()
+
+	queries := repository.New(tx)
+
+	rowsAffected, err := queries.DeleteDiscountReservationByID(ctx, reservation.ID)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to delete discount reservation: %v", err)
+	}
+
+	if rowsAffected == 0 {
+		return nil
+	}
+
+	err = queries.ReleaseDiscount(ctx, reservation.DiscountID)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to release discount: %v", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+// ReleaseExpiredDiscountReservations releases the reservations of discounts
+// of which the checkouts have expired without being paid, and returns the
+// number of reservations released.
+func (s *EndpointService) ReleaseExpiredDiscountReservations(ctx context.Context) (int, error) {
+	queries := repository.New(s.db)
+	now := generator.NowISO8601()
+
+	count := 0
+
+	for {
+		reservations, err := queries.GetExpiredDiscountReservationList(ctx, repository.GetExpiredDiscountReservationListParams{
+			Now:      now,
+			PageSize: env.PageSizeMax,
+		})
+		if err != nil {
+			return count, NewServiceErrorf(ErrCodeInternal, "failed to get expired discount reservations: %v", err)
+		}
+
+		if len(reservations) == 0 {
+			return count, nil
+		}
+
+		for _, reservation := range reservations {
+			err = s.releaseDiscountReservation(ctx, reservation)
+			if err != nil {
+				return count, err
+			}
+
+			count++
+		}
+	}
+}
+
+func getDiscountByID(ctx context.Context, queries *repository.Queries, discountID string) (*repository.Discount, error) {
+	discountList, err := queries.GetDiscountByID(ctx, discountID)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get discount by ID: %v", err)
+	}
+
+	if len(discountList) == 0 {
+		return nil, NewServiceError(ErrCodeNotFound, "discount not found")
+	}
+
+	if len(discountList) > 1 {
+		return nil, NewServiceError(ErrCodeInternal, "multiple discounts found with the same ID")
+	}
+
+	return &discountList[0], nil
+}
+
+func getDiscountViewList(ctx context.Context, queries *repository.Queries, discounts []repository.Discount) ([]DiscountView, error) {
+	discountIDs := []string{}
+	for _, discount := range discounts {
+		discountIDs = append(discountIDs, discount.ID)
+	}
+
+	discountPriceList, err := queries.GetDiscountPriceListByDiscountIDs(ctx, discountIDs)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get discount prices: %v", err)
+	}
+
+	mapPriceIDs := make(map[string][]string)
+	for _, discountPrice := range discountPriceList {
+		mapPriceIDs[discountPrice.DiscountID] = append(mapPriceIDs[discountPrice.DiscountID], discountPrice.PriceID)
+	}
+
+	views := []DiscountView{}
+	for _, discount := range discounts {
+		priceIDs := mapPriceIDs[discount.ID]
+		if priceIDs == nil {
+			priceIDs = []string{}
+		}
+
+		views = append(views, DiscountView{
+			Discount: discount,
+			PriceIDs: priceIDs,
+		})
+	}
+
+	return views, nil
+}
+
+func createDiscountPrices(ctx context.Context, queries *repository.Queries, discountID string, priceIDs []string, now string) error {
+	for _, priceID := range priceIDs {
+		err := queries.CreateDiscountPrice(ctx, repository.DiscountPrice{
+			DiscountID: discountID,
+			PriceID:    priceID,
+			CreatedAt:  now,
+		})
+		if err != nil {
+			return NewServiceErrorf(ErrCodeInternal, "failed to create discount price: %v", err)
+		}
+	}
+
+	return nil
+}
+
+func checkDiscountAmount(discountType string, amount int, currency *string) error {
+	switch discountType {
+	case env.DiscountTypePercentage:
+		if amount <= 0 || amount > 100 {
+			return NewServiceError(ErrCodeUnprocessable, "percentage must be between 1 and 100")
+		}
+		if currency != nil {
+			return NewServiceError(ErrCodeUnprocessable, "currency must be nil for percentage discounts")
+		}
+	case env.DiscountTypeFixed:
+		if amount <= 0 {
+			return NewServiceError(ErrCodeUnprocessable, "amount must be greater than 0")
+		}
+		if currency == nil {
+			return NewServiceError(ErrCodeUnprocessable, "currency is required for fixed discounts")
+		}
+		return checkAmountAndCurrency(amount, *currency)
+	default:
+		return NewServiceError(ErrCodeUnprocessable, "discount type is not supported")
+	}
+
+	return nil
+}
+
+func checkDiscountDuration(duration string, durationInMonths *int) error {
+	switch duration {
+	case env.DiscountDurationOnce, env.DiscountDurationForever:
+		if durationInMonths != nil {
+			return NewServiceError(ErrCodeUnprocessable, "duration in months must be nil unless the duration is repeating")
+		}
+	case env.DiscountDurationRepeating:
+		if durationInMonths == nil || *durationInMonths <= 0 {
+			return NewServiceError(ErrCodeUnprocessable, "duration in months must be greater than 0 for repeating discounts")
+		}
+	default:
+		return NewServiceError(ErrCodeUnprocessable, "discount duration is not supported")
+	}
+
+	return nil
+}
+
+func checkDiscountLimits(redemptionMax *int, redemptionCount int, expiresAt *string) error {
+	if redemptionMax != nil && (*redemptionMax <= 0 || *redemptionMax < redemptionCount) {
+		return NewServiceError(ErrCodeUnprocessable, "redemption max must be greater than 0 and not less than the redemption count")
+	}
+
+	if expiresAt != nil {
+		if _, err := format.ISO8601ToTime(*expiresAt); err != nil {
+			return NewServiceError(ErrCodeUnprocessable, "expiresAt must be in ISO 8601 format")
+		}
+
+		if *expiresAt <= generator.NowISO8601() {
+			return NewServiceError(ErrCodeUnprocessable, "expiresAt must be in the future")
+		}
+	}
+
+	return nil
+}
+
+func checkDiscountPrices(ctx context.Context, queries *repository.Queries, priceIDs []string) error {
+	for i, priceID := range priceIDs {
+		if slices.Contains(priceIDs[:i], priceID) {
+			return NewServiceError(ErrCodeUnprocessable, "price IDs must be unique")
+		}
+
+		priceList, err := queries.GetPriceByID(ctx, priceID)
+		if err != nil {
+			return NewServiceErrorf(ErrCodeInternal, "failed to get price by ID: %v", err)
+		}
+
+		if len(priceList) == 0 {
+			return NewServiceError(ErrCodeNotFound, "price not found")
+		}
+	}
+
+	return nil
+}
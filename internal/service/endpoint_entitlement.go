@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/generator"
+	"github.com/jljl1337/issho/internal/repository"
+)
+
+type CreateEntitlementParams struct {
+	User      repository.User
+	UserID    string
+	ProductID string
+	EndsAt    *string
+}
+
+// CreateEntitlement grants a user access to a product manually, e.g. for
+// complimentary memberships.
+func (s *EndpointService) CreateEntitlement(ctx context.Context, arg CreateEntitlementParams) error {
+	if arg.User.Role == env.UserRole {
+		return NewServiceError(ErrCodeForbidden, "insufficient permissions to create entitlement")
+	}
+
+	now := generator.NowISO8601()
+
+	if arg.EndsAt != nil && *arg.EndsAt <= now {
+		return NewServiceError(ErrCodeUnprocessable, "endsAt must be in the future")
+	}
+
+	queries := repository.New(s.db)
+
+	userList, err := queries.GetUserListByIDs(ctx, []string{arg.UserID})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get user by ID: %v", err)
+	}
+
+	if len(userList) == 0 {
+		return NewServiceError(ErrCodeNotFound, "user not found")
+	}
+
+	productList, err := queries.GetProductByID(ctx, arg.ProductID)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get product by ID: %v", err)
+	}
+
+	if len(productList) == 0 {
+		return NewServiceError(ErrCodeNotFound, "product not found")
+	}
+
+	err = queries.CreateEntitlement(ctx, repository.Entitlement{
+		ID:        generator.NewULID(),
+		UserID:    arg.UserID,
+		ProductID: arg.ProductID,
+		Source:    env.EntitlementSourceManual,
+		SourceID:  nil,
+		StartsAt:  now,
+		EndsAt:    arg.EndsAt,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to create entitlement: %v", err)
+	}
+
+	return nil
+}
+
+// HasEntitlement reports whether the user currently has an entitlement to the
+// product, regardless of whether it is granted manually or by a purchase.
+func (s *EndpointService) HasEntitlement(ctx context.Context, user repository.User, productID string) (bool, error) {
+	queries := repository.New(s.db)
+
+	entitlementList, err := queries.GetActiveEntitlementListByUserIDAndProductID(ctx, repository.GetActiveEntitlementListByUserIDAndProductIDParams{
+		UserID:    user.ID,
+		ProductID: productID,
+		Now:       generator.NowISO8601(),
+	})
+	if err != nil {
+		return false, NewServiceErrorf(ErrCodeInternal, "failed to get active entitlement list: %v", err)
+	}
+
+	return len(entitlementList) > 0, nil
+}
+
+type GetEntitlementListParams struct {
+	User      repository.User
+	UserID    *string
+	ProductID *string
+	Cursor    *string
+	CursorID  *string
+	PageSize  int
+}
+
+// GetEntitlementList returns the entitlements of all users for owners. Regular
+// users only get their own entitlements.
+func (s *EndpointService) GetEntitlementList(ctx context.Context, arg GetEntitlementListParams) ([]repository.Entitlement, error) {
+	if arg.User.Role == env.UserRole {
+		arg.UserID = &arg.User.ID
+	}
+
+	if arg.PageSize <= 0 || arg.PageSize > env.PageSizeMax {
+		arg.PageSize = env.PageSizeDefault
+	}
+
+	queries := repository.New(s.db)
+
+	entitlements, err := queries.GetEntitlementList(ctx, repository.GetEntitlementListParams{
+		UserID:    arg.UserID,
+		ProductID: arg.ProductID,
+		Ascending: false,
+		PageSize:  arg.PageSize,
+		Cursor:    arg.Cursor,
+		CursorID:  arg.CursorID,
+	})
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get entitlement list: %v", err)
+	}
+
+	return entitlements, nil
+}
+
+type DeleteEntitlementByIDParams struct {
+	User          repository.User
+	EntitlementID string
+}
+
+func (s *EndpointService) DeleteEntitlementByID(ctx context.Context, arg DeleteEntitlementByIDParams) error {
+	if arg.User.Role == env.UserRole {
+		return NewServiceError(ErrCodeForbidden, "insufficient permissions to delete entitlement")
+	}
+
+	queries := repository.New(s.db)
+
+	entitlementList, err := queries.GetEntitlementByID(ctx, arg.EntitlementID)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get entitlement by ID: %v", err)
+	}
+
+	if len(entitlementList) == 0 {
+		return NewServiceError(ErrCodeNotFound, "entitlement not found")
+	}
+
+	err = queries.DeleteEntitlementByID(ctx, arg.EntitlementID)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to delete entitlement: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/feed"
+	"github.com/jljl1337/issho/internal/format"
+	"github.com/jljl1337/issho/internal/generator"
+	"github.com/jljl1337/issho/internal/repository"
+)
+
+type GetFeedParams struct {
+	FeedURL        string
+	UserID         *string
+	Tag            *string
+	AcceptLanguage string
+}
+
+// GetFeed returns the latest posts that are visible to non-privileged users,
+// i.e. published and not scheduled in the future, as a feed.
+func (s *EndpointService) GetFeed(ctx context.Context, arg GetFeedParams) (*feed.Feed, error) {
+	if arg.Tag != nil {
+		tag := normalizeTag(*arg.Tag)
+		arg.Tag = &tag
+	}
+
+	queries := repository.New(s.db)
+
+	posts, err := queries.GetPostList(ctx, repository.GetPostListParams{
+		UserID:      arg.UserID,
+		Tag:         arg.Tag,
+		SearchQuery: nil,
+		OrderBy:     "published_at",
+		Ascending:   false,
+		IncludeAll:  false,
+		Now:         generator.NowISO8601(),
+		PageSize:    env.FeedItemCount,
+		Cursor:      nil,
+		CursorID:    nil,
+	})
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get post list: %v", err)
+	}
+
+	// Feeds are anonymous, so non-public posts only show their teaser
+	postViews, err := toPostViews(ctx, queries, posts, nil, preferredLanguageCodes(nil, arg.AcceptLanguage))
+	if err != nil {
+		return nil, err
+	}
+
+	authorIDs := []string{}
+	for _, post := range posts {
+		if post.UserID != nil {
+			authorIDs = append(authorIDs, *post.UserID)
+		}
+	}
+
+	authors, err := queries.GetUserListByIDs(ctx, authorIDs)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get post authors: %v", err)
+	}
+
+	mapUserIDToUsername := make(map[string]string)
+	for _, author := range authors {
+		mapUserIDToUsername[author.ID] = author.Username
+	}
+
+	result := &feed.Feed{
+		Title:       env.SiteTitle,
+		Description: env.SiteDescription,
+		Link:        env.SiteURL,
+		FeedURL:     arg.FeedURL,
+		UpdatedAt:   time.Unix(0, 0),
+		Items:       make([]feed.Item, len(postViews)),
+	}
+
+	for i, post := range postViews {
+		publishedAt, err := format.ISO8601ToTime(*post.PublishedAt)
+		if err != nil {
+			return nil, NewServiceErrorf(ErrCodeInternal, "failed to parse post publish time: %v", err)
+		}
+
+		updatedAt, err := format.ISO8601ToTime(post.UpdatedAt)
+		if err != nil {
+			return nil, NewServiceErrorf(ErrCodeInternal, "failed to parse post update time: %v", err)
+		}
+
+		// A scheduled post is last updated before it appears in the feed
+		if updatedAt.Before(publishedAt) {
+			updatedAt = publishedAt
+		}
+
+		if updatedAt.After(result.UpdatedAt) {
+			result.UpdatedAt = updatedAt
+		}
+
+		item := feed.Item{
+			ID:          post.ID,
+			Title:       post.Title,
+			Link:        env.SiteURL + "/posts/" + post.ID,
+			Description: post.Description,
+			Content:     post.Content,
+			AuthorName:  "",
+			Tags:        post.Tags,
+			PublishedAt: publishedAt,
+			UpdatedAt:   updatedAt,
+		}
+
+		if post.UserID != nil {
+			item.AuthorName = mapUserIDToUsername[*post.UserID]
+		}
+
+		result.Items[i] = item
+	}
+
+	return result, nil
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jljl1337/issho/internal/payment"
+	"github.com/jljl1337/issho/internal/repository"
+)
+
+// getLocalProvider returns the local payment provider, or an error as if the
+// checkout did not exist when another provider is used.
+func (s *EndpointService) getLocalProvider() (*payment.LocalProvider, error) {
+	provider, ok := s.paymentProvider.(*payment.LocalProvider)
+	if !ok {
+		return nil, NewServiceError(ErrCodeNotFound, "checkout not found")
+	}
+
+	return provider, nil
+}
+
+func toLocalCheckoutError(err error) error {
+	if errors.Is(err, payment.ErrLocalObjectNotFound) {
+		return NewServiceError(ErrCodeNotFound, "checkout not found")
+	}
+
+	if errors.Is(err, payment.ErrLocalCheckoutNotOpen) {
+		return NewServiceError(ErrCodeConflict, "checkout is not open")
+	}
+
+	return NewServiceErrorf(ErrCodeInternal, "failed to process local checkout: %v", err)
+}
+
+type GetLocalCheckoutParams struct {
+	CheckoutID string
+}
+
+func (s *EndpointService) GetLocalCheckout(ctx context.Context, arg GetLocalCheckoutParams) (*payment.LocalCheckout, error) {
+	provider, err := s.getLocalProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	checkout, err := provider.GetCheckout(ctx, arg.CheckoutID)
+	if err != nil {
+		return nil, toLocalCheckoutError(err)
+	}
+
+	return checkout, nil
+}
+
+type CompleteLocalCheckoutParams struct {
+	CheckoutID string
+}
+
+// CompleteLocalCheckout completes a checkout of the local provider without
+// payment, and receives its webhooks like those from a real provider. It
+// returns the URL to redirect to.
+func (s *EndpointService) CompleteLocalCheckout(ctx context.Context, arg CompleteLocalCheckoutParams) (string, error) {
+	provider, err := s.getLocalProvider()
+	if err != nil {
+		return "", err
+	}
+
+	checkout, webhooks, err := provider.CompleteCheckout(ctx, arg.CheckoutID)
+	if err != nil {
+		return "", toLocalCheckoutError(err)
+	}
+
+	for _, webhook := range webhooks {
+		err = s.ReceivePaymentWebhook(ctx, ReceivePaymentWebhookParams{
+			Provider: "local",
+			Header:   webhook.Header,
+			Body:     webhook.Body,
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return checkout.SuccessURL, nil
+}
+
+type CancelLocalCheckoutParams struct {
+	CheckoutID string
+}
+
+// CancelLocalCheckout cancels a checkout of the local provider, releasing the
+// reservation of its discount, and returns the URL to redirect to.
+func (s *EndpointService) CancelLocalCheckout(ctx context.Context, arg CancelLocalCheckoutParams) (string, error) {
+	provider, err := s.getLocalProvider()
+	if err != nil {
+		return "", err
+	}
+
+	checkout, err := provider.CancelCheckout(ctx, arg.CheckoutID)
+	if err != nil {
+		return "", toLocalCheckoutError(err)
+	}
+
+	reservations, err := repository.New(s.db).GetDiscountReservationByCheckoutExternalID(ctx, checkout.ID)
+	if err != nil {
+		return "", NewServiceErrorf(ErrCodeInternal, "failed to get discount reservation: %v", err)
+	}
+
+	for _, reservation := range reservations {
+		err = s.releaseDiscountReservation(ctx, reservation)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return checkout.CancelURL, nil
+}
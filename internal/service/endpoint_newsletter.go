@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/url"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/generator"
+	"github.com/jljl1337/issho/internal/repository"
+)
+
+// NewsletterStatus is whether the user is subscribed to the newsletter.
+type NewsletterStatus struct {
+	Subscribed bool `json:"subscribed"`
+}
+
+func (s *EndpointService) GetNewsletterStatus(ctx context.Context, user repository.User) (*NewsletterStatus, error) {
+	queries := repository.New(s.db)
+
+	subscriptions, err := queries.GetNewsletterSubscriptionByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get newsletter subscription: %v", err)
+	}
+
+	return &NewsletterStatus{
+		Subscribed: len(subscriptions) > 0,
+	}, nil
+}
+
+// SubscribeNewsletter subscribes the user to the newsletter. The email of the
+// user is already verified, so the subscription is confirmed right away, and
+// follows the user if they change their email.
+func (s *EndpointService) SubscribeNewsletter(ctx context.Context, user repository.User) error {
+	if user.Role == env.UserRole && !user.IsVerified {
+		return NewServiceError(ErrCodeUnprocessable, "email must be verified to subscribe")
+	}
+
+	queries := repository.New(s.db)
+
+	subscriptions, err := queries.GetNewsletterSubscriptionByUserID(ctx, user.ID)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get newsletter subscription: %v", err)
+	}
+
+	if len(subscriptions) > 0 {
+		return nil
+	}
+
+	now := generator.NowISO8601()
+
+	err = queries.CreateNewsletterSubscription(ctx, repository.NewsletterSubscription{
+		ID:        generator.NewULID(),
+		UserID:    &user.ID,
+		Email:     nil,
+		Token:     generator.NewToken(env.NewsletterTokenLength, env.NewsletterTokenCharset),
+		Status:    env.NewsletterSubscriptionStatusConfirmed,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to create newsletter subscription: %v", err)
+	}
+
+	return nil
+}
+
+func (s *EndpointService) UnsubscribeNewsletter(ctx context.Context, user repository.User) error {
+	queries := repository.New(s.db)
+
+	subscriptions, err := queries.GetNewsletterSubscriptionByUserID(ctx, user.ID)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get newsletter subscription: %v", err)
+	}
+
+	if len(subscriptions) == 0 {
+		return NewServiceError(ErrCodeNotFound, "newsletter subscription not found")
+	}
+
+	err = queries.DeleteNewsletterSubscriptionByID(ctx, subscriptions[0].ID)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to delete newsletter subscription: %v", err)
+	}
+
+	return nil
+}
+
+type CreateNewsletterSubscriptionParams struct {
+	Email string
+}
+
+// CreateNewsletterSubscription subscribes an email address without an account
+// to the newsletter. The subscription is pending until it is confirmed with
+// the link sent to the address. The result is the same whether or not the
+// address is already subscribed, so that subscriptions cannot be probed.
+func (s *EndpointService) CreateNewsletterSubscription(ctx context.Context, arg CreateNewsletterSubscriptionParams) error {
+	emailValid, err := checkEmail(arg.Email)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to validate email: %v", err)
+	}
+	if !emailValid {
+		return NewServiceError(ErrCodeUnprocessable, "invalid email format")
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to begin transaction: %v", err)
+	}
+
+	defer tx.Rollback()
+
+	queries := repository.New(tx)
+
+	subscriptions, err := queries.GetNewsletterSubscriptionByEmail(ctx, arg.Email)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get newsletter subscription: %v", err)
+	}
+
+	var token string
+	if len(subscriptions) > 0 {
+		subscription := subscriptions[0]
+
+		if subscription.Status == env.NewsletterSubscriptionStatusConfirmed {
+			return nil
+		}
+
+		// Send the confirmation link again for a pending subscription, at most
+		// once in a while, so that the address cannot be flooded with emails
+		if subscription.UpdatedAt > generator.MinutesFromNowISO8601(-env.NewsletterConfirmResendMin) {
+			return nil
+		}
+
+		err = queries.UpdateNewsletterSubscriptionStatusByID(ctx, repository.UpdateNewsletterSubscriptionStatusByIDParams{
+			ID:        subscription.ID,
+			Status:    env.NewsletterSubscriptionStatusPending,
+			UpdatedAt: generator.NowISO8601(),
+		})
+		if err != nil {
+			return NewServiceErrorf(ErrCodeInternal, "failed to update newsletter subscription status: %v", err)
+		}
+
+		token = subscription.Token
+	} else {
+		now := generator.NowISO8601()
+		token = generator.NewToken(env.NewsletterTokenLength, env.NewsletterTokenCharset)
+
+		err = queries.CreateNewsletterSubscription(ctx, repository.NewsletterSubscription{
+			ID:        generator.NewULID(),
+			UserID:    nil,
+			Email:     &arg.Email,
+			Token:     token,
+			Status:    env.NewsletterSubscriptionStatusPending,
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+		if err != nil {
+			return NewServiceErrorf(ErrCodeInternal, "failed to create newsletter subscription: %v", err)
+		}
+	}
+
+	confirmURL := env.SiteURL + "/api/newsletter/confirm?token=" + url.QueryEscape(token)
+
+	err = queueEmail(ctx, queries, queueEmailParams{
+		Type:      env.EmailTypeNewsletterConfirm,
+		ToAddress: arg.Email,
+		Subject:   "Confirm your subscription to " + env.SiteTitle,
+		Body: fmt.Sprintf(
+			"Please confirm your subscription to %s by opening the link below:<br><br><a href=\"%s\">%s</a><br><br>If you did not subscribe, you can ignore this email.",
+			html.EscapeString(env.SiteTitle),
+			html.EscapeString(confirmURL),
+			html.EscapeString(confirmURL),
+		),
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to queue newsletter confirmation: %v", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+type ConfirmNewsletterSubscriptionParams struct {
+	Token string
+}
+
+func (s *EndpointService) ConfirmNewsletterSubscription(ctx context.Context, arg ConfirmNewsletterSubscriptionParams) error {
+	queries := repository.New(s.db)
+
+	subscriptions, err := queries.GetNewsletterSubscriptionByToken(ctx, arg.Token)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get newsletter subscription: %v", err)
+	}
+
+	if len(subscriptions) == 0 {
+		return NewServiceError(ErrCodeNotFound, "newsletter subscription not found")
+	}
+
+	subscription := subscriptions[0]
+
+	if subscription.Status == env.NewsletterSubscriptionStatusConfirmed {
+		return nil
+	}
+
+	err = queries.UpdateNewsletterSubscriptionStatusByID(ctx, repository.UpdateNewsletterSubscriptionStatusByIDParams{
+		ID:        subscription.ID,
+		Status:    env.NewsletterSubscriptionStatusConfirmed,
+		UpdatedAt: generator.NowISO8601(),
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to update newsletter subscription status: %v", err)
+	}
+
+	return nil
+}
+
+type DeleteNewsletterSubscriptionByTokenParams struct {
+	Token string
+}
+
+// DeleteNewsletterSubscriptionByToken unsubscribes with the token in the
+// unsubscribe link of a newsletter email, which works for both users and
+// email addresses without an account.
+func (s *EndpointService) DeleteNewsletterSubscriptionByToken(ctx context.Context, arg DeleteNewsletterSubscriptionByTokenParams) error {
+	queries := repository.New(s.db)
+
+	subscriptions, err := queries.GetNewsletterSubscriptionByToken(ctx, arg.Token)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get newsletter subscription: %v", err)
+	}
+
+	if len(subscriptions) == 0 {
+		return NewServiceError(ErrCodeNotFound, "newsletter subscription not found")
+	}
+
+	err = queries.DeleteNewsletterSubscriptionByID(ctx, subscriptions[0].ID)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to delete newsletter subscription: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/repository"
+)
+
+type GetPaymentReconciliationIssueListParams struct {
+	User repository.User
+}
+
+// GetPaymentReconciliationIssueList returns the issues found by the last
+// payment reconciliation, including the ones fixed.
+func (s *EndpointService) GetPaymentReconciliationIssueList(ctx context.Context, arg GetPaymentReconciliationIssueListParams) ([]repository.PaymentReconciliationIssue, error) {
+	if arg.User.Role != env.OwnerRole {
+		return nil, NewServiceError(ErrCodeForbidden, "insufficient permissions to get payment reconciliation")
+	}
+
+	queries := repository.New(s.db)
+
+	issues, err := queries.GetPaymentReconciliationIssueList(ctx)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get payment reconciliation issue list: %v", err)
+	}
+
+	return issues, nil
+}
+
+type RunPaymentReconciliationParams struct {
+	User repository.User
+}
+
+// RunPaymentReconciliation runs the payment reconciliation without waiting for
+// the scheduled run, returning the issues found.
+func (s *EndpointService) RunPaymentReconciliation(ctx context.Context, arg RunPaymentReconciliationParams) ([]repository.PaymentReconciliationIssue, error) {
+	if arg.User.Role != env.OwnerRole {
+		return nil, NewServiceError(ErrCodeForbidden, "insufficient permissions to run payment reconciliation")
+	}
+
+	_, err := s.ReconcilePayments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetPaymentReconciliationIssueList(ctx, GetPaymentReconciliationIssueListParams{
+		User: arg.User,
+	})
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/generator"
+	"github.com/jljl1337/issho/internal/payment"
+	"github.com/jljl1337/issho/internal/repository"
+)
+
+type ReceivePaymentWebhookParams struct {
+	Provider string
+	Header   http.Header
+	Body     []byte
+}
+
+// ReceivePaymentWebhook verifies and stores a webhook event of the payment
+// provider, and queues it to be processed. An event that is already stored is
+// a retry or a replay, so it is accepted without being queued again.
+func (s *EndpointService) ReceivePaymentWebhook(ctx context.Context, arg ReceivePaymentWebhookParams) error {
+	if arg.Provider != env.PaymentProvider {
+		return NewServiceError(ErrCodeNotFound, "payment provider not found")
+	}
+
+	event, err := s.paymentProvider.VerifyWebhook(arg.Header, arg.Body)
+	if errors.Is(err, payment.ErrInvalidWebhookSignature) {
+		return NewServiceError(ErrCodeInvalidWebhookSignature, "invalid webhook signature")
+	}
+	if err != nil {
+		return NewServiceErrorf(ErrCodeBadRequest, "failed to verify webhook: %v", err)
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to begin transaction: %v", err)
+	}
+
+	defer tx.Rollback()
+
+	queries := repository.New(tx)
+
+	now := generator.NowISO8601()
+	id := generator.NewULID()
+
+	created, err := queries.CreatePaymentEvent(ctx, repository.PaymentEvent{
+		ID:        id,
+		Provider:  arg.Provider,
+		EventID:   event.ID,
+		Type:      event.Type,
+		Payload:   string(arg.Body),
+		Status:    env.PaymentEventStatusPending,
+		Error:     nil,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to create payment event: %v", err)
+	}
+
+	if !created {
+		return nil
+	}
+
+	err = queries.CreateQueueTask(ctx, repository.QueueTask{
+		ID:        generator.NewULID(),
+		Lane:      env.QueueTaskLanePaymentEvent,
+		Payload:   id,
+		Status:    env.QueueTaskStatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to create queue task: %v", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+// ProcessPaymentEvent syncs the subscription or order of the stored payment
+// event, and marks it as processed. Events about other objects, or about
+// purchases unknown to this application, are ignored. An event that is already
+// processed or ignored is skipped. Syncing stores the whole object, so
+// processing an event again is harmless. Failed events are retried, so an
+// event may be processed after a newer one, and the subscription is therefore
+// synced with its current state at the payment provider instead.
+func (s *EndpointService) ProcessPaymentEvent(ctx context.Context, eventID string) error {
+	queries := repository.New(s.db)
+
+	eventList, err := queries.GetPaymentEventByID(ctx, eventID)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get payment event by ID: %v", err)
+	}
+
+	if len(eventList) == 0 {
+		return NewServiceError(ErrCodeNotFound, "payment event not found")
+	}
+
+	event := eventList[0]
+
+	if event.Status == env.PaymentEventStatusProcessed || event.Status == env.PaymentEventStatusIgnored {
+		return nil
+	}
+
+	// The payment provider is called outside of the transaction
+	data, err := s.paymentProvider.ParseWebhookEvent(ctx, event.Type, []byte(event.Payload))
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to parse payment event: %v", err)
+	}
+
+	if data.Subscription != nil {
+		data.Subscription, err = s.getCurrentSubscription(ctx, *data.Subscription)
+		if err != nil {
+			return err
+		}
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to begin transaction: %v", err)
+	}
+
+	defer tx.Rollback()
+
+	queries = repository.New(tx)
+
+	synced := false
+	switch {
+	case data.Subscription != nil:
+		synced, err = syncSubscription(ctx, queries, *data.Subscription)
+	case data.Order != nil:
+		synced, err = syncOrder(ctx, queries, *data.Order)
+	}
+	if err != nil {
+		return err
+	}
+
+	status := env.PaymentEventStatusIgnored
+	if synced {
+		status = env.PaymentEventStatusProcessed
+	}
+
+	err = queries.UpdatePaymentEventStatusByID(ctx, repository.UpdatePaymentEventStatusByIDParams{
+		ID:        event.ID,
+		Status:    status,
+		Error:     nil,
+		UpdatedAt: generator.NowISO8601(),
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to update payment event status: %v", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+// getCurrentSubscription returns the current state of the subscription at the
+// payment provider, or the given state if the provider does not list it.
+func (s *EndpointService) getCurrentSubscription(ctx context.Context, subscription payment.Subscription) (*payment.Subscription, error) {
+	subscriptions, err := s.paymentProvider.GetSubscriptionList(ctx, subscription.CustomerExternalID)
+	if err != nil {
+		return nil, newPaymentProviderError(err, "failed to get subscriptions from payment provider")
+	}
+
+	for _, current := range subscriptions {
+		if current.ExternalID == subscription.ExternalID {
+			return &current, nil
+		}
+	}
+
+	return &subscription, nil
+}
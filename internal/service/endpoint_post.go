@@ -3,6 +3,8 @@ package service
 import (
 	"context"
 	"log/slog"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/jljl1337/issho/internal/env"
 	"github.com/jljl1337/issho/internal/generator"
@@ -10,11 +12,16 @@ import (
 )
 
 type CreatePostParams struct {
-	User        repository.User
-	Title       string
-	Description string
-	Content     string
-	PublishedAt *string
+	User         repository.User
+	Title        string
+	Description  string
+	Content      string
+	PublishedAt  *string
+	Tags         []string
+	Visibility   string
+	ProductIDs   []string
+	LanguageCode string
+	Slug         *string
 }
 
 func (s *EndpointService) CreatePost(ctx context.Context, arg CreatePostParams) error {
@@ -29,40 +36,106 @@ func (s *EndpointService) CreatePost(ctx context.Context, arg CreatePostParams)
 		arg.PublishedAt = &now
 	}
 
-	queries := repository.New(s.db)
+	if arg.Visibility == "" {
+		arg.Visibility = env.PostVisibilityPublic
+	}
+
+	// Posts are written in the language of the author by default
+	if arg.LanguageCode == "" {
+		arg.LanguageCode = arg.User.LanguageCode
+	}
+
+	if !checkLanguageCode(arg.LanguageCode) {
+		return NewServiceError(ErrCodeUnprocessable, "invalid language code")
+	}
+
+	tags, err := checkTags(arg.Tags)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to begin transaction: %v", err)
+	}
+
+	defer tx.Rollback()
+
+	queries := repository.New(tx)
+
+	productIDs, err := checkPostProducts(ctx, queries, arg.Visibility, arg.ProductIDs)
+	if err != nil {
+		return err
+	}
+
+	slug, err := checkPostSlug(ctx, queries, arg.Slug, "")
+	if err != nil {
+		return err
+	}
 
 	post := repository.Post{
-		ID:          generator.NewULID(),
-		UserID:      &arg.User.ID,
-		Title:       arg.Title,
-		Description: arg.Description,
-		Content:     arg.Content,
-		PublishedAt: arg.PublishedAt,
-		CreatedAt:   now,
-		UpdatedAt:   now,
+		ID:           generator.NewULID(),
+		UserID:       &arg.User.ID,
+		Title:        arg.Title,
+		Description:  arg.Description,
+		Content:      arg.Content,
+		PublishedAt:  arg.PublishedAt,
+		Visibility:   arg.Visibility,
+		LanguageCode: arg.LanguageCode,
+		Slug:         slug,
+		CreatedAt:    now,
+		UpdatedAt:    now,
 	}
 
-	err := queries.CreatePost(ctx, post)
+	err = queries.CreatePost(ctx, post)
 	if err != nil {
 		return NewServiceErrorf(ErrCodeInternal, "failed to create post: %v", err)
 	}
 
+	err = queries.UpsertPostCollaborator(ctx, repository.PostCollaborator{
+		PostID:    post.ID,
+		UserID:    arg.User.ID,
+		Role:      env.PostCollaboratorRoleAuthor,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to create post collaborator: %v", err)
+	}
+
+	err = setPostTags(ctx, queries, post.ID, tags)
+	if err != nil {
+		return err
+	}
+
+	err = setPostProducts(ctx, queries, post.ID, productIDs)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to commit transaction: %v", err)
+	}
+
 	return nil
 }
 
 type GetPostListParams struct {
-	User        repository.User
-	UserID      *string
-	SearchQuery *string
-	Cursor      *string
-	CursorID    *string
-	OrderBy     string
-	Ascending   bool
-	IncludeAll  bool
-	PageSize    int
-}
-
-func (s *EndpointService) GetPostList(ctx context.Context, arg GetPostListParams) ([]repository.Post, error) {
+	User           repository.User
+	AcceptLanguage string
+	UserID         *string
+	Tag            *string
+	SearchQuery    *string
+	Cursor         *string
+	CursorID       *string
+	OrderBy        string
+	Ascending      bool
+	IncludeAll     bool
+	PageSize       int
+}
+
+func (s *EndpointService) GetPostList(ctx context.Context, arg GetPostListParams) ([]PostView, error) {
 	// Input validation and adjustments
 	if arg.User.Role == env.UserRole {
 		arg.IncludeAll = false
@@ -81,16 +154,31 @@ func (s *EndpointService) GetPostList(ctx context.Context, arg GetPostListParams
 	// Query execution
 	queries := repository.New(s.db)
 
+	if arg.Tag != nil {
+		tag := normalizeTag(*arg.Tag)
+		arg.Tag = &tag
+	}
+
+	// Regular users may not be able to read the content of paid posts, so it
+	// must not be probed by searching
+	var gatedVisibility *string
+	if arg.User.Role == env.UserRole {
+		visibility := env.PostVisibilityPaid
+		gatedVisibility = &visibility
+	}
+
 	params := repository.GetPostListParams{
-		UserID:      arg.UserID,
-		SearchQuery: arg.SearchQuery,
-		OrderBy:     arg.OrderBy,
-		Ascending:   arg.Ascending,
-		IncludeAll:  arg.IncludeAll,
-		Now:         generator.NowISO8601(),
-		PageSize:    arg.PageSize,
-		Cursor:      arg.Cursor,
-		CursorID:    arg.CursorID,
+		UserID:          arg.UserID,
+		Tag:             arg.Tag,
+		SearchQuery:     arg.SearchQuery,
+		GatedVisibility: gatedVisibility,
+		OrderBy:         arg.OrderBy,
+		Ascending:       arg.Ascending,
+		IncludeAll:      arg.IncludeAll,
+		Now:             generator.NowISO8601(),
+		PageSize:        arg.PageSize,
+		Cursor:          arg.Cursor,
+		CursorID:        arg.CursorID,
 	}
 
 	posts, err := queries.GetPostList(ctx, params)
@@ -98,15 +186,16 @@ func (s *EndpointService) GetPostList(ctx context.Context, arg GetPostListParams
 		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get post list: %v", err)
 	}
 
-	return posts, nil
+	return toPostViews(ctx, queries, posts, &arg.User, preferredLanguageCodes(&arg.User, arg.AcceptLanguage))
 }
 
 type GetPostByIDParams struct {
-	User   repository.User
-	PostID string
+	User           repository.User
+	PostID         string
+	AcceptLanguage string
 }
 
-func (s *EndpointService) GetPostByID(ctx context.Context, arg GetPostByIDParams) (*repository.Post, error) {
+func (s *EndpointService) GetPostByID(ctx context.Context, arg GetPostByIDParams) (*PostView, error) {
 	queries := repository.New(s.db)
 
 	postList, err := queries.GetPostByID(ctx, arg.PostID)
@@ -120,27 +209,45 @@ func (s *EndpointService) GetPostByID(ctx context.Context, arg GetPostByIDParams
 
 	post := postList[0]
 
-	if arg.User.Role == env.UserRole {
-		if post.PublishedAt == nil {
-			return nil, NewServiceError(ErrCodeNotFound, "post not found")
-		}
+	canView, err := canViewPost(ctx, queries, &arg.User, post)
+	if err != nil {
+		return nil, err
+	}
 
-		now := generator.NowISO8601()
-		if *post.PublishedAt > now {
-			return nil, NewServiceError(ErrCodeNotFound, "post not found")
-		}
+	if !canView {
+		return nil, NewServiceError(ErrCodeNotFound, "post not found")
+	}
+
+	languageCodes := preferredLanguageCodes(&arg.User, arg.AcceptLanguage)
+
+	postViews, err := toPostViews(ctx, queries, []repository.Post{post}, &arg.User, languageCodes)
+	if err != nil {
+		return nil, err
 	}
 
-	return &post, nil
+	postView := postViews[0]
+
+	postView.Series, err = getPostSeries(ctx, queries, post, arg.User, languageCodes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &postView, nil
 }
 
 type UpdatePostByIDParams struct {
-	User        repository.User
-	PostID      string
-	Title       string
-	Description string
-	Content     string
-	PublishedAt *string
+	User         repository.User
+	PostID       string
+	Title        string
+	Description  string
+	Content      string
+	PublishedAt  *string
+	Tags         *[]string
+	Visibility   *string
+	ProductIDs   *[]string
+	LanguageCode *string
+	Slug         *string
+	Versions     []string
 }
 
 func (s *EndpointService) UpdatePostByID(ctx context.Context, arg UpdatePostByIDParams) error {
@@ -148,7 +255,23 @@ func (s *EndpointService) UpdatePostByID(ctx context.Context, arg UpdatePostByID
 		return NewServiceError(ErrCodeForbidden, "insufficient permissions to update post")
 	}
 
-	queries := repository.New(s.db)
+	var tags []string
+	if arg.Tags != nil {
+		var err error
+		tags, err = checkTags(*arg.Tags)
+		if err != nil {
+			return err
+		}
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to begin transaction: %v", err)
+	}
+
+	defer tx.Rollback()
+
+	queries := repository.New(tx)
 
 	postList, err := queries.GetPostByID(ctx, arg.PostID)
 	if err != nil {
@@ -161,8 +284,14 @@ func (s *EndpointService) UpdatePostByID(ctx context.Context, arg UpdatePostByID
 
 	post := postList[0]
 
-	if post.UserID == nil || *post.UserID != arg.User.ID {
-		return NewServiceError(ErrCodeForbidden, "insufficient permissions to update post")
+	err = checkPostRole(ctx, queries, arg.User, post.ID, env.PostCollaboratorRoleEditor, "update post")
+	if err != nil {
+		return err
+	}
+
+	err = checkVersion("post", post.UpdatedAt, arg.Versions)
+	if err != nil {
+		return err
 	}
 
 	now := generator.NowISO8601()
@@ -179,20 +308,95 @@ func (s *EndpointService) UpdatePostByID(ctx context.Context, arg UpdatePostByID
 		arg.PublishedAt = &now
 	}
 
+	// Visibility and products are left unchanged if not provided, but the
+	// products are cleared when the post is no longer paid
+	visibility := post.Visibility
+	if arg.Visibility != nil {
+		visibility = *arg.Visibility
+	}
+
+	var productIDs []string
+	if arg.ProductIDs != nil {
+		productIDs = *arg.ProductIDs
+	}
+
+	productIDs, err = checkPostProducts(ctx, queries, visibility, productIDs)
+	if err != nil {
+		return err
+	}
+
+	languageCode := post.LanguageCode
+	if arg.LanguageCode != nil && *arg.LanguageCode != post.LanguageCode {
+		languageCode = *arg.LanguageCode
+
+		if !checkLanguageCode(languageCode) {
+			return NewServiceError(ErrCodeUnprocessable, "invalid language code")
+		}
+
+		translations, err := queries.GetPostTranslationListByPostIDs(ctx, []string{post.ID})
+		if err != nil {
+			return NewServiceErrorf(ErrCodeInternal, "failed to get post translations: %v", err)
+		}
+
+		for _, translation := range translations {
+			if translation.LanguageCode == languageCode {
+				return NewServiceError(ErrCodeUnprocessable, "post already has a translation in the language")
+			}
+		}
+	}
+
+	// The slug is left unchanged if not provided, and removed if empty
+	slug := post.Slug
+	if arg.Slug != nil {
+		slug, err = checkPostSlug(ctx, queries, arg.Slug, post.ID)
+		if err != nil {
+			return err
+		}
+	}
+
 	updateParams := repository.UpdatePostByIDParams{
-		Title:       arg.Title,
-		Description: arg.Description,
-		Content:     arg.Content,
-		PublishedAt: arg.PublishedAt,
-		UpdatedAt:   now,
-		ID:          arg.PostID,
+		Title:        arg.Title,
+		Description:  arg.Description,
+		Content:      arg.Content,
+		PublishedAt:  arg.PublishedAt,
+		Visibility:   visibility,
+		LanguageCode: languageCode,
+		Slug:         slug,
+		UpdatedAt:    now,
+		ID:           arg.PostID,
+		Version:      versionCondition(post.UpdatedAt, arg.Versions),
 	}
 
-	err = queries.UpdatePostByID(ctx, updateParams)
+	rowsAffected, err := queries.UpdatePostByID(ctx, updateParams)
 	if err != nil {
 		return NewServiceErrorf(ErrCodeInternal, "failed to update post by ID: %v", err)
 	}
 
+	err = checkVersionUpdated("post", rowsAffected)
+	if err != nil {
+		return err
+	}
+
+	// Tags are left unchanged if not provided
+	if arg.Tags != nil {
+		err = setPostTags(ctx, queries, arg.PostID, tags)
+		if err != nil {
+			return err
+		}
+	}
+
+	if arg.ProductIDs != nil || visibility != env.PostVisibilityPaid {
+		err = setPostProducts(ctx, queries, arg.PostID, productIDs)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to commit transaction: %v", err)
+	}
+
 	return nil
 }
 
@@ -219,14 +423,305 @@ func (s *EndpointService) DeletePostByID(ctx context.Context, arg DeletePostByID
 
 	post := postList[0]
 
-	if post.UserID == nil || *post.UserID != arg.User.ID {
-		return NewServiceError(ErrCodeForbidden, "insufficient permissions to update post")
+	err = checkPostRole(ctx, queries, arg.User, post.ID, env.PostCollaboratorRoleAuthor, "delete post")
+	if err != nil {
+		return err
 	}
 
-	err = queries.DeletePostByID(ctx, arg.PostID)
+	// The post is moved to the trash, and purged by the cron job after the
+	// retention period
+	now := generator.NowISO8601()
+
+	err = queries.SoftDeletePostByID(ctx, repository.SoftDeletePostByIDParams{
+		DeletedAt: now,
+		UpdatedAt: now,
+		ID:        arg.PostID,
+	})
 	if err != nil {
 		return NewServiceErrorf(ErrCodeInternal, "failed to delete post by ID: %v", err)
 	}
 
 	return nil
 }
+
+type GetTrashedPostListParams struct {
+	User     repository.User
+	Cursor   *string
+	CursorID *string
+	PageSize int
+}
+
+// GetTrashedPostList returns the posts in the trash that the user can restore,
+// most recently deleted first.
+func (s *EndpointService) GetTrashedPostList(ctx context.Context, arg GetTrashedPostListParams) ([]repository.Post, error) {
+	if arg.User.Role == env.UserRole {
+		return nil, NewServiceError(ErrCodeForbidden, "insufficient permissions to get trashed posts")
+	}
+
+	if arg.PageSize <= 0 || arg.PageSize > env.PageSizeMax {
+		arg.PageSize = env.PageSizeDefault
+	}
+
+	queries := repository.New(s.db)
+
+	posts, err := queries.GetTrashedPostList(ctx, repository.GetTrashedPostListParams{
+		UserID:   &arg.User.ID,
+		Role:     env.PostCollaboratorRoleAuthor,
+		PageSize: arg.PageSize,
+		Cursor:   arg.Cursor,
+		CursorID: arg.CursorID,
+	})
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get trashed post list: %v", err)
+	}
+
+	return posts, nil
+}
+
+type RestorePostByIDParams struct {
+	User   repository.User
+	PostID string
+}
+
+func (s *EndpointService) RestorePostByID(ctx context.Context, arg RestorePostByIDParams) error {
+	if arg.User.Role == env.UserRole {
+		return NewServiceError(ErrCodeForbidden, "insufficient permissions to restore post")
+	}
+
+	queries := repository.New(s.db)
+
+	postList, err := queries.GetTrashedPostByID(ctx, arg.PostID)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get trashed post by ID: %v", err)
+	}
+
+	if len(postList) == 0 {
+		return NewServiceError(ErrCodeNotFound, "post not found in trash")
+	}
+
+	post := postList[0]
+
+	err = checkPostRole(ctx, queries, arg.User, post.ID, env.PostCollaboratorRoleAuthor, "restore post")
+	if err != nil {
+		return err
+	}
+
+	err = queries.RestorePostByID(ctx, repository.RestorePostByIDParams{
+		UpdatedAt: generator.NowISO8601(),
+		ID:        post.ID,
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to restore post by ID: %v", err)
+	}
+
+	return nil
+}
+
+// checkPostSlug validates the slug of a post, returning nil if the slug is not
+// given or empty, as the slug of a post is optional.
+func checkPostSlug(ctx context.Context, queries *repository.Queries, slug *string, postID string) (*string, error) {
+	if slug == nil || *slug == "" {
+		return nil, nil
+	}
+
+	isValid, err := checkSlug(*slug)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to check slug: %v", err)
+	}
+
+	if !isValid {
+		return nil, NewServiceError(ErrCodeUnprocessable, "invalid slug")
+	}
+
+	postList, err := queries.GetPostListBySlugs(ctx, []string{*slug})
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get posts by slug: %v", err)
+	}
+
+	if len(postList) > 0 && postList[0].ID != postID {
+		return nil, NewServiceError(ErrCodeSlugTaken, "slug already exists")
+	}
+
+	return slug, nil
+}
+
+// isPostPublished reports whether the post has a publish time that is not in
+// the future.
+func isPostPublished(post repository.Post, now string) bool {
+	return post.PublishedAt != nil && *post.PublishedAt <= now
+}
+
+// PostView is a post together with the data related to it that is returned
+// by the post endpoints.
+type PostView struct {
+	repository.Post
+	Tags       []string `json:"tags"`
+	ProductIDs []string `json:"productIDs"`
+	// IsTeaser reports whether the content is truncated because the viewer
+	// cannot read the full post
+	IsTeaser bool `json:"isTeaser"`
+	// ContentLanguageCode is the language of the returned title, description
+	// and content, which differs from the language of the post if translated
+	ContentLanguageCode      string             `json:"contentLanguageCode"`
+	TranslationLanguageCodes []string           `json:"translationLanguageCodes"`
+	Reactions                []PostReactionView `json:"reactions"`
+	// Series is only set when getting a single post in a series
+	Series *PostSeries `json:"series,omitempty"`
+}
+
+// toPostViews loads the related data of the posts in batch and returns them
+// in the same order. The posts are translated to the first available language
+// code, and the content is truncated to a teaser for posts that the viewer,
+// which is nil for anonymous requests, cannot read in full. Posts are not
+// translated for privileged viewers, as the editor saves the text it loads
+// back to the original post.
+func toPostViews(ctx context.Context, queries *repository.Queries, posts []repository.Post, viewer *repository.User, languageCodes []string) ([]PostView, error) {
+	postIDs := make([]string, len(posts))
+	for i, post := range posts {
+		postIDs[i] = post.ID
+	}
+
+	postTags, err := queries.GetPostTagListByPostIDs(ctx, postIDs)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get post tags: %v", err)
+	}
+
+	mapPostIDToTags := make(map[string][]string)
+	for _, postTag := range postTags {
+		mapPostIDToTags[postTag.PostID] = append(mapPostIDToTags[postTag.PostID], postTag.Tag)
+	}
+
+	postProducts, err := queries.GetPostProductListByPostIDs(ctx, postIDs)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get post products: %v", err)
+	}
+
+	mapPostIDToProductIDs := make(map[string][]string)
+	for _, postProduct := range postProducts {
+		mapPostIDToProductIDs[postProduct.PostID] = append(mapPostIDToProductIDs[postProduct.PostID], postProduct.ProductID)
+	}
+
+	postTranslations, err := queries.GetPostTranslationListByPostIDs(ctx, postIDs)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get post translations: %v", err)
+	}
+
+	mapPostIDToTranslations := make(map[string][]repository.PostTranslation)
+	for _, postTranslation := range postTranslations {
+		mapPostIDToTranslations[postTranslation.PostID] = append(mapPostIDToTranslations[postTranslation.PostID], postTranslation)
+	}
+
+	mapPostIDToReactions, err := getPostReactionViews(ctx, queries, postIDs, viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	access := newPostAccess(viewer)
+
+	if viewer != nil && viewer.Role != env.UserRole {
+		languageCodes = nil
+	}
+
+	postViews := make([]PostView, len(posts))
+	for i, post := range posts {
+		tags := mapPostIDToTags[post.ID]
+		if tags == nil {
+			tags = []string{}
+		}
+
+		productIDs := mapPostIDToProductIDs[post.ID]
+		if productIDs == nil {
+			productIDs = []string{}
+		}
+
+		translations := mapPostIDToTranslations[post.ID]
+
+		translationLanguageCodes := make([]string, len(translations))
+		for j, translation := range translations {
+			translationLanguageCodes[j] = translation.LanguageCode
+		}
+
+		contentLanguageCode := translatePost(&post, translations, languageCodes)
+
+		canRead, err := access.canReadContent(ctx, queries, post, productIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		if !canRead {
+			post.Content = postTeaser(post.Content)
+		}
+
+		postViews[i] = PostView{
+			Post:                     post,
+			Tags:                     tags,
+			ProductIDs:               productIDs,
+			IsTeaser:                 !canRead,
+			ContentLanguageCode:      contentLanguageCode,
+			TranslationLanguageCodes: translationLanguageCodes,
+			Reactions:                mapPostIDToReactions[post.ID],
+		}
+	}
+
+	return postViews, nil
+}
+
+// setPostTags replaces the tags of a post.
+func setPostTags(ctx context.Context, queries *repository.Queries, postID string, tags []string) error {
+	_, err := queries.DeletePostTagByPostID(ctx, postID)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to delete post tags: %v", err)
+	}
+
+	now := generator.NowISO8601()
+
+	for _, tag := range tags {
+		err = queries.CreatePostTag(ctx, repository.PostTag{
+			PostID:    postID,
+			Tag:       tag,
+			CreatedAt: now,
+		})
+		if err != nil {
+			return NewServiceErrorf(ErrCodeInternal, "failed to create post tag: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// checkTags normalizes the tags to trimmed lowercase strings without
+// duplicates and validates them.
+func checkTags(tags []string) ([]string, error) {
+	normalizedTags := []string{}
+	seen := make(map[string]bool)
+
+	for _, tag := range tags {
+		tag = normalizeTag(tag)
+
+		if tag == "" {
+			return nil, NewServiceError(ErrCodeUnprocessable, "tag must not be empty")
+		}
+
+		if utf8.RuneCountInString(tag) > env.PostTagLengthMax {
+			return nil, NewServiceErrorf(ErrCodeUnprocessable, "tag must be at most %d characters", env.PostTagLengthMax)
+		}
+
+		if seen[tag] {
+			continue
+		}
+
+		seen[tag] = true
+		normalizedTags = append(normalizedTags, tag)
+	}
+
+	if len(normalizedTags) > env.PostTagCountMax {
+		return nil, NewServiceErrorf(ErrCodeUnprocessable, "post can have at most %d tags", env.PostTagCountMax)
+	}
+
+	return normalizedTags, nil
+}
+
+// normalizeTag trims and lowercases the tag, the way tags are stored.
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
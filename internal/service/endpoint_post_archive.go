@@ -0,0 +1,666 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/format"
+	"github.com/jljl1337/issho/internal/frontmatter"
+	"github.com/jljl1337/issho/internal/generator"
+	"github.com/jljl1337/issho/internal/repository"
+)
+
+// Jekyll posts are named like 2006-01-02-title.md
+var postFileDatePrefix = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})-`)
+
+// Layouts of the dates in the front matter, from the most to the least
+// specific. Dates without a time zone are in UTC.
+var postFileDateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05 -07:00",
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+type ExportPostsParams struct {
+	User repository.User
+}
+
+// ExportPosts writes a ZIP archive of the posts not in the trash to the
+// writer, with each post in a Markdown file with YAML front matter.
+func (s *EndpointService) ExportPosts(ctx context.Context, arg ExportPostsParams, w io.Writer) error {
+	if arg.User.Role == env.UserRole {
+		return NewServiceError(ErrCodeForbidden, "insufficient permissions to export posts")
+	}
+
+	queries := repository.New(s.db)
+
+	archive := zip.NewWriter(w)
+
+	var cursorID *string
+	for {
+		posts, err := queries.GetAllPostList(ctx, repository.GetAllPostListParams{
+			CursorID: cursorID,
+			PageSize: env.PageSizeMax,
+		})
+		if err != nil {
+			return NewServiceErrorf(ErrCodeInternal, "failed to get posts: %v", err)
+		}
+
+		if len(posts) == 0 {
+			break
+		}
+
+		cursorID = &posts[len(posts)-1].ID
+
+		postIDs := make([]string, len(posts))
+		userIDs := []string{}
+		for i, post := range posts {
+			postIDs[i] = post.ID
+			if post.UserID != nil {
+				userIDs = append(userIDs, *post.UserID)
+			}
+		}
+
+		postTags, err := queries.GetPostTagListByPostIDs(ctx, postIDs)
+		if err != nil {
+			return NewServiceErrorf(ErrCodeInternal, "failed to get post tags: %v", err)
+		}
+
+		mapPostTags := make(map[string][]string)
+		for _, postTag := range postTags {
+			mapPostTags[postTag.PostID] = append(mapPostTags[postTag.PostID], postTag.Tag)
+		}
+
+		users, err := queries.GetUserListByIDs(ctx, userIDs)
+		if err != nil {
+			return NewServiceErrorf(ErrCodeInternal, "failed to get users: %v", err)
+		}
+
+		mapUsername := make(map[string]string)
+		for _, user := range users {
+			mapUsername[user.ID] = user.Username
+		}
+
+		for _, post := range posts {
+			tags := mapPostTags[post.ID]
+			if tags == nil {
+				tags = []string{}
+			}
+
+			fields := []frontmatter.Field{
+				{Key: "id", Value: post.ID},
+				{Key: "title", Value: post.Title},
+				{Key: "description", Value: post.Description},
+			}
+			if post.Slug != nil {
+				fields = append(fields, frontmatter.Field{Key: "slug", Value: *post.Slug})
+			}
+			if post.PublishedAt != nil {
+				fields = append(fields, frontmatter.Field{Key: "published_at", Value: *post.PublishedAt})
+			}
+			fields = append(fields, frontmatter.Field{Key: "tags", Value: tags})
+			if post.UserID != nil && mapUsername[*post.UserID] != "" {
+				fields = append(fields, frontmatter.Field{Key: "author", Value: mapUsername[*post.UserID]})
+			}
+			fields = append(fields,
+				frontmatter.Field{Key: "visibility", Value: post.Visibility},
+				frontmatter.Field{Key: "language_code", Value: post.LanguageCode},
+			)
+
+			name := post.ID
+			if post.Slug != nil {
+				name = *post.Slug
+			}
+
+			header := &zip.FileHeader{
+				Name:   "posts/" + name + ".md",
+				Method: zip.Deflate,
+			}
+			if updatedAt, err := format.ISO8601ToTime(post.UpdatedAt); err == nil {
+				header.Modified = updatedAt
+			}
+
+			file, err := archive.CreateHeader(header)
+			if err != nil {
+				return NewServiceErrorf(ErrCodeInternal, "failed to create archive file: %v", err)
+			}
+
+			_, err = file.Write(frontmatter.Format(fields, post.Content))
+			if err != nil {
+				return NewServiceErrorf(ErrCodeInternal, "failed to write archive file: %v", err)
+			}
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to close archive: %v", err)
+	}
+
+	return nil
+}
+
+type ImportPostsParams struct {
+	User repository.User
+	// Files holds the Markdown files, e.g. an exported archive or the source
+	// directory of a Hugo or Jekyll site
+	Files fs.FS
+	// AuthorMap maps the authors in the front matter to usernames
+	AuthorMap map[string]string
+	DryRun    bool
+}
+
+// ImportPostsResult reports the outcome of each file of an import. In a dry
+// run, the files that would be imported are ready instead of created.
+type ImportPostsResult struct {
+	DryRun  bool              `json:"dryRun"`
+	Created int               `json:"created"`
+	Items   []ImportPostsItem `json:"items"`
+}
+
+type ImportPostsItem struct {
+	Path    string  `json:"path"`
+	Status  string  `json:"status"`
+	Message string  `json:"message,omitempty"`
+	ID      string  `json:"id,omitempty"`
+	Title   string  `json:"title,omitempty"`
+	Slug    *string `json:"slug,omitempty"`
+	Author  string  `json:"author,omitempty"`
+
+	post repository.Post
+	tags []string
+}
+
+// ImportPosts creates a post for each Markdown file with front matter. Files
+// that are invalid, or conflict with the existing posts or with each other by
+// ID or slug, are skipped and reported. Authors are mapped to users by the
+// author map or by username, and otherwise the importing user is the author.
+func (s *EndpointService) ImportPosts(ctx context.Context, arg ImportPostsParams) (*ImportPostsResult, error) {
+	if arg.User.Role == env.UserRole {
+		return nil, NewServiceError(ErrCodeForbidden, "insufficient permissions to import posts")
+	}
+
+	paths, err := findPostFiles(arg.Files)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeUnprocessable, "failed to read files: %v", err)
+	}
+
+	queries := repository.New(s.db)
+
+	// The authors are mapped on purpose, so a wrong mapping fails the import
+	mapAuthor := make(map[string]*repository.User)
+	for name, username := range arg.AuthorMap {
+		author, err := getPostWriter(ctx, queries, username)
+		if err != nil {
+			return nil, err
+		}
+
+		if author == nil {
+			return nil, NewServiceErrorf(ErrCodeUnprocessable, "author %q is mapped to %q, who cannot write posts", name, username)
+		}
+
+		mapAuthor[username] = author
+	}
+
+	now := generator.NowISO8601()
+
+	items := make([]ImportPostsItem, len(paths))
+	for i, filePath := range paths {
+		items[i], err = readPostFile(ctx, queries, arg, filePath, now, mapAuthor)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = checkImportConflicts(ctx, queries, items)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportPostsResult{
+		DryRun: arg.DryRun,
+		Items:  items,
+	}
+
+	if arg.DryRun {
+		return result, nil
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to begin transaction: %v", err)
+	}
+
+	defer tx.Rollback()
+
+	queries = repository.New(tx)
+
+	for i := range items {
+		item := &items[i]
+		if item.Status != env.PostImportStatusReady {
+			continue
+		}
+
+		err = queries.CreatePost(ctx, item.post)
+		if err != nil {
+			return nil, NewServiceErrorf(ErrCodeInternal, "failed to create post: %v", err)
+		}
+
+		err = queries.UpsertPostCollaborator(ctx, repository.PostCollaborator{
+			PostID:    item.post.ID,
+			UserID:    *item.post.UserID,
+			Role:      env.PostCollaboratorRoleAuthor,
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+		if err != nil {
+			return nil, NewServiceErrorf(ErrCodeInternal, "failed to create post collaborator: %v", err)
+		}
+
+		err = setPostTags(ctx, queries, item.post.ID, item.tags)
+		if err != nil {
+			return nil, err
+		}
+
+		item.Status = env.PostImportStatusCreated
+		result.Created++
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to commit transaction: %v", err)
+	}
+
+	return result, nil
+}
+
+// findPostFiles returns the paths of the Markdown files to import. Only the
+// posts of a Jekyll site, in _posts and _drafts, or the content of a Hugo
+// site are imported, leaving out the other pages of the site.
+func findPostFiles(files fs.FS) ([]string, error) {
+	paths := []string{}
+
+	err := fs.WalkDir(files, ".", func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		name := entry.Name()
+		if entry.IsDir() {
+			if filePath != "." && (strings.HasPrefix(name, ".") || name == "node_modules" || name == "_site") {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		ext := strings.ToLower(path.Ext(name))
+		if ext != ".md" && ext != ".markdown" {
+			return nil
+		}
+
+		// Section pages of Hugo list the posts instead
+		if strings.TrimSuffix(name, path.Ext(name)) == "_index" {
+			return nil
+		}
+
+		paths = append(paths, filePath)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dirs := range [][]string{{"_posts", "_drafts"}, {"content"}} {
+		filtered := []string{}
+		for _, filePath := range paths {
+			for dir := range strings.SplitSeq(path.Dir(filePath), "/") {
+				if slices.Contains(dirs, dir) {
+					filtered = append(filtered, filePath)
+					break
+				}
+			}
+		}
+
+		if len(filtered) > 0 {
+			return filtered, nil
+		}
+	}
+
+	return paths, nil
+}
+
+// readPostFile reads the post from the file, returning an item that is ready
+// to import or invalid.
+func readPostFile(ctx context.Context, queries *repository.Queries, arg ImportPostsParams, filePath, now string, mapAuthor map[string]*repository.User) (ImportPostsItem, error) {
+	item := ImportPostsItem{
+		Path:   filePath,
+		Status: env.PostImportStatusInvalid,
+	}
+
+	file, err := arg.Files.Open(filePath)
+	if err != nil {
+		item.Message = fmt.Sprintf("failed to open file: %v", err)
+		return item, nil
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, env.PostImportSizeMaxBytes+1))
+	if err != nil {
+		item.Message = fmt.Sprintf("failed to read file: %v", err)
+		return item, nil
+	}
+
+	if int64(len(data)) > env.PostImportSizeMaxBytes {
+		item.Message = "file is too large"
+		return item, nil
+	}
+
+	document, err := frontmatter.Parse(data)
+	if err != nil {
+		item.Message = err.Error()
+		return item, nil
+	}
+
+	item.Title, _ = document.String("title")
+	if strings.TrimSpace(item.Title) == "" {
+		item.Message = "title is required"
+		return item, nil
+	}
+
+	// Keep the ID of an exported post, so links to the post keep working
+	item.ID = generator.NewULID()
+	id, hasID := document.String("id")
+	if hasID && generator.IsULID(id) {
+		item.ID = strings.ToUpper(id)
+	}
+
+	item.Slug, err = readPostFileSlug(document, filePath, hasID)
+	if err != nil {
+		item.Message = err.Error()
+		return item, nil
+	}
+
+	publishedAt, err := readPostFilePublishedAt(document, filePath)
+	if err != nil {
+		item.Message = err.Error()
+		return item, nil
+	}
+
+	tags, err := checkTags(readPostFileTags(document))
+	if err != nil {
+		item.Message = err.Error()
+		return item, nil
+	}
+
+	author, err := getImportAuthor(ctx, queries, arg, document, mapAuthor)
+	if err != nil {
+		return item, err
+	}
+	item.Author = author.Username
+
+	visibility, ok := document.String("visibility")
+	if !ok {
+		visibility = env.PostVisibilityPublic
+	}
+
+	if !mapPostVisibilityAllowed[visibility] {
+		item.Message = "invalid post visibility"
+		return item, nil
+	}
+
+	languageCode, ok := document.String("language_code")
+	if !ok {
+		languageCode = author.LanguageCode
+	}
+
+	if !checkLanguageCode(languageCode) {
+		item.Message = "invalid language code"
+		return item, nil
+	}
+
+	description, _ := document.String("description")
+	if description == "" {
+		description, _ = document.String("summary")
+	}
+	if description == "" {
+		description, _ = document.String("excerpt")
+	}
+
+	item.post = repository.Post{
+		ID:           item.ID,
+		UserID:       &author.ID,
+		Title:        item.Title,
+		Description:  strings.TrimSpace(description),
+		Content:      document.Content,
+		PublishedAt:  publishedAt,
+		Visibility:   visibility,
+		LanguageCode: languageCode,
+		Slug:         item.Slug,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	item.tags = tags
+	item.Status = env.PostImportStatusReady
+
+	return item, nil
+}
+
+// readPostFileSlug returns the slug of the post. Without a slug in the front
+// matter, the slug of a Hugo or Jekyll post is derived from the file name,
+// but an exported post without a slug is left without one.
+func readPostFileSlug(document *frontmatter.Document, filePath string, hasID bool) (*string, error) {
+	slug, ok := document.String("slug")
+	if !ok {
+		if hasID {
+			return nil, nil
+		}
+
+		// Hugo page bundles are named by their directory
+		name := strings.TrimSuffix(path.Base(filePath), path.Ext(filePath))
+		if name == "index" {
+			name = path.Base(path.Dir(filePath))
+		}
+
+		slug = slugify(postFileDatePrefix.ReplaceAllString(name, ""))
+		if slug == "" {
+			return nil, nil
+		}
+	}
+
+	isValid, err := checkSlug(slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check slug: %w", err)
+	}
+
+	if !isValid {
+		return nil, fmt.Errorf("invalid slug")
+	}
+
+	return &slug, nil
+}
+
+// readPostFilePublishedAt returns the publish date of the post, or nil for a
+// draft. The date is taken from the front matter, or from the name of a
+// Jekyll post.
+func readPostFilePublishedAt(document *frontmatter.Document, filePath string) (*string, error) {
+	draft, _ := document.String("draft")
+	published, _ := document.String("published")
+	if draft == "true" || published == "false" || slices.Contains(strings.Split(filePath, "/"), "_drafts") {
+		return nil, nil
+	}
+
+	var value string
+	for _, key := range []string{"published_at", "publishDate", "date"} {
+		if v, ok := document.String(key); ok && v != "" {
+			value = v
+			break
+		}
+	}
+
+	if value == "" {
+		match := postFileDatePrefix.FindStringSubmatch(path.Base(filePath))
+		if match == nil {
+			return nil, nil
+		}
+		value = match[1]
+	}
+
+	for _, layout := range postFileDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			publishedAt := format.TimeToISO8601(t)
+			return &publishedAt, nil
+		}
+	}
+
+	return nil, fmt.Errorf("invalid date %q", value)
+}
+
+// readPostFileTags returns the tags of the post. Jekyll also accepts tags
+// separated by spaces.
+func readPostFileTags(document *frontmatter.Document) []string {
+	tags, ok := document.Fields["tags"].(string)
+	if !ok {
+		list, _ := document.List("tags")
+		return list
+	}
+
+	if strings.Contains(tags, ",") {
+		return strings.Split(tags, ",")
+	}
+
+	return strings.Fields(tags)
+}
+
+// getImportAuthor returns the user to be the author of the post, by the
+// author map or by username. Authors who are not users that can write posts
+// fall back to the importing user.
+func getImportAuthor(ctx context.Context, queries *repository.Queries, arg ImportPostsParams, document *frontmatter.Document, mapAuthor map[string]*repository.User) (*repository.User, error) {
+	authors, _ := document.List("author")
+	if len(authors) == 0 {
+		authors, _ = document.List("authors")
+	}
+
+	if len(authors) == 0 || strings.TrimSpace(authors[0]) == "" {
+		return &arg.User, nil
+	}
+
+	name := strings.TrimSpace(authors[0])
+	username, ok := arg.AuthorMap[name]
+	if !ok {
+		username = name
+	}
+
+	author, ok := mapAuthor[username]
+	if !ok {
+		var err error
+		author, err = getPostWriter(ctx, queries, username)
+		if err != nil {
+			return nil, err
+		}
+
+		mapAuthor[username] = author
+	}
+
+	if author == nil {
+		return &arg.User, nil
+	}
+
+	return author, nil
+}
+
+// getPostWriter returns the user with the username if the user can write
+// posts, or nil otherwise.
+func getPostWriter(ctx context.Context, queries *repository.Queries, username string) (*repository.User, error) {
+	userList, err := queries.GetUserByUsername(ctx, username)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get user by username: %v", err)
+	}
+
+	if len(userList) == 0 || userList[0].Role == env.UserRole {
+		return nil, nil
+	}
+
+	return &userList[0], nil
+}
+
+// checkImportConflicts marks the ready items as conflicts if their IDs or
+// slugs are taken by existing posts, including the posts in the trash, or by
+// an earlier item.
+func checkImportConflicts(ctx context.Context, queries *repository.Queries, items []ImportPostsItem) error {
+	ids := []string{}
+	slugs := []string{}
+	for _, item := range items {
+		if item.Status != env.PostImportStatusReady {
+			continue
+		}
+
+		ids = append(ids, item.ID)
+		if item.Slug != nil {
+			slugs = append(slugs, *item.Slug)
+		}
+	}
+
+	existingPosts, err := queries.GetPostListByIDsWithTrashed(ctx, ids)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get posts by IDs: %v", err)
+	}
+
+	postsBySlug, err := queries.GetPostListBySlugs(ctx, slugs)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get posts by slugs: %v", err)
+	}
+
+	mapIDTaken := make(map[string]bool)
+	for _, post := range existingPosts {
+		mapIDTaken[post.ID] = true
+	}
+
+	mapSlugTaken := make(map[string]bool)
+	for _, post := range postsBySlug {
+		mapSlugTaken[*post.Slug] = true
+	}
+
+	mapIDImported := make(map[string]bool)
+	mapSlugImported := make(map[string]bool)
+	for i := range items {
+		item := &items[i]
+		if item.Status != env.PostImportStatusReady {
+			continue
+		}
+
+		switch {
+		case mapIDTaken[item.ID]:
+			item.Status = env.PostImportStatusConflict
+			item.Message = "post already exists"
+		case mapIDImported[item.ID]:
+			item.Status = env.PostImportStatusConflict
+			item.Message = "post is imported by another file"
+		case item.Slug != nil && mapSlugTaken[*item.Slug]:
+			item.Status = env.PostImportStatusConflict
+			item.Message = "slug already exists"
+		case item.Slug != nil && mapSlugImported[*item.Slug]:
+			item.Status = env.PostImportStatusConflict
+			item.Message = "slug is used by another file"
+		default:
+			mapIDImported[item.ID] = true
+			if item.Slug != nil {
+				mapSlugImported[*item.Slug] = true
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/generator"
+	"github.com/jljl1337/issho/internal/repository"
+)
+
+type PostCollaboratorView struct {
+	repository.PostCollaborator
+	Username string `json:"username"`
+}
+
+type GetPostCollaboratorListParams struct {
+	User   repository.User
+	PostID string
+}
+
+func (s *EndpointService) GetPostCollaboratorList(ctx context.Context, arg GetPostCollaboratorListParams) ([]PostCollaboratorView, error) {
+	queries := repository.New(s.db)
+
+	post, err := getPostByID(ctx, queries, arg.PostID)
+	if err != nil {
+		return nil, err
+	}
+
+	if arg.User.Role == env.UserRole {
+		err = checkPostRole(ctx, queries, arg.User, post.ID, env.PostCollaboratorRoleViewer, "get post collaborators")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	collaborators, err := queries.GetPostCollaboratorListByPostID(ctx, post.ID)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get post collaborators: %v", err)
+	}
+
+	userIDs := make([]string, len(collaborators))
+	for i, collaborator := range collaborators {
+		userIDs[i] = collaborator.UserID
+	}
+
+	users, err := queries.GetUserListByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get users: %v", err)
+	}
+
+	mapUserIDToUsername := make(map[string]string)
+	for _, user := range users {
+		mapUserIDToUsername[user.ID] = user.Username
+	}
+
+	result := make([]PostCollaboratorView, len(collaborators))
+	for i, collaborator := range collaborators {
+		result[i] = PostCollaboratorView{
+			PostCollaborator: collaborator,
+			Username:         mapUserIDToUsername[collaborator.UserID],
+		}
+	}
+
+	return result, nil
+}
+
+type UpsertPostCollaboratorParams struct {
+	User   repository.User
+	PostID string
+	UserID string
+	Role   string
+}
+
+// UpsertPostCollaborator adds a collaborator to the post, or changes the role
+// of an existing collaborator. The main author of the post always stays an
+// author, so the ownership has to be transferred first.
+func (s *EndpointService) UpsertPostCollaborator(ctx context.Context, arg UpsertPostCollaboratorParams) error {
+	if _, ok := mapPostCollaboratorRoleRank[arg.Role]; !ok {
+		return NewServiceError(ErrCodeUnprocessable, "invalid collaborator role")
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to begin transaction: %v", err)
+	}
+
+	defer tx.Rollback()
+
+	queries := repository.New(tx)
+
+	post, err := getPostByID(ctx, queries, arg.PostID)
+	if err != nil {
+		return err
+	}
+
+	err = checkPostRole(ctx, queries, arg.User, post.ID, env.PostCollaboratorRoleAuthor, "manage post collaborators")
+	if err != nil {
+		return err
+	}
+
+	if post.UserID != nil && *post.UserID == arg.UserID && arg.Role != env.PostCollaboratorRoleAuthor {
+		return NewServiceError(ErrCodeUnprocessable, "cannot change the role of the main author")
+	}
+
+	err = checkCollaboratorUser(ctx, queries, arg.UserID, arg.Role)
+	if err != nil {
+		return err
+	}
+
+	now := generator.NowISO8601()
+
+	err = queries.UpsertPostCollaborator(ctx, repository.PostCollaborator{
+		PostID:    post.ID,
+		UserID:    arg.UserID,
+		Role:      arg.Role,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to upsert post collaborator: %v", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+type DeletePostCollaboratorParams struct {
+	User   repository.User
+	PostID string
+	UserID string
+}
+
+// DeletePostCollaborator removes a collaborator from the post. Authors can
+// remove anyone but the main author, and collaborators can remove themselves.
+func (s *EndpointService) DeletePostCollaborator(ctx context.Context, arg DeletePostCollaboratorParams) error {
+	queries := repository.New(s.db)
+
+	post, err := getPostByID(ctx, queries, arg.PostID)
+	if err != nil {
+		return err
+	}
+
+	if arg.UserID != arg.User.ID {
+		err = checkPostRole(ctx, queries, arg.User, post.ID, env.PostCollaboratorRoleAuthor, "manage post collaborators")
+		if err != nil {
+			return err
+		}
+	}
+
+	if post.UserID != nil && *post.UserID == arg.UserID {
+		return NewServiceError(ErrCodeUnprocessable, "cannot remove the main author")
+	}
+
+	rows, err := queries.DeletePostCollaborator(ctx, repository.DeletePostCollaboratorParams{
+		PostID: post.ID,
+		UserID: arg.UserID,
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to delete post collaborator: %v", err)
+	}
+
+	if rows == 0 {
+		return NewServiceError(ErrCodeNotFound, "post collaborator not found")
+	}
+
+	return nil
+}
+
+type TransferPostByIDParams struct {
+	User   repository.User
+	PostID string
+	UserID string
+}
+
+// TransferPostByID makes the user the main author of the post. The previous
+// main author stays an author until removed. If the post has no authors left,
+// e.g. after the account of the main author is deleted, any owner can take
+// the post over.
+func (s *EndpointService) TransferPostByID(ctx context.Context, arg TransferPostByIDParams) error {
+	if arg.User.Role == env.UserRole {
+		return NewServiceError(ErrCodeForbidden, "insufficient permissions to transfer post")
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to begin transaction: %v", err)
+	}
+
+	defer tx.Rollback()
+
+	queries := repository.New(tx)
+
+	post, err := getPostByID(ctx, queries, arg.PostID)
+	if err != nil {
+		return err
+	}
+
+	collaborators, err := queries.GetPostCollaboratorListByPostID(ctx, post.ID)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get post collaborators: %v", err)
+	}
+
+	hasAuthor := false
+	for _, collaborator := range collaborators {
+		if collaborator.Role == env.PostCollaboratorRoleAuthor {
+			hasAuthor = true
+			break
+		}
+	}
+
+	if hasAuthor {
+		err = checkPostRole(ctx, queries, arg.User, post.ID, env.PostCollaboratorRoleAuthor, "transfer post")
+		if err != nil {
+			return err
+		}
+	}
+
+	err = checkCollaboratorUser(ctx, queries, arg.UserID, env.PostCollaboratorRoleAuthor)
+	if err != nil {
+		return err
+	}
+
+	now := generator.NowISO8601()
+
+	err = queries.UpsertPostCollaborator(ctx, repository.PostCollaborator{
+		PostID:    post.ID,
+		UserID:    arg.UserID,
+		Role:      env.PostCollaboratorRoleAuthor,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to upsert post collaborator: %v", err)
+	}
+
+	err = queries.UpdatePostUserIDByID(ctx, repository.UpdatePostUserIDByIDParams{
+		UserID:    arg.UserID,
+		UpdatedAt: now,
+		ID:        post.ID,
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to update post by ID: %v", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+func getPostByID(ctx context.Context, queries *repository.Queries, postID string) (*repository.Post, error) {
+	postList, err := queries.GetPostByID(ctx, postID)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get post by ID: %v", err)
+	}
+
+	if len(postList) == 0 {
+		return nil, NewServiceError(ErrCodeNotFound, "post not found")
+	}
+
+	return &postList[0], nil
+}
+
+// checkCollaboratorUser checks that the user exists and can have the role.
+// Only owners can update posts, so regular users can only be viewers.
+func checkCollaboratorUser(ctx context.Context, queries *repository.Queries, userID, role string) error {
+	userList, err := queries.GetUserListByIDs(ctx, []string{userID})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get user by ID: %v", err)
+	}
+
+	if len(userList) == 0 {
+		return NewServiceError(ErrCodeNotFound, "user not found")
+	}
+
+	if userList[0].Role == env.UserRole && role != env.PostCollaboratorRoleViewer {
+		return NewServiceError(ErrCodeUnprocessable, "regular users can only be viewers")
+	}
+
+	return nil
+}
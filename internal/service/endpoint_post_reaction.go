@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"slices"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/generator"
+	"github.com/jljl1337/issho/internal/repository"
+)
+
+type CreatePostReactionParams struct {
+	User   repository.User
+	PostID string
+	Type   string
+}
+
+func (s *EndpointService) CreatePostReaction(ctx context.Context, arg CreatePostReactionParams) error {
+	if !slices.Contains(env.PostReactionTypes, arg.Type) {
+		return NewServiceError(ErrCodeUnprocessable, "invalid reaction type")
+	}
+
+	queries := repository.New(s.db)
+
+	postList, err := queries.GetPostByID(ctx, arg.PostID)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get post by ID: %v", err)
+	}
+
+	if len(postList) == 0 {
+		return NewServiceError(ErrCodeNotFound, "post not found")
+	}
+
+	post := postList[0]
+
+	canView, err := canViewPost(ctx, queries, &arg.User, post)
+	if err != nil {
+		return err
+	}
+
+	if !canView {
+		return NewServiceError(ErrCodeNotFound, "post not found")
+	}
+
+	now := generator.NowISO8601()
+
+	if !isPostPublished(post, now) {
+		return NewServiceError(ErrCodeUnprocessable, "cannot react to unpublished post")
+	}
+
+	err = queries.CreatePostReaction(ctx, repository.PostReaction{
+		PostID:    arg.PostID,
+		UserID:    arg.User.ID,
+		Type:      arg.Type,
+		CreatedAt: now,
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to create post reaction: %v", err)
+	}
+
+	return nil
+}
+
+type DeletePostReactionParams struct {
+	User   repository.User
+	PostID string
+	Type   string
+}
+
+func (s *EndpointService) DeletePostReaction(ctx context.Context, arg DeletePostReactionParams) error {
+	queries := repository.New(s.db)
+
+	rows, err := queries.DeletePostReaction(ctx, repository.DeletePostReactionParams{
+		PostID: arg.PostID,
+		UserID: arg.User.ID,
+		Type:   arg.Type,
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to delete post reaction: %v", err)
+	}
+
+	if rows == 0 {
+		return NewServiceError(ErrCodeNotFound, "reaction not found")
+	}
+
+	return nil
+}
+
+// PostReactionView is the number of reactions of a type to a post, and
+// whether the viewer is one of them.
+type PostReactionView struct {
+	Type        string `json:"type"`
+	Count       int64  `json:"count"`
+	ReactedByMe bool   `json:"reactedByMe"`
+}
+
+// getPostReactionViews loads the reactions of the posts in batch, in the
+// order of the configured reaction types. Types without reactions, or no
+// longer configured, are left out. The viewer is nil for anonymous requests.
+func getPostReactionViews(ctx context.Context, queries *repository.Queries, postIDs []string, viewer *repository.User) (map[string][]PostReactionView, error) {
+	reactionCounts, err := queries.GetPostReactionCountListByPostIDs(ctx, postIDs)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get post reaction counts: %v", err)
+	}
+
+	mapReactedByMe := make(map[repository.PostReactionCount]bool)
+	if viewer != nil {
+		reactions, err := queries.GetPostReactionListByPostIDsAndUserID(ctx, repository.GetPostReactionListByPostIDsAndUserIDParams{
+			PostIDs: postIDs,
+			UserID:  viewer.ID,
+		})
+		if err != nil {
+			return nil, NewServiceErrorf(ErrCodeInternal, "failed to get post reactions: %v", err)
+		}
+
+		for _, reaction := range reactions {
+			mapReactedByMe[repository.PostReactionCount{PostID: reaction.PostID, Type: reaction.Type}] = true
+		}
+	}
+
+	mapCount := make(map[repository.PostReactionCount]int64)
+	for _, reactionCount := range reactionCounts {
+		mapCount[repository.PostReactionCount{PostID: reactionCount.PostID, Type: reactionCount.Type}] = reactionCount.Count
+	}
+
+	mapPostIDToReactions := make(map[string][]PostReactionView)
+	for _, postID := range postIDs {
+		reactionViews := []PostReactionView{}
+		for _, reactionType := range env.PostReactionTypes {
+			key := repository.PostReactionCount{PostID: postID, Type: reactionType}
+			if mapCount[key] == 0 {
+				continue
+			}
+
+			reactionViews = append(reactionViews, PostReactionView{
+				Type:        reactionType,
+				Count:       mapCount[key],
+				ReactedByMe: mapReactedByMe[key],
+			})
+		}
+
+		mapPostIDToReactions[postID] = reactionViews
+	}
+
+	return mapPostIDToReactions, nil
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+
+	"github.com/jljl1337/issho/internal/crypto"
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/generator"
+	"github.com/jljl1337/issho/internal/repository"
+)
+
+type RecordPostViewParams struct {
+	User         repository.User
+	PostID       string
+	SessionToken string
+}
+
+// RecordPostView counts a view of the post by a regular user, at most once per
+// session per day. Views of posts that are not published yet are not counted.
+//
+// No IP address or user ID is stored, only a hash of the session token and the
+// date, which changes every day. The views are deleted once they are rolled up
+// into the daily counts.
+func (s *EndpointService) RecordPostView(ctx context.Context, arg RecordPostViewParams) error {
+	if arg.User.Role != env.UserRole || arg.SessionToken == "" {
+		return nil
+	}
+
+	queries := repository.New(s.db)
+
+	postList, err := queries.GetPostByID(ctx, arg.PostID)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get post by ID: %v", err)
+	}
+
+	now := generator.NowISO8601()
+
+	if len(postList) == 0 || !isPostPublished(postList[0], now) {
+		return nil
+	}
+
+	viewDate := generator.DaysFromTodayDate(0)
+
+	_, err = queries.CreatePostView(ctx, repository.PostView{
+		PostID:      arg.PostID,
+		ViewDate:    viewDate,
+		VisitorHash: crypto.HashSHA256(viewDate + ":" + arg.SessionToken),
+		CreatedAt:   now,
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to create post view: %v", err)
+	}
+
+	return nil
+}
+
+type GetPostStatsParams struct {
+	User   repository.User
+	PostID string
+	Days   int
+}
+
+// PostStats is the number of views of a post in each of the last days,
+// including today.
+type PostStats struct {
+	PostID    string                     `json:"postID"`
+	From      string                     `json:"from"`
+	To        string                     `json:"to"`
+	ViewCount int64                      `json:"viewCount"`
+	Days      []repository.PostViewCount `json:"days"`
+}
+
+func (s *EndpointService) GetPostStats(ctx context.Context, arg GetPostStatsParams) (*PostStats, error) {
+	if arg.User.Role == env.UserRole {
+		return nil, NewServiceError(ErrCodeForbidden, "insufficient permissions to get post stats")
+	}
+
+	days := clampPostStatsDays(arg.Days)
+
+	queries := repository.New(s.db)
+
+	postList, err := queries.GetPostByID(ctx, arg.PostID)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get post by ID: %v", err)
+	}
+
+	if len(postList) == 0 {
+		return nil, NewServiceError(ErrCodeNotFound, "post not found")
+	}
+
+	from := generator.DaysFromTodayDate(1 - days)
+
+	viewCounts, err := queries.GetPostViewCountListByPostID(ctx, repository.GetPostViewCountListByPostIDParams{
+		PostID:       arg.PostID,
+		ViewDateFrom: from,
+	})
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get post view counts: %v", err)
+	}
+
+	mapViewCount := make(map[string]int64)
+	for _, viewCount := range viewCounts {
+		mapViewCount[viewCount.ViewDate] = viewCount.ViewCount
+	}
+
+	// Fill in the days without views
+	stats := &PostStats{
+		PostID: arg.PostID,
+		From:   from,
+		To:     generator.DaysFromTodayDate(0),
+		Days:   make([]repository.PostViewCount, days),
+	}
+	for i := range days {
+		viewDate := generator.DaysFromTodayDate(i + 1 - days)
+		stats.Days[i] = repository.PostViewCount{
+			ViewDate:  viewDate,
+			ViewCount: mapViewCount[viewDate],
+		}
+		stats.ViewCount += mapViewCount[viewDate]
+	}
+
+	return stats, nil
+}
+
+type GetTopPostListParams struct {
+	User     repository.User
+	Days     int
+	PageSize int
+}
+
+// TopPost is a post with the number of its views in the last days.
+type TopPost struct {
+	PostID    string `json:"postID"`
+	Title     string `json:"title"`
+	ViewCount int64  `json:"viewCount"`
+}
+
+func (s *EndpointService) GetTopPostList(ctx context.Context, arg GetTopPostListParams) ([]TopPost, error) {
+	if arg.User.Role == env.UserRole {
+		return nil, NewServiceError(ErrCodeForbidden, "insufficient permissions to get top posts")
+	}
+
+	if arg.PageSize <= 0 || arg.PageSize > env.PageSizeMax {
+		arg.PageSize = env.PageSizeDefault
+	}
+
+	days := clampPostStatsDays(arg.Days)
+
+	queries := repository.New(s.db)
+
+	viewTotals, err := queries.GetTopPostViewTotalList(ctx, repository.GetTopPostViewTotalListParams{
+		ViewDateFrom: generator.DaysFromTodayDate(1 - days),
+		PageSize:     arg.PageSize,
+	})
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get top post view totals: %v", err)
+	}
+
+	postIDs := make([]string, len(viewTotals))
+	for i, viewTotal := range viewTotals {
+		postIDs[i] = viewTotal.PostID
+	}
+
+	posts, err := queries.GetPostListByIDs(ctx, postIDs)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get posts: %v", err)
+	}
+
+	mapPostTitle := make(map[string]string)
+	for _, post := range posts {
+		mapPostTitle[post.ID] = post.Title
+	}
+
+	topPosts := make([]TopPost, len(viewTotals))
+	for i, viewTotal := range viewTotals {
+		topPosts[i] = TopPost{
+			PostID:    viewTotal.PostID,
+			Title:     mapPostTitle[viewTotal.PostID],
+			ViewCount: viewTotal.ViewCount,
+		}
+	}
+
+	return topPosts, nil
+}
+
+// clampPostStatsDays returns the number of days to get the stats for, using
+// the default if the days are not given or out of range.
+func clampPostStatsDays(days int) int {
+	if days <= 0 || days > env.PostStatsDaysMax {
+		return env.PostStatsDaysDefault
+	}
+
+	return days
+}
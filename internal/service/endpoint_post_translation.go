@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/generator"
+	"github.com/jljl1337/issho/internal/repository"
+)
+
+type UpsertPostTranslationParams struct {
+	User         repository.User
+	PostID       string
+	LanguageCode string
+	Title        string
+	Description  string
+	Content      string
+}
+
+func (s *EndpointService) UpsertPostTranslation(ctx context.Context, arg UpsertPostTranslationParams) error {
+	if arg.User.Role == env.UserRole {
+		return NewServiceError(ErrCodeForbidden, "insufficient permissions to update post")
+	}
+
+	if !checkLanguageCode(arg.LanguageCode) {
+		return NewServiceError(ErrCodeUnprocessable, "invalid language code")
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to begin transaction: %v", err)
+	}
+
+	defer tx.Rollback()
+
+	queries := repository.New(tx)
+
+	post, err := getEditablePostByID(ctx, queries, arg.User, arg.PostID)
+	if err != nil {
+		return err
+	}
+
+	if post.LanguageCode == arg.LanguageCode {
+		return NewServiceError(ErrCodeUnprocessable, "post is already written in the language")
+	}
+
+	now := generator.NowISO8601()
+
+	err = queries.UpsertPostTranslation(ctx, repository.PostTranslation{
+		PostID:       post.ID,
+		LanguageCode: arg.LanguageCode,
+		Title:        arg.Title,
+		Description:  arg.Description,
+		Content:      arg.Content,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to upsert post translation: %v", err)
+	}
+
+	// A translation is part of the post, so the post counts as updated
+	err = queries.UpdatePostUpdatedAtByID(ctx, repository.UpdatePostUpdatedAtByIDParams{
+		UpdatedAt: now,
+		ID:        post.ID,
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to update post by ID: %v", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+type GetPostTranslationListParams struct {
+	User   repository.User
+	PostID string
+}
+
+func (s *EndpointService) GetPostTranslationList(ctx context.Context, arg GetPostTranslationListParams) ([]repository.PostTranslation, error) {
+	if arg.User.Role == env.UserRole {
+		return nil, NewServiceError(ErrCodeForbidden, "insufficient permissions to get post translations")
+	}
+
+	queries := repository.New(s.db)
+
+	postList, err := queries.GetPostByID(ctx, arg.PostID)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get post by ID: %v", err)
+	}
+
+	if len(postList) == 0 {
+		return nil, NewServiceError(ErrCodeNotFound, "post not found")
+	}
+
+	translations, err := queries.GetPostTranslationListByPostIDs(ctx, []string{arg.PostID})
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get post translations: %v", err)
+	}
+
+	return translations, nil
+}
+
+type DeletePostTranslationParams struct {
+	User         repository.User
+	PostID       string
+	LanguageCode string
+}
+
+func (s *EndpointService) DeletePostTranslation(ctx context.Context, arg DeletePostTranslationParams) error {
+	if arg.User.Role == env.UserRole {
+		return NewServiceError(ErrCodeForbidden, "insufficient permissions to update post")
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to begin transaction: %v", err)
+	}
+
+	defer tx.Rollback()
+
+	queries := repository.New(tx)
+
+	post, err := getEditablePostByID(ctx, queries, arg.User, arg.PostID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := queries.DeletePostTranslation(ctx, repository.DeletePostTranslationParams{
+		PostID:       post.ID,
+		LanguageCode: arg.LanguageCode,
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to delete post translation: %v", err)
+	}
+
+	if rows == 0 {
+		return NewServiceError(ErrCodeNotFound, "post translation not found")
+	}
+
+	err = queries.UpdatePostUpdatedAtByID(ctx, repository.UpdatePostUpdatedAtByIDParams{
+		UpdatedAt: generator.NowISO8601(),
+		ID:        post.ID,
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to update post by ID: %v", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+type GetPostListMissingTranslationParams struct {
+	User         repository.User
+	LanguageCode *string
+	Cursor       *string
+	CursorID     *string
+	PageSize     int
+}
+
+type PostMissingTranslation struct {
+	repository.Post
+	MissingLanguageCodes []string `json:"missingLanguageCodes"`
+}
+
+// GetPostListMissingTranslation returns the posts the user can update that are
+// not available in the language, or in any of the supported languages if not
+// given, most recently updated first.
+func (s *EndpointService) GetPostListMissingTranslation(ctx context.Context, arg GetPostListMissingTranslationParams) ([]PostMissingTranslation, error) {
+	if arg.User.Role == env.UserRole {
+		return nil, NewServiceError(ErrCodeForbidden, "insufficient permissions to get post translations")
+	}
+
+	languageCodes := supportedLanguageCodes()
+	if arg.LanguageCode != nil {
+		if !checkLanguageCode(*arg.LanguageCode) {
+			return nil, NewServiceError(ErrCodeUnprocessable, "invalid language code")
+		}
+		languageCodes = []string{*arg.LanguageCode}
+	}
+
+	if arg.PageSize <= 0 || arg.PageSize > env.PageSizeMax {
+		arg.PageSize = env.PageSizeDefault
+	}
+
+	queries := repository.New(s.db)
+
+	posts, err := queries.GetPostListMissingTranslation(ctx, repository.GetPostListMissingTranslationParams{
+		UserID:            &arg.User.ID,
+		Roles:             []string{env.PostCollaboratorRoleAuthor, env.PostCollaboratorRoleEditor},
+		LanguageCodes:     languageCodes,
+		LanguageCodeCount: len(languageCodes),
+		Ascending:         false,
+		PageSize:          arg.PageSize,
+		Cursor:            arg.Cursor,
+		CursorID:          arg.CursorID,
+	})
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get post list: %v", err)
+	}
+
+	postIDs := make([]string, len(posts))
+	for i, post := range posts {
+		postIDs[i] = post.ID
+	}
+
+	translations, err := queries.GetPostTranslationListByPostIDs(ctx, postIDs)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get post translations: %v", err)
+	}
+
+	mapPostIDToLanguageCodes := make(map[string]map[string]bool)
+	for _, post := range posts {
+		mapPostIDToLanguageCodes[post.ID] = map[string]bool{post.LanguageCode: true}
+	}
+	for _, translation := range translations {
+		mapPostIDToLanguageCodes[translation.PostID][translation.LanguageCode] = true
+	}
+
+	result := make([]PostMissingTranslation, len(posts))
+	for i, post := range posts {
+		missingLanguageCodes := []string{}
+		for _, languageCode := range languageCodes {
+			if !mapPostIDToLanguageCodes[post.ID][languageCode] {
+				missingLanguageCodes = append(missingLanguageCodes, languageCode)
+			}
+		}
+
+		result[i] = PostMissingTranslation{
+			Post:                 post,
+			MissingLanguageCodes: missingLanguageCodes,
+		}
+	}
+
+	return result, nil
+}
+
+// getEditablePostByID returns the post if the user can update it.
+func getEditablePostByID(ctx context.Context, queries *repository.Queries, user repository.User, postID string) (*repository.Post, error) {
+	postList, err := queries.GetPostByID(ctx, postID)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get post by ID: %v", err)
+	}
+
+	if len(postList) == 0 {
+		return nil, NewServiceError(ErrCodeNotFound, "post not found")
+	}
+
+	post := postList[0]
+
+	err = checkPostRole(ctx, queries, user, post.ID, env.PostCollaboratorRoleEditor, "update post")
+	if err != nil {
+		return nil, err
+	}
+
+	return &post, nil
+}
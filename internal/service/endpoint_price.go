@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"log/slog"
 
 	"github.com/jljl1337/issho/internal/env"
 	"github.com/jljl1337/issho/internal/generator"
@@ -21,6 +22,10 @@ type CreatePriceParams struct {
 	RecurringIntervalCount *int
 }
 
+// CreatePrice creates the price at the payment provider, which gives the price
+// its external ID, before storing it. A price created at the provider but
+// failing to be stored is only repaired by the payment reconciliation, which
+// creates it from the provider.
 func (s *EndpointService) CreatePrice(ctx context.Context, arg CreatePriceParams) error {
 	if arg.User.Role == env.UserRole {
 		return NewServiceError(ErrCodeForbidden, "insufficient permissions to create price")
@@ -53,8 +58,13 @@ func (s *EndpointService) CreatePrice(ctx context.Context, arg CreatePriceParams
 
 	product := productList[0]
 
+	productExternalID, err := s.linkProductWithPaymentProvider(ctx, queries, product)
+	if err != nil {
+		return err
+	}
+
 	price, err := s.paymentProvider.CreatePrice(ctx, payment.CreatePriceParams{
-		ProductExternalID:      product.ExternalID,
+		ProductExternalID:      &productExternalID,
 		Name:                   arg.Name,
 		Description:            arg.Description,
 		PriceAmount:            arg.PriceAmount,
@@ -64,7 +74,7 @@ func (s *EndpointService) CreatePrice(ctx context.Context, arg CreatePriceParams
 		RecurringIntervalCount: arg.RecurringIntervalCount,
 	})
 	if err != nil {
-		return NewServiceErrorf(ErrCodeInternal, "failed to create price in payment provider: %v", err)
+		return newPaymentProviderError(err, "failed to create price in payment provider")
 	}
 
 	now := generator.NowISO8601()
@@ -143,6 +153,7 @@ type UpdatePriceByIDParams struct {
 	RecurringInterval      *string
 	RecurringIntervalCount *int
 	IsActive               bool
+	Versions               []string
 }
 
 func (s *EndpointService) UpdatePriceByID(ctx context.Context, arg UpdatePriceByIDParams) error {
@@ -177,6 +188,11 @@ func (s *EndpointService) UpdatePriceByID(ctx context.Context, arg UpdatePriceBy
 
 	price := priceList[0]
 
+	err = checkVersion("price", price.UpdatedAt, arg.Versions)
+	if err != nil {
+		return err
+	}
+
 	// Only modify active status if reactivating a price
 	if !price.IsActive && arg.IsActive {
 		arg.Name = price.Name
@@ -188,8 +204,27 @@ func (s *EndpointService) UpdatePriceByID(ctx context.Context, arg UpdatePriceBy
 		arg.RecurringIntervalCount = price.RecurringIntervalCount
 	}
 
-	newPrice, err := s.paymentProvider.UpdatePrice(ctx, payment.UpdatePriceParams{
-		ExternalID:             price.ExternalID,
+	// Reject the changes the payment provider cannot make before the price is
+	// updated, as the update is only synced to the provider afterwards
+	isAmountChanged := arg.PriceAmount != price.PriceAmount ||
+		arg.PriceCurrency != price.PriceCurrency ||
+		arg.IsRecurring != price.IsRecurring ||
+		(arg.IsRecurring && (*arg.RecurringInterval != *price.RecurringInterval ||
+			*arg.RecurringIntervalCount != *price.RecurringIntervalCount))
+	if isAmountChanged && !s.paymentProvider.CanUpdatePriceAmount() {
+		return NewServiceError(ErrCodeUnprocessable, "price amount, currency and recurrence cannot be changed with the payment provider")
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to begin transaction: %v", err)
+	}
+
+	defer tx.Rollback()
+
+	queries = repository.New(tx)
+
+	params := repository.UpdatePriceParams{
 		Name:                   arg.Name,
 		Description:            arg.Description,
 		PriceAmount:            arg.PriceAmount,
@@ -198,29 +233,33 @@ func (s *EndpointService) UpdatePriceByID(ctx context.Context, arg UpdatePriceBy
 		RecurringInterval:      arg.RecurringInterval,
 		RecurringIntervalCount: arg.RecurringIntervalCount,
 		IsActive:               arg.IsActive,
-	})
+		UpdatedAt:              generator.NowISO8601(),
+		ID:                     arg.PriceID,
+		Version:                versionCondition(price.UpdatedAt, arg.Versions),
+	}
+
+	rowsAffected, err := queries.UpdatePrice(ctx, params)
 	if err != nil {
-		return NewServiceErrorf(ErrCodeInternal, "failed to update price in payment provider: %v", err)
+		return NewServiceErrorf(ErrCodeInternal, "failed to update price: %v", err)
 	}
 
-	now := generator.NowISO8601()
+	err = checkVersionUpdated("price", rowsAffected)
+	if err != nil {
+		return err
+	}
 
-	params := repository.UpdatePriceParams{
-		Name:                   newPrice.Name,
-		Description:            newPrice.Description,
-		PriceAmount:            newPrice.PriceAmount,
-		PriceCurrency:          newPrice.PriceCurrency,
-		IsRecurring:            newPrice.IsRecurring,
-		RecurringInterval:      newPrice.RecurringInterval,
-		RecurringIntervalCount: newPrice.RecurringIntervalCount,
-		IsActive:               newPrice.IsActive,
-		UpdatedAt:              now,
-		ID:                     arg.PriceID,
+	// Update price in payment provider
+	err = enqueuePaymentOutbox(ctx, queries, enqueuePaymentOutboxParams{
+		Type:    env.PaymentOutboxTypeUpdatePrice,
+		PriceID: &arg.PriceID,
+	})
+	if err != nil {
+		return err
 	}
 
-	err = queries.UpdatePrice(ctx, params)
+	err = tx.Commit()
 	if err != nil {
-		return NewServiceErrorf(ErrCodeInternal, "failed to update price: %v", err)
+		return NewServiceErrorf(ErrCodeInternal, "failed to commit transaction: %v", err)
 	}
 
 	return nil
@@ -271,3 +310,112 @@ func checkRecurringParams(isRecurring bool, interval *string, intervalCount *int
 	}
 	return nil
 }
+
+type CreateCheckoutParams struct {
+	User         repository.User
+	PriceID      string
+	DiscountCode *string
+}
+
+// Checkout is a checkout session hosted by the payment provider.
+type Checkout struct {
+	URL string `json:"url"`
+}
+
+// CreateCheckout creates a checkout session for the user to buy the price,
+// with the discount of the code if given. The user must have verified their
+// email, which creates their customer in the payment provider. A redemption
+// of the discount is reserved for the checkout, which is counted as redeemed
+// once the purchase is paid, and released if the checkout is canceled or
+// expires.
+func (s *EndpointService) CreateCheckout(ctx context.Context, arg CreateCheckoutParams) (*Checkout, error) {
+	if arg.User.Role != env.UserRole {
+		return nil, NewServiceError(ErrCodeForbidden, "only regular users can check out")
+	}
+
+	if !arg.User.IsVerified {
+		return nil, NewServiceError(ErrCodeUnprocessable, "email must be verified to check out")
+	}
+
+	if arg.User.ExternalID == nil {
+		return nil, NewServiceError(ErrCodeConflict, "customer is still being created with the payment provider, try again later")
+	}
+
+	queries := repository.New(s.db)
+
+	priceList, err := queries.GetPriceByID(ctx, arg.PriceID)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get price by ID: %v", err)
+	}
+
+	if len(priceList) == 0 {
+		return nil, NewServiceError(ErrCodeNotFound, "price not found")
+	}
+
+	if len(priceList) > 1 {
+		return nil, NewServiceError(ErrCodeInternal, "multiple prices found with the same ID")
+	}
+
+	price := priceList[0]
+
+	productList, err := queries.GetProductByID(ctx, price.ProductID)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get product by ID: %v", err)
+	}
+
+	if len(productList) == 0 {
+		return nil, NewServiceError(ErrCodeNotFound, "product not found")
+	}
+
+	if !price.IsActive || !productList[0].IsActive {
+		return nil, NewServiceError(ErrCodeUnprocessable, "price is not active")
+	}
+
+	entitled, err := s.HasEntitlement(ctx, arg.User, price.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	if entitled {
+		return nil, NewServiceError(ErrCodeConflict, "already entitled to product")
+	}
+
+	var reservation *repository.DiscountReservation
+	var discountExternalID *string
+	if arg.DiscountCode != nil {
+		discount, err := getRedeemableDiscount(ctx, queries, *arg.DiscountCode, price)
+		if err != nil {
+			return nil, err
+		}
+
+		reservation, err = s.reserveDiscount(ctx, *discount, arg.User.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		discountExternalID = &discount.ExternalID
+	}
+
+	checkout, err := s.paymentProvider.CreateCheckout(ctx, *arg.User.ExternalID, price.ExternalID, discountExternalID, env.CheckoutSuccessURL, env.CheckoutCancelURL)
+	if err != nil {
+		// The reservation would otherwise only be released once it expires
+		if reservation != nil {
+			if releaseErr := s.releaseDiscountReservation(ctx, *reservation); releaseErr != nil {
+				slog.Error("Failed to release discount reservation " + reservation.ID + ": " + releaseErr.Error())
+			}
+		}
+
+		return nil, newPaymentProviderError(err, "failed to create checkout in payment provider")
+	}
+
+	if reservation != nil {
+		err = linkDiscountReservation(ctx, queries, *reservation, *checkout)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &Checkout{
+		URL: checkout.URL,
+	}, nil
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"log/slog"
 
 	"github.com/jljl1337/issho/internal/env"
 	"github.com/jljl1337/issho/internal/generator"
@@ -15,6 +16,10 @@ type CreateProductParams struct {
 	Description string
 }
 
+// CreateProduct creates the product before creating it at the payment
+// provider. A product the provider fails to create is created there when it is
+// next updated, or by the sync-products command. A product created at the
+// provider but failing to be linked is reported by the payment reconciliation.
 func (s *EndpointService) CreateProduct(ctx context.Context, arg CreateProductParams) error {
 	if arg.User.Role == env.UserRole {
 		return NewServiceError(ErrCodeForbidden, "insufficient permissions to create product")
@@ -24,23 +29,26 @@ func (s *EndpointService) CreateProduct(ctx context.Context, arg CreateProductPa
 
 	queries := repository.New(s.db)
 
-	product, err := s.paymentProvider.CreateProduct(ctx, payment.CreateProductParams{
+	product := repository.Product{
+		ID:          generator.NewULID(),
+		ExternalID:  nil,
 		Name:        arg.Name,
 		Description: arg.Description,
-	})
-	if err != nil {
-		return NewServiceErrorf(ErrCodeInternal, "failed to create product in payment provider: %v", err)
+		IsActive:    true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
 	}
 
-	product.ID = generator.NewULID()
-	product.CreatedAt = now
-	product.UpdatedAt = now
-
-	err = queries.CreateProduct(ctx, *product)
+	err := queries.CreateProduct(ctx, product)
 	if err != nil {
 		return NewServiceErrorf(ErrCodeInternal, "failed to create product: %v", err)
 	}
 
+	_, err = s.linkProductWithPaymentProvider(ctx, queries, product)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -100,8 +108,12 @@ type UpdateProductByIDParams struct {
 	Name        string
 	Description string
 	IsActive    bool
+	Versions    []string
 }
 
+// UpdateProductByID updates the product before updating it at the payment
+// provider, so that a version conflict fails before the provider is changed.
+// The product is restored if the provider fails to update it.
 func (s *EndpointService) UpdateProductByID(ctx context.Context, arg UpdateProductByIDParams) error {
 	if arg.User.Role == env.UserRole {
 		return NewServiceError(ErrCodeForbidden, "insufficient permissions to update product")
@@ -124,35 +136,157 @@ func (s *EndpointService) UpdateProductByID(ctx context.Context, arg UpdateProdu
 
 	product := productList[0]
 
+	err = checkVersion("product", product.UpdatedAt, arg.Versions)
+	if err != nil {
+		return err
+	}
+
 	// Only modify active status if reactivating a product
 	if !product.IsActive && arg.IsActive {
 		arg.Name = product.Name
 		arg.Description = product.Description
 	}
 
-	newProduct, err := s.paymentProvider.UpdateProduct(ctx, payment.UpdateProductParams{
+	now := generator.NowISO8601()
+
+	params := repository.UpdateProductByIDParams{
+		ID:          arg.ProductID,
 		Name:        arg.Name,
 		Description: arg.Description,
 		IsActive:    arg.IsActive,
-	})
+		UpdatedAt:   now,
+		Version:     versionCondition(product.UpdatedAt, arg.Versions),
+	}
+
+	rowsAffected, err := queries.UpdateProductByID(ctx, params)
 	if err != nil {
-		return NewServiceErrorf(ErrCodeInternal, "failed to update product in payment provider: %v", err)
+		return NewServiceErrorf(ErrCodeInternal, "failed to update product: %v", err)
 	}
 
-	now := generator.NowISO8601()
+	err = checkVersionUpdated("product", rowsAffected)
+	if err != nil {
+		return err
+	}
 
-	params := repository.UpdateProductByIDParams{
-		ID:          arg.ProductID,
-		Name:        newProduct.Name,
-		Description: newProduct.Description,
-		IsActive:    newProduct.IsActive,
-		UpdatedAt:   now,
+	err = s.updateProductWithPaymentProvider(ctx, queries, product, arg)
+	if err != nil {
+		// Linking the product changes it, so it is restored regardless of the
+		// version
+		_, restoreErr := queries.UpdateProductByID(ctx, repository.UpdateProductByIDParams{
+			ID:          product.ID,
+			Name:        product.Name,
+			Description: product.Description,
+			IsActive:    product.IsActive,
+			UpdatedAt:   generator.NowISO8601(),
+			Version:     nil,
+		})
+		if restoreErr != nil {
+			slog.Error("Failed to restore product ID " + product.ID + ": " + restoreErr.Error())
+		}
+
+		return err
 	}
 
-	err = queries.UpdateProductByID(ctx, params)
+	return nil
+}
+
+// updateProductWithPaymentProvider updates the product at the payment
+// provider, creating it there first if it has not been.
+func (s *EndpointService) updateProductWithPaymentProvider(ctx context.Context, queries *repository.Queries, product repository.Product, arg UpdateProductByIDParams) error {
+	externalID, err := s.linkProductWithPaymentProvider(ctx, queries, product)
 	if err != nil {
-		return NewServiceErrorf(ErrCodeInternal, "failed to update product: %v", err)
+		return err
+	}
+
+	priceList, err := queries.GetPriceListByProductID(ctx, product.ID)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get price list by product ID: %v", err)
+	}
+
+	priceExternalIDs := []string{}
+	for _, price := range priceList {
+		if price.IsActive {
+			priceExternalIDs = append(priceExternalIDs, price.ExternalID)
+		}
+	}
+
+	_, err = s.paymentProvider.UpdateProduct(ctx, payment.UpdateProductParams{
+		ExternalID:       externalID,
+		Name:             arg.Name,
+		Description:      arg.Description,
+		IsActive:         arg.IsActive,
+		PriceExternalIDs: priceExternalIDs,
+	})
+	if err != nil {
+		return newPaymentProviderError(err, "failed to update product in payment provider")
 	}
 
 	return nil
 }
+
+// SyncProducts creates the payment provider product of every product created
+// before products were synced with the provider, and returns the number of
+// products synced.
+func (s *EndpointService) SyncProducts(ctx context.Context) (int, error) {
+	queries := repository.New(s.db)
+
+	productList, err := queries.GetProductListWithoutExternalID(ctx)
+	if err != nil {
+		return 0, NewServiceErrorf(ErrCodeInternal, "failed to get product list without external ID: %v", err)
+	}
+
+	for i, product := range productList {
+		_, err := s.linkProductWithPaymentProvider(ctx, queries, product)
+		if err != nil {
+			return i, err
+		}
+	}
+
+	return len(productList), nil
+}
+
+// linkProductWithPaymentProvider returns the external ID of the product. A
+// product without one is created at the payment provider with its existing
+// prices attached.
+func (s *EndpointService) linkProductWithPaymentProvider(ctx context.Context, queries *repository.Queries, product repository.Product) (string, error) {
+	if product.ExternalID != nil {
+		return *product.ExternalID, nil
+	}
+
+	newProduct, err := s.paymentProvider.CreateProduct(ctx, payment.CreateProductParams{
+		Name:        product.Name,
+		Description: product.Description,
+	})
+	if err != nil {
+		return "", newPaymentProviderError(err, "failed to create product in payment provider")
+	}
+
+	if newProduct.ExternalID == nil {
+		return "", NewServiceError(ErrCodeInternal, "payment provider returned no product ID")
+	}
+
+	externalID := *newProduct.ExternalID
+
+	priceList, err := queries.GetPriceListByProductID(ctx, product.ID)
+	if err != nil {
+		return "", NewServiceErrorf(ErrCodeInternal, "failed to get price list by product ID: %v", err)
+	}
+
+	for _, price := range priceList {
+		err = s.paymentProvider.AttachPrice(ctx, externalID, price.ExternalID)
+		if err != nil {
+			return "", newPaymentProviderError(err, "failed to attach price in payment provider")
+		}
+	}
+
+	err = queries.UpdateProductExternalIDByID(ctx, repository.UpdateProductExternalIDByIDParams{
+		ID:         product.ID,
+		ExternalID: externalID,
+		UpdatedAt:  generator.NowISO8601(),
+	})
+	if err != nil {
+		return "", NewServiceErrorf(ErrCodeInternal, "failed to update product external ID: %v", err)
+	}
+
+	return externalID, nil
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/repository"
+)
+
+type GetSubscriptionListParams struct {
+	User     repository.User
+	Cursor   *string
+	CursorID *string
+	PageSize int
+}
+
+// GetSubscriptionList returns the subscriptions of the user, latest first.
+func (s *EndpointService) GetSubscriptionList(ctx context.Context, arg GetSubscriptionListParams) ([]repository.Subscription, error) {
+	if arg.PageSize <= 0 || arg.PageSize > env.PageSizeMax {
+		arg.PageSize = env.PageSizeDefault
+	}
+
+	queries := repository.New(s.db)
+
+	subscriptions, err := queries.GetSubscriptionListByUserID(ctx, repository.GetSubscriptionListByUserIDParams{
+		UserID:   arg.User.ID,
+		Cursor:   arg.Cursor,
+		CursorID: arg.CursorID,
+		PageSize: arg.PageSize,
+	})
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get subscription list: %v", err)
+	}
+
+	return subscriptions, nil
+}
+
+type GetOrderListParams struct {
+	User     repository.User
+	Cursor   *string
+	CursorID *string
+	PageSize int
+}
+
+// GetOrderList returns the orders of the user, latest first.
+func (s *EndpointService) GetOrderList(ctx context.Context, arg GetOrderListParams) ([]repository.Order, error) {
+	if arg.PageSize <= 0 || arg.PageSize > env.PageSizeMax {
+		arg.PageSize = env.PageSizeDefault
+	}
+
+	queries := repository.New(s.db)
+
+	orders, err := queries.GetOrderListByUserID(ctx, repository.GetOrderListByUserIDParams{
+		UserID:   arg.User.ID,
+		Cursor:   arg.Cursor,
+		CursorID: arg.CursorID,
+		PageSize: arg.PageSize,
+	})
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get order list: %v", err)
+	}
+
+	return orders, nil
+}
+
+type SyncPurchasesParams struct {
+	User repository.User
+}
+
+// SyncPurchases fetches the subscriptions and orders of the user from the
+// payment provider and syncs them, e.g. when the user returns from a checkout
+// before its webhook events are processed.
+func (s *EndpointService) SyncPurchases(ctx context.Context, arg SyncPurchasesParams) error {
+	if arg.User.ExternalID == nil {
+		return nil
+	}
+
+	subscriptions, err := s.paymentProvider.GetSubscriptionList(ctx, *arg.User.ExternalID)
+	if err != nil {
+		return newPaymentProviderError(err, "failed to get subscriptions from payment provider")
+	}
+
+	orders, err := s.paymentProvider.GetOrderList(ctx, *arg.User.ExternalID)
+	if err != nil {
+		return newPaymentProviderError(err, "failed to get orders from payment provider")
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to begin transaction: %v", err)
+	}
+
+	defer tx.Rollback()
+
+	queries := repository.New(tx)
+
+	for _, subscription := range subscriptions {
+		_, err = syncSubscription(ctx, queries, subscription)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, order := range orders {
+		_, err = syncOrder(ctx, queries, order)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
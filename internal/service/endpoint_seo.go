@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/format"
+	"github.com/jljl1337/issho/internal/generator"
+	"github.com/jljl1337/issho/internal/repository"
+	"github.com/jljl1337/issho/internal/seo"
+)
+
+// sitemapURLCountMax is the maximum number of URLs in a sitemap.
+const sitemapURLCountMax = 50000
+
+type GetPostPageParams struct {
+	PostID         string
+	AcceptLanguage string
+}
+
+// GetPostPage returns the metadata of the page of a post as seen by anonymous
+// visitors such as crawlers, so only published posts are found. The image is
+// the first image attached to the post, if visitors can read the attachments.
+func (s *EndpointService) GetPostPage(ctx context.Context, arg GetPostPageParams) (*seo.Page, error) {
+	queries := repository.New(s.db)
+
+	postList, err := queries.GetPostByID(ctx, arg.PostID)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get post by ID: %v", err)
+	}
+
+	if len(postList) == 0 || !isPostPublished(postList[0], generator.NowISO8601()) {
+		return nil, NewServiceError(ErrCodeNotFound, "post not found")
+	}
+
+	post := postList[0]
+
+	postViews, err := toPostViews(ctx, queries, []repository.Post{post}, nil, preferredLanguageCodes(nil, arg.AcceptLanguage))
+	if err != nil {
+		return nil, err
+	}
+
+	postView := postViews[0]
+
+	publishedAt, err := format.ISO8601ToTime(*post.PublishedAt)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to parse post publish time: %v", err)
+	}
+
+	updatedAt, err := format.ISO8601ToTime(post.UpdatedAt)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to parse post update time: %v", err)
+	}
+
+	// A scheduled post is last updated before it is published
+	if updatedAt.Before(publishedAt) {
+		updatedAt = publishedAt
+	}
+
+	page := &seo.Page{
+		Title:        postView.Title,
+		Description:  postView.Description,
+		URL:          env.SiteURL + "/posts/" + post.ID,
+		SiteName:     env.SiteTitle,
+		LanguageCode: postView.ContentLanguageCode,
+		Article: &seo.Article{
+			Tags:        postView.Tags,
+			PublishedAt: publishedAt,
+			UpdatedAt:   updatedAt,
+		},
+	}
+
+	if post.UserID != nil {
+		author, err := queries.GetUserByID(ctx, *post.UserID)
+		if err != nil {
+			return nil, NewServiceErrorf(ErrCodeInternal, "failed to get post author: %v", err)
+		}
+
+		page.Article.AuthorName = author.Username
+	}
+
+	canViewAttachments, err := canViewPostAttachments(ctx, queries, nil, post)
+	if err != nil {
+		return nil, err
+	}
+
+	if canViewAttachments {
+		attachments, err := queries.GetAttachmentListByPostID(ctx, post.ID)
+		if err != nil {
+			return nil, NewServiceErrorf(ErrCodeInternal, "failed to get attachments: %v", err)
+		}
+
+		for _, attachment := range attachments {
+			if strings.HasPrefix(attachment.ContentType, "image/") {
+				page.ImageURL = env.SiteURL + "/api/attachments/" + attachment.ID
+				break
+			}
+		}
+	}
+
+	return page, nil
+}
+
+// GetSitemap returns the home page and the pages of the published posts, up
+// to the maximum number of URLs in a sitemap.
+func (s *EndpointService) GetSitemap(ctx context.Context) ([]seo.URL, error) {
+	queries := repository.New(s.db)
+
+	urls := []seo.URL{
+		{Loc: env.SiteURL + "/"},
+	}
+
+	now := generator.NowISO8601()
+
+	var cursor, cursorID *string
+	for len(urls) < sitemapURLCountMax {
+		posts, err := queries.GetPostList(ctx, repository.GetPostListParams{
+			UserID:      nil,
+			Tag:         nil,
+			SearchQuery: nil,
+			OrderBy:     "published_at",
+			Ascending:   false,
+			IncludeAll:  false,
+			Now:         now,
+			PageSize:    min(env.PageSizeMax, sitemapURLCountMax-len(urls)),
+			Cursor:      cursor,
+			CursorID:    cursorID,
+		})
+		if err != nil {
+			return nil, NewServiceErrorf(ErrCodeInternal, "failed to get post list: %v", err)
+		}
+
+		if len(posts) == 0 {
+			break
+		}
+
+		for _, post := range posts {
+			lastModified, err := format.ISO8601ToTime(max(post.UpdatedAt, *post.PublishedAt))
+			if err != nil {
+				return nil, NewServiceErrorf(ErrCodeInternal, "failed to parse post update time: %v", err)
+			}
+
+			urls = append(urls, seo.URL{
+				Loc:          env.SiteURL + "/posts/" + post.ID,
+				LastModified: lastModified,
+			})
+		}
+
+		lastPost := posts[len(posts)-1]
+		cursor = lastPost.PublishedAt
+		cursorID = &lastPost.ID
+	}
+
+	return urls, nil
+}
@@ -0,0 +1,551 @@
+package service
+
+import (
+	"context"
+	"slices"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/generator"
+	"github.com/jljl1337/issho/internal/repository"
+)
+
+type CreateSeriesParams struct {
+	User        repository.User
+	Title       string
+	Description string
+	Slug        string
+}
+
+func (s *EndpointService) CreateSeries(ctx context.Context, arg CreateSeriesParams) error {
+	if arg.User.Role == env.UserRole {
+		return NewServiceError(ErrCodeForbidden, "insufficient permissions to create series")
+	}
+
+	queries := repository.New(s.db)
+
+	slug, err := checkSeriesSlug(ctx, queries, arg.Slug, arg.Title, "")
+	if err != nil {
+		return err
+	}
+
+	now := generator.NowISO8601()
+
+	err = queries.CreateSeries(ctx, repository.Series{
+		ID:          generator.NewULID(),
+		UserID:      &arg.User.ID,
+		Title:       arg.Title,
+		Description: arg.Description,
+		Slug:        slug,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to create series: %v", err)
+	}
+
+	return nil
+}
+
+type GetSeriesListParams struct {
+	Cursor   *string
+	CursorID *string
+	PageSize int
+}
+
+func (s *EndpointService) GetSeriesList(ctx context.Context, arg GetSeriesListParams) ([]repository.Series, error) {
+	if arg.PageSize <= 0 || arg.PageSize > env.PageSizeMax {
+		arg.PageSize = env.PageSizeDefault
+	}
+
+	queries := repository.New(s.db)
+
+	seriesList, err := queries.GetSeriesList(ctx, repository.GetSeriesListParams{
+		Cursor:   arg.Cursor,
+		CursorID: arg.CursorID,
+		PageSize: arg.PageSize,
+	})
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get series list: %v", err)
+	}
+
+	return seriesList, nil
+}
+
+// SeriesView is a series together with its posts in order. Only the posts
+// that the viewer can see are included.
+type SeriesView struct {
+	repository.Series
+	Posts []PostView `json:"posts"`
+}
+
+type GetSeriesByIDParams struct {
+	User           repository.User
+	SeriesID       string
+	AcceptLanguage string
+}
+
+func (s *EndpointService) GetSeriesByID(ctx context.Context, arg GetSeriesByIDParams) (*SeriesView, error) {
+	queries := repository.New(s.db)
+
+	series, err := getSeriesByID(ctx, queries, arg.SeriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toSeriesView(ctx, queries, *series, arg.User, arg.AcceptLanguage)
+}
+
+type GetSeriesBySlugParams struct {
+	User           repository.User
+	Slug           string
+	AcceptLanguage string
+}
+
+func (s *EndpointService) GetSeriesBySlug(ctx context.Context, arg GetSeriesBySlugParams) (*SeriesView, error) {
+	queries := repository.New(s.db)
+
+	seriesList, err := queries.GetSeriesBySlug(ctx, arg.Slug)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get series by slug: %v", err)
+	}
+
+	if len(seriesList) == 0 {
+		return nil, NewServiceError(ErrCodeNotFound, "series not found")
+	}
+
+	return toSeriesView(ctx, queries, seriesList[0], arg.User, arg.AcceptLanguage)
+}
+
+type UpdateSeriesByIDParams struct {
+	User        repository.User
+	SeriesID    string
+	Title       string
+	Description string
+	Slug        string
+	Versions    []string
+}
+
+func (s *EndpointService) UpdateSeriesByID(ctx context.Context, arg UpdateSeriesByIDParams) error {
+	if arg.User.Role == env.UserRole {
+		return NewServiceError(ErrCodeForbidden, "insufficient permissions to update series")
+	}
+
+	queries := repository.New(s.db)
+
+	series, err := getSeriesByID(ctx, queries, arg.SeriesID)
+	if err != nil {
+		return err
+	}
+
+	err = checkVersion("series", series.UpdatedAt, arg.Versions)
+	if err != nil {
+		return err
+	}
+
+	slug, err := checkSeriesSlug(ctx, queries, arg.Slug, arg.Title, series.ID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := queries.UpdateSeriesByID(ctx, repository.UpdateSeriesByIDParams{
+		Title:       arg.Title,
+		Description: arg.Description,
+		Slug:        slug,
+		UpdatedAt:   generator.NowISO8601(),
+		ID:          series.ID,
+		Version:     versionCondition(series.UpdatedAt, arg.Versions),
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to update series: %v", err)
+	}
+
+	err = checkVersionUpdated("series", rowsAffected)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+type DeleteSeriesByIDParams struct {
+	User     repository.User
+	SeriesID string
+}
+
+// DeleteSeriesByID deletes the series, but not its posts.
+func (s *EndpointService) DeleteSeriesByID(ctx context.Context, arg DeleteSeriesByIDParams) error {
+	if arg.User.Role == env.UserRole {
+		return NewServiceError(ErrCodeForbidden, "insufficient permissions to delete series")
+	}
+
+	queries := repository.New(s.db)
+
+	series, err := getSeriesByID(ctx, queries, arg.SeriesID)
+	if err != nil {
+		return err
+	}
+
+	err = queries.DeleteSeriesByID(ctx, series.ID)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to delete series: %v", err)
+	}
+
+	return nil
+}
+
+type SetSeriesPostsParams struct {
+	User     repository.User
+	SeriesID string
+	PostIDs  []string
+	Versions []string
+}
+
+// SetSeriesPosts replaces the posts of the series with the posts in the given
+// order, keeping the posts in the trash. It is used to add, remove and reorder
+// the posts. A post can only be in one series, and adding a post to a series
+// requires permission to update the post.
+func (s *EndpointService) SetSeriesPosts(ctx context.Context, arg SetSeriesPostsParams) error {
+	if arg.User.Role == env.UserRole {
+		return NewServiceError(ErrCodeForbidden, "insufficient permissions to update series")
+	}
+
+	seen := make(map[string]bool)
+	for _, postID := range arg.PostIDs {
+		if seen[postID] {
+			return NewServiceError(ErrCodeUnprocessable, "duplicate post in series")
+		}
+		seen[postID] = true
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to begin transaction: %v", err)
+	}
+
+	defer tx.Rollback()
+
+	queries := repository.New(tx)
+
+	series, err := getSeriesByID(ctx, queries, arg.SeriesID)
+	if err != nil {
+		return err
+	}
+
+	err = checkVersion("series", series.UpdatedAt, arg.Versions)
+	if err != nil {
+		return err
+	}
+
+	seriesPosts, err := queries.GetSeriesPostListByPostIDs(ctx, arg.PostIDs)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get series posts: %v", err)
+	}
+
+	mapPostIDToSeriesID := make(map[string]string)
+	for _, seriesPost := range seriesPosts {
+		mapPostIDToSeriesID[seriesPost.PostID] = seriesPost.SeriesID
+	}
+
+	for _, postID := range arg.PostIDs {
+		seriesID, isInSeries := mapPostIDToSeriesID[postID]
+		if isInSeries && seriesID == series.ID {
+			continue
+		}
+
+		if isInSeries {
+			return NewServiceError(ErrCodeUnprocessable, "post is already in another series")
+		}
+
+		post, err := getPostByID(ctx, queries, postID)
+		if err != nil {
+			return err
+		}
+
+		err = checkPostRole(ctx, queries, arg.User, post.ID, env.PostCollaboratorRoleEditor, "add post to series")
+		if err != nil {
+			return err
+		}
+	}
+
+	// Posts in the trash are hidden from the client, so they are kept in the
+	// series for when they are restored
+	currentSeriesPosts, err := queries.GetSeriesPostListBySeriesID(ctx, series.ID)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get series posts: %v", err)
+	}
+
+	trashedPosts, err := queries.GetTrashedPostListBySeriesID(ctx, series.ID)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get trashed series posts: %v", err)
+	}
+
+	isTrashed := make(map[string]bool)
+	for _, post := range trashedPosts {
+		isTrashed[post.ID] = true
+	}
+
+	postIDs := keepTrashedSeriesPosts(currentSeriesPosts, isTrashed, arg.PostIDs)
+
+	_, err = queries.DeleteSeriesPostBySeriesID(ctx, series.ID)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to delete series posts: %v", err)
+	}
+
+	now := generator.NowISO8601()
+
+	for i, postID := range postIDs {
+		err = queries.CreateSeriesPost(ctx, repository.SeriesPost{
+			SeriesID:  series.ID,
+			PostID:    postID,
+			Position:  i + 1,
+			CreatedAt: now,
+		})
+		if err != nil {
+			return NewServiceErrorf(ErrCodeInternal, "failed to create series post: %v", err)
+		}
+	}
+
+	rowsAffected, err := queries.UpdateSeriesUpdatedAtByID(ctx, repository.UpdateSeriesUpdatedAtByIDParams{
+		UpdatedAt: now,
+		ID:        series.ID,
+		Version:   versionCondition(series.UpdatedAt, arg.Versions),
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to update series: %v", err)
+	}
+
+	err = checkVersionUpdated("series", rowsAffected)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+func getSeriesByID(ctx context.Context, queries *repository.Queries, seriesID string) (*repository.Series, error) {
+	seriesList, err := queries.GetSeriesByID(ctx, seriesID)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get series by ID: %v", err)
+	}
+
+	if len(seriesList) == 0 {
+		return nil, NewServiceError(ErrCodeNotFound, "series not found")
+	}
+
+	return &seriesList[0], nil
+}
+
+// checkSeriesSlug validates the slug of a series, deriving it from the title
+// if not given, and checks that no other series uses it.
+func checkSeriesSlug(ctx context.Context, queries *repository.Queries, slug, title, seriesID string) (string, error) {
+	if slug == "" {
+		slug = slugify(title)
+	}
+
+	isValid, err := checkSlug(slug)
+	if err != nil {
+		return "", NewServiceErrorf(ErrCodeInternal, "failed to check slug: %v", err)
+	}
+
+	if !isValid {
+		return "", NewServiceError(ErrCodeUnprocessable, "invalid slug")
+	}
+
+	seriesList, err := queries.GetSeriesBySlug(ctx, slug)
+	if err != nil {
+		return "", NewServiceErrorf(ErrCodeInternal, "failed to get series by slug: %v", err)
+	}
+
+	if len(seriesList) > 0 && seriesList[0].ID != seriesID {
+		return "", NewServiceError(ErrCodeSlugTaken, "slug already exists")
+	}
+
+	return slug, nil
+}
+
+// PostSeries is the place of a post in its series, with links to the previous
+// and next posts. The position and the links only count the posts that the
+// viewer can see, so drafts are skipped for readers.
+type PostSeries struct {
+	ID       string          `json:"id"`
+	Title    string          `json:"title"`
+	Slug     string          `json:"slug"`
+	Position int             `json:"position"`
+	Count    int             `json:"count"`
+	Previous *SeriesPostLink `json:"previous"`
+	Next     *SeriesPostLink `json:"next"`
+}
+
+type SeriesPostLink struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// getPostSeries returns the place of the post in its series, or nil if the
+// post is not in a series. The titles of the links are translated to the
+// first available language code.
+func getPostSeries(ctx context.Context, queries *repository.Queries, post repository.Post, viewer repository.User, languageCodes []string) (*PostSeries, error) {
+	seriesPosts, err := queries.GetSeriesPostListByPostIDs(ctx, []string{post.ID})
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get series posts: %v", err)
+	}
+
+	if len(seriesPosts) == 0 {
+		return nil, nil
+	}
+
+	series, err := getSeriesByID(ctx, queries, seriesPosts[0].SeriesID)
+	if err != nil {
+		return nil, err
+	}
+
+	posts, err := getVisibleSeriesPostList(ctx, queries, series.ID, viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	index := slices.IndexFunc(posts, func(p repository.Post) bool {
+		return p.ID == post.ID
+	})
+	if index < 0 {
+		return nil, nil
+	}
+
+	linkedPostIDs := []string{}
+	if index > 0 {
+		linkedPostIDs = append(linkedPostIDs, posts[index-1].ID)
+	}
+	if index < len(posts)-1 {
+		linkedPostIDs = append(linkedPostIDs, posts[index+1].ID)
+	}
+
+	translations, err := queries.GetPostTranslationListByPostIDs(ctx, linkedPostIDs)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get post translations: %v", err)
+	}
+
+	toLink := func(linkedPost repository.Post) *SeriesPostLink {
+		postTranslations := []repository.PostTranslation{}
+		for _, translation := range translations {
+			if translation.PostID == linkedPost.ID {
+				postTranslations = append(postTranslations, translation)
+			}
+		}
+
+		translatePost(&linkedPost, postTranslations, languageCodes)
+
+		return &SeriesPostLink{
+			ID:    linkedPost.ID,
+			Title: linkedPost.Title,
+		}
+	}
+
+	postSeries := &PostSeries{
+		ID:       series.ID,
+		Title:    series.Title,
+		Slug:     series.Slug,
+		Position: index + 1,
+		Count:    len(posts),
+	}
+
+	if index > 0 {
+		postSeries.Previous = toLink(posts[index-1])
+	}
+
+	if index < len(posts)-1 {
+		postSeries.Next = toLink(posts[index+1])
+	}
+
+	return postSeries, nil
+}
+
+// getVisibleSeriesPostList returns the posts of the series in order that the
+// viewer can see.
+func getVisibleSeriesPostList(ctx context.Context, queries *repository.Queries, seriesID string, viewer repository.User) ([]repository.Post, error) {
+	posts, err := queries.GetPostListBySeriesID(ctx, seriesID)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get series posts: %v", err)
+	}
+
+	visiblePosts := []repository.Post{}
+	for _, post := range posts {
+		canView, err := canViewPost(ctx, queries, &viewer, post)
+		if err != nil {
+			return nil, err
+		}
+
+		if canView {
+			visiblePosts = append(visiblePosts, post)
+		}
+	}
+
+	return visiblePosts, nil
+}
+
+func toSeriesView(ctx context.Context, queries *repository.Queries, series repository.Series, viewer repository.User, acceptLanguage string) (*SeriesView, error) {
+	posts, err := getVisibleSeriesPostList(ctx, queries, series.ID, viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	postViews, err := toPostViews(ctx, queries, posts, &viewer, preferredLanguageCodes(&viewer, acceptLanguage))
+	if err != nil {
+		return nil, err
+	}
+
+	return &SeriesView{
+		Series: series,
+		Posts:  postViews,
+	}, nil
+}
+
+// keepTrashedSeriesPosts returns the post IDs with the posts in the trash of
+// the series kept in it. A post in the trash stays after the post it followed,
+// or goes to the end if that post has been removed from the series.
+func keepTrashedSeriesPosts(seriesPosts []repository.SeriesPost, isTrashed map[string]bool, postIDs []string) []string {
+	// The posts in the trash following each post, or the start of the series
+	mapPostIDToTrashedPostIDs := make(map[string][]string)
+	previousPostID := ""
+	for _, seriesPost := range seriesPosts {
+		if isTrashed[seriesPost.PostID] {
+			mapPostIDToTrashedPostIDs[previousPostID] = append(mapPostIDToTrashedPostIDs[previousPostID], seriesPost.PostID)
+			continue
+		}
+
+		previousPostID = seriesPost.PostID
+	}
+
+	result := []string{}
+	isKept := make(map[string]bool)
+
+	keep := func(trashedPostIDs []string) {
+		for _, postID := range trashedPostIDs {
+			result = append(result, postID)
+			isKept[postID] = true
+		}
+	}
+
+	keep(mapPostIDToTrashedPostIDs[""])
+
+	for _, postID := range postIDs {
+		if isTrashed[postID] {
+			continue
+		}
+
+		result = append(result, postID)
+		keep(mapPostIDToTrashedPostIDs[postID])
+	}
+
+	for _, seriesPost := range seriesPosts {
+		if isTrashed[seriesPost.PostID] && !isKept[seriesPost.PostID] {
+			result = append(result, seriesPost.PostID)
+		}
+	}
+
+	return result
+}
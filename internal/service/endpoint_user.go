@@ -6,7 +6,6 @@ import (
 	"github.com/jljl1337/issho/internal/crypto"
 	"github.com/jljl1337/issho/internal/env"
 	"github.com/jljl1337/issho/internal/generator"
-	"github.com/jljl1337/issho/internal/payment"
 	"github.com/jljl1337/issho/internal/repository"
 )
 
@@ -120,14 +119,7 @@ func (s *EndpointService) ConfirmEmailVerification(ctx context.Context, arg Conf
 	}
 
 	// Validate verification code
-	tx, err := s.db.BeginTxx(ctx, nil)
-	if err != nil {
-		return NewServiceErrorf(ErrCodeInternal, "failed to begin transaction: %v", err)
-	}
-
-	defer tx.Rollback()
-
-	queries := repository.New(tx)
+	queries := repository.New(s.db)
 
 	now := generator.NowISO8601()
 
@@ -155,17 +147,16 @@ func (s *EndpointService) ConfirmEmailVerification(ctx context.Context, arg Conf
 		return NewServiceError(ErrCodeVerificationFailed, "code is invalid or expired")
 	}
 
-	// Create customer in payment provider
-	customerExternalID, err := s.paymentProvider.CreateCustomer(ctx, payment.CreateCustomerParams{
-		Name:         arg.User.Username,
-		Email:        arg.User.Email,
-		LanguageCode: arg.User.LanguageCode,
-	})
+	// Update verification status and user record
+	tx, err := s.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return NewServiceErrorf(ErrCodeInternal, "failed to create customer in payment provider: %v", err)
+		return NewServiceErrorf(ErrCodeInternal, "failed to begin transaction: %v", err)
 	}
 
-	// Update verification status and user record
+	defer tx.Rollback()
+
+	queries = repository.New(tx)
+
 	err = queries.UpdateEmailVerificationStatusByID(ctx, repository.UpdateEmailVerificationStatusByIDParams{
 		ID:        verification.ID,
 		Status:    env.EmailVerificationStatusVerified,
@@ -176,14 +167,22 @@ func (s *EndpointService) ConfirmEmailVerification(ctx context.Context, arg Conf
 	}
 
 	err = queries.VerifyUser(ctx, repository.VerifyUserParams{
-		ID:         arg.User.ID,
-		ExternalID: customerExternalID,
-		UpdatedAt:  now,
+		ID:        arg.User.ID,
+		UpdatedAt: now,
 	})
 	if err != nil {
 		return NewServiceErrorf(ErrCodeInternal, "failed to verify user: %v", err)
 	}
 
+	// Create customer in payment provider
+	err = enqueuePaymentOutbox(ctx, queries, enqueuePaymentOutboxParams{
+		Type:   env.PaymentOutboxTypeCreateCustomer,
+		UserID: &arg.User.ID,
+	})
+	if err != nil {
+		return err
+	}
+
 	err = tx.Commit()
 	if err != nil {
 		return NewServiceErrorf(ErrCodeInternal, "failed to commit transaction: %v", err)
@@ -211,24 +210,6 @@ func (s *EndpointService) UpdateUsernameByID(ctx context.Context, arg UpdateUser
 		return NewServiceError(ErrCodeUnprocessable, "new username must be different from the old username")
 	}
 
-	// Update customer in payment provider if needed
-	if arg.User.Role == env.UserRole && arg.User.IsVerified {
-		if arg.User.ExternalID == nil {
-			return NewServiceError(ErrCodeInternal, "user external ID is nil")
-		}
-
-		externalID := *arg.User.ExternalID
-		err := s.paymentProvider.UpdateCustomer(ctx, payment.UpdateCustomerParams{
-			ExternalID:   externalID,
-			Name:         arg.NewUsername,
-			Email:        arg.User.Email,
-			LanguageCode: arg.User.LanguageCode,
-		})
-		if err != nil {
-			return NewServiceErrorf(ErrCodeInternal, "failed to update customer in payment provider: %v", err)
-		}
-	}
-
 	queries := repository.New(s.db)
 
 	// Check if new username is the same as the old one or already taken
@@ -245,6 +226,15 @@ func (s *EndpointService) UpdateUsernameByID(ctx context.Context, arg UpdateUser
 		return NewServiceError(ErrCodeUsernameTaken, "username already taken")
 	}
 
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to begin transaction: %v", err)
+	}
+
+	defer tx.Rollback()
+
+	queries = repository.New(tx)
+
 	err = queries.UpdateUserUsername(ctx, repository.UpdateUserUsernameParams{
 		ID:        arg.User.ID,
 		Username:  arg.NewUsername,
@@ -254,6 +244,22 @@ func (s *EndpointService) UpdateUsernameByID(ctx context.Context, arg UpdateUser
 		return NewServiceErrorf(ErrCodeInternal, "failed to update username: %v", err)
 	}
 
+	// Update customer in payment provider if needed
+	if arg.User.Role == env.UserRole && arg.User.IsVerified {
+		err = enqueuePaymentOutbox(ctx, queries, enqueuePaymentOutboxParams{
+			Type:   env.PaymentOutboxTypeUpdateCustomer,
+			UserID: &arg.User.ID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to commit transaction: %v", err)
+	}
+
 	return nil
 }
 
@@ -426,14 +432,7 @@ type ConfirmEmailChangeParams struct {
 
 func (s *EndpointService) ConfirmEmailChange(ctx context.Context, arg ConfirmEmailChangeParams) error {
 	// Validate verification code
-	tx, err := s.db.BeginTxx(ctx, nil)
-	if err != nil {
-		return NewServiceErrorf(ErrCodeInternal, "failed to begin transaction: %v", err)
-	}
-
-	defer tx.Rollback()
-
-	queries := repository.New(tx)
+	queries := repository.New(s.db)
 
 	now := generator.NowISO8601()
 
@@ -461,43 +460,41 @@ func (s *EndpointService) ConfirmEmailChange(ctx context.Context, arg ConfirmEma
 		return NewServiceError(ErrCodeVerificationFailed, "code is invalid or expired")
 	}
 
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to begin transaction: %v", err)
+	}
+
+	defer tx.Rollback()
+
+	queries = repository.New(tx)
+
 	if !arg.User.IsVerified {
-		// Create customer in payment provider
-		customerExternalID, err := s.paymentProvider.CreateCustomer(ctx, payment.CreateCustomerParams{
-			Name:         arg.User.Username,
-			Email:        verification.Email,
-			LanguageCode: arg.User.LanguageCode,
+		// Update verification status and user record
+		err = queries.VerifyUser(ctx, repository.VerifyUserParams{
+			ID:        arg.User.ID,
+			UpdatedAt: now,
 		})
 		if err != nil {
-			return NewServiceErrorf(ErrCodeInternal, "failed to create customer in payment provider: %v", err)
+			return NewServiceErrorf(ErrCodeInternal, "failed to verify user: %v", err)
 		}
 
-		// Update verification status and user record
-		err = queries.VerifyUser(ctx, repository.VerifyUserParams{
-			ID:         arg.User.ID,
-			ExternalID: customerExternalID,
-			UpdatedAt:  now,
+		// Create customer in payment provider
+		err = enqueuePaymentOutbox(ctx, queries, enqueuePaymentOutboxParams{
+			Type:   env.PaymentOutboxTypeCreateCustomer,
+			UserID: &arg.User.ID,
 		})
 		if err != nil {
-			return NewServiceErrorf(ErrCodeInternal, "failed to verify user: %v", err)
+			return err
 		}
-	} else {
+	} else if arg.User.Role == env.UserRole {
 		// Update email in payment provider for customers that are already verified
-		if arg.User.Role == env.UserRole {
-			if arg.User.ExternalID == nil {
-				return NewServiceError(ErrCodeInternal, "user external ID is nil")
-			}
-
-			externalID := *arg.User.ExternalID
-			err = s.paymentProvider.UpdateCustomer(ctx, payment.UpdateCustomerParams{
-				ExternalID:   externalID,
-				Name:         arg.User.Username,
-				Email:        verification.Email,
-				LanguageCode: arg.User.LanguageCode,
-			})
-			if err != nil {
-				return NewServiceErrorf(ErrCodeInternal, "failed to update customer in payment provider: %v", err)
-			}
+		err = enqueuePaymentOutbox(ctx, queries, enqueuePaymentOutboxParams{
+			Type:   env.PaymentOutboxTypeUpdateCustomer,
+			UserID: &arg.User.ID,
+		})
+		if err != nil {
+			return err
 		}
 	}
 
@@ -542,27 +539,16 @@ func (s *EndpointService) UpdateLanguageByID(ctx context.Context, arg UpdateLang
 		return NewServiceError(ErrCodeUnprocessable, "new language code must be different from the old language code")
 	}
 
-	// Update customer in payment provider if needed
-	if arg.User.Role == env.UserRole && arg.User.IsVerified {
-		if arg.User.ExternalID == nil {
-			return NewServiceError(ErrCodeInternal, "user external ID is nil")
-		}
-
-		externalID := *arg.User.ExternalID
-		err := s.paymentProvider.UpdateCustomer(ctx, payment.UpdateCustomerParams{
-			ExternalID:   externalID,
-			Name:         arg.User.Username,
-			Email:        arg.User.Email,
-			LanguageCode: arg.LanguageCode,
-		})
-		if err != nil {
-			return NewServiceErrorf(ErrCodeInternal, "failed to update customer in payment provider: %v", err)
-		}
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to begin transaction: %v", err)
 	}
 
-	queries := repository.New(s.db)
+	defer tx.Rollback()
+
+	queries := repository.New(tx)
 
-	err := queries.UpdateUserLanguage(ctx, repository.UpdateUserLanguageParams{
+	err = queries.UpdateUserLanguage(ctx, repository.UpdateUserLanguageParams{
 		ID:           arg.User.ID,
 		LanguageCode: arg.LanguageCode,
 		UpdatedAt:    generator.NowISO8601(),
@@ -571,29 +557,56 @@ func (s *EndpointService) UpdateLanguageByID(ctx context.Context, arg UpdateLang
 		return NewServiceErrorf(ErrCodeInternal, "failed to update language: %v", err)
 	}
 
+	// Update customer in payment provider if needed
+	if arg.User.Role == env.UserRole && arg.User.IsVerified {
+		err = enqueuePaymentOutbox(ctx, queries, enqueuePaymentOutboxParams{
+			Type:   env.PaymentOutboxTypeUpdateCustomer,
+			UserID: &arg.User.ID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to commit transaction: %v", err)
+	}
+
 	return nil
 }
 
 func (s *EndpointService) DeleteUserByID(ctx context.Context, user repository.User) error {
-	// Delete customer in payment provider if needed
-	if user.Role == env.UserRole && user.IsVerified {
-		if user.ExternalID == nil {
-			return NewServiceError(ErrCodeInternal, "user external ID is nil")
-		}
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to begin transaction: %v", err)
+	}
+
+	defer tx.Rollback()
+
+	queries := repository.New(tx)
 
-		externalID := *user.ExternalID
-		err := s.paymentProvider.DeleteCustomer(ctx, externalID)
+	// Delete user record, with the pending changes of its customer
+	err = queries.DeleteUser(ctx, user.ID)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to delete user: %v", err)
+	}
+
+	// Delete customer in payment provider if needed. A customer still being
+	// created is left to the reconciliation.
+	if user.ExternalID != nil {
+		err = enqueuePaymentOutbox(ctx, queries, enqueuePaymentOutboxParams{
+			Type:               env.PaymentOutboxTypeDeleteCustomer,
+			CustomerExternalID: user.ExternalID,
+		})
 		if err != nil {
-			return NewServiceErrorf(ErrCodeInternal, "failed to delete customer in payment provider: %v", err)
+			return err
 		}
 	}
 
-	// Delete user record
-	queries := repository.New(s.db)
-
-	err := queries.DeleteUser(ctx, user.ID)
+	err = tx.Commit()
 	if err != nil {
-		return NewServiceErrorf(ErrCodeInternal, "failed to delete user: %v", err)
+		return NewServiceErrorf(ErrCodeInternal, "failed to commit transaction: %v", err)
 	}
 
 	return nil
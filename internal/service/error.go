@@ -11,13 +11,26 @@ const (
 	ErrCodeNotFound
 	ErrCodeConflict
 	ErrCodeUnprocessable
+	ErrCodePreconditionFailed
 	ErrCodeInternal
+	ErrCodeServiceUnavailable
 
 	ErrCodeVerificationFailed
 	ErrCodeUsernameTaken
 	ErrCodeEmailTaken
 	ErrCodeInvalidCredentials
 	ErrCodeUpdatePublishedAt
+	ErrCodeCommentEditWindowExpired
+	ErrCodeAttachmentTooLarge
+	ErrCodeAttachmentContentTypeNotAllowed
+	ErrCodeSlugTaken
+	ErrCodeInvalidWebhookSignature
+	ErrCodePaymentProviderUnavailable
+	ErrCodePaymentProviderRejected
+	ErrCodeDiscountCodeTaken
+	ErrCodeDiscountExpired
+	ErrCodeDiscountExhausted
+	ErrCodeDiscountIneligible
 )
 
 type ServiceError struct {
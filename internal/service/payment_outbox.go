@@ -0,0 +1,321 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/generator"
+	"github.com/jljl1337/issho/internal/payment"
+	"github.com/jljl1337/issho/internal/repository"
+)
+
+type enqueuePaymentOutboxParams struct {
+	Type               string
+	UserID             *string
+	PriceID            *string
+	CustomerExternalID *string
+}
+
+// enqueuePaymentOutbox records a change to make at the payment provider, in
+// the transaction of the queries making the same change locally, and queues
+// it to be made. The user or price of the change is marked as pending.
+func enqueuePaymentOutbox(ctx context.Context, queries *repository.Queries, arg enqueuePaymentOutboxParams) error {
+	now := generator.NowISO8601()
+	id := generator.NewULID()
+
+	err := queries.CreatePaymentOutbox(ctx, repository.PaymentOutbox{
+		ID:                 id,
+		Type:               arg.Type,
+		UserID:             arg.UserID,
+		PriceID:            arg.PriceID,
+		CustomerExternalID: arg.CustomerExternalID,
+		IdempotencyKey:     generator.NewULID(),
+		Status:             env.PaymentOutboxStatusPending,
+		Error:              nil,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to create payment outbox: %v", err)
+	}
+
+	err = queries.CreateQueueTask(ctx, repository.QueueTask{
+		ID:        generator.NewULID(),
+		Lane:      env.QueueTaskLanePaymentOutbox,
+		Payload:   id,
+		Status:    env.QueueTaskStatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to create queue task: %v", err)
+	}
+
+	return updatePaymentSyncStatus(ctx, queries, arg.UserID, arg.PriceID, env.PaymentSyncStatusPending)
+}
+
+// ProcessPaymentOutbox makes the change of the payment outbox at the payment
+// provider, with the user or price as it is now, so that a change queued after
+// it is made as well. A change that is no longer pending is skipped, and one
+// the provider can never make or has rejected is marked as failed without
+// being retried.
+func (s *EndpointService) ProcessPaymentOutbox(ctx context.Context, outboxID string) error {
+	queries := repository.New(s.db)
+
+	outboxList, err := queries.GetPaymentOutboxByID(ctx, outboxID)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get payment outbox by ID: %v", err)
+	}
+
+	// The outbox of a deleted user is deleted with the user
+	if len(outboxList) == 0 || outboxList[0].Status != env.PaymentOutboxStatusPending {
+		return nil
+	}
+
+	outbox := outboxList[0]
+
+	// Call the payment provider outside a transaction, as the local provider
+	// writes to the same database
+	ctx = payment.WithIdempotencyKey(ctx, outbox.IdempotencyKey)
+
+	customerExternalID := ""
+	switch outbox.Type {
+	case env.PaymentOutboxTypeCreateCustomer, env.PaymentOutboxTypeUpdateCustomer:
+		customerExternalID, err = s.syncCustomer(ctx, queries, outbox)
+	case env.PaymentOutboxTypeDeleteCustomer:
+		err = s.paymentProvider.DeleteCustomer(ctx, *outbox.CustomerExternalID)
+		if err != nil {
+			err = newPaymentProviderError(err, "failed to delete customer in payment provider")
+		}
+	case env.PaymentOutboxTypeUpdatePrice:
+		err = s.syncPrice(ctx, queries, outbox)
+	default:
+		err = NewServiceErrorf(ErrCodeInternal, "unknown payment outbox type: %s", outbox.Type)
+	}
+	if errors.Is(err, payment.ErrPriceNotUpdatable) {
+		return s.FailPaymentOutbox(ctx, outbox.ID, "price amount, currency and recurrence cannot be changed with the payment provider")
+	}
+
+	// A change rejected by the provider fails the same way when retried
+	var serviceErr *ServiceError
+	if errors.As(err, &serviceErr) && serviceErr.Code == ErrCodePaymentProviderRejected {
+		return s.FailPaymentOutbox(ctx, outbox.ID, serviceErr.Message)
+	}
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to begin transaction: %v", err)
+	}
+
+	defer tx.Rollback()
+
+	queries = repository.New(tx)
+
+	if customerExternalID != "" {
+		err = queries.UpdateUserExternalID(ctx, repository.UpdateUserExternalIDParams{
+			ID:         *outbox.UserID,
+			ExternalID: customerExternalID,
+			UpdatedAt:  generator.NowISO8601(),
+		})
+		if err != nil {
+			return NewServiceErrorf(ErrCodeInternal, "failed to update user external ID: %v", err)
+		}
+	}
+
+	err = completePaymentOutbox(ctx, queries, outbox, env.PaymentOutboxStatusSucceeded, nil)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+// FailPaymentOutbox marks the payment outbox as failed, with the user or price
+// of it.
+func (s *EndpointService) FailPaymentOutbox(ctx context.Context, outboxID, message string) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to begin transaction: %v", err)
+	}
+
+	defer tx.Rollback()
+
+	queries := repository.New(tx)
+
+	outboxList, err := queries.GetPaymentOutboxByID(ctx, outboxID)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get payment outbox by ID: %v", err)
+	}
+
+	if len(outboxList) == 0 {
+		return nil
+	}
+
+	err = completePaymentOutbox(ctx, queries, outboxList[0], env.PaymentOutboxStatusFailed, &message)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+// syncCustomer creates or updates the customer of the user, returning the
+// external ID of a customer created. A user that is not linked to a customer
+// yet is left to the outbox creating it.
+func (s *EndpointService) syncCustomer(ctx context.Context, queries *repository.Queries, outbox repository.PaymentOutbox) (string, error) {
+	userList, err := queries.GetUserListByIDs(ctx, []string{*outbox.UserID})
+	if err != nil {
+		return "", NewServiceErrorf(ErrCodeInternal, "failed to get user by ID: %v", err)
+	}
+
+	if len(userList) == 0 {
+		return "", nil
+	}
+
+	user := userList[0]
+
+	if user.ExternalID != nil {
+		err = s.paymentProvider.UpdateCustomer(ctx, payment.UpdateCustomerParams{
+			ExternalID:   *user.ExternalID,
+			Name:         user.Username,
+			Email:        user.Email,
+			LanguageCode: user.LanguageCode,
+		})
+		if err != nil {
+			return "", newPaymentProviderError(err, "failed to update customer in payment provider")
+		}
+
+		return "", nil
+	}
+
+	if outbox.Type != env.PaymentOutboxTypeCreateCustomer {
+		return "", nil
+	}
+
+	customerExternalID, err := s.paymentProvider.CreateCustomer(ctx, payment.CreateCustomerParams{
+		UserID:       user.ID,
+		Name:         user.Username,
+		Email:        user.Email,
+		LanguageCode: user.LanguageCode,
+	})
+	if err != nil {
+		return "", newPaymentProviderError(err, "failed to create customer in payment provider")
+	}
+
+	return customerExternalID, nil
+}
+
+// syncPrice updates the price at the payment provider.
+func (s *EndpointService) syncPrice(ctx context.Context, queries *repository.Queries, outbox repository.PaymentOutbox) error {
+	priceList, err := queries.GetPriceByID(ctx, *outbox.PriceID)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get price by ID: %v", err)
+	}
+
+	if len(priceList) == 0 {
+		return nil
+	}
+
+	price := priceList[0]
+
+	_, err = s.paymentProvider.UpdatePrice(ctx, payment.UpdatePriceParams{
+		ExternalID:             price.ExternalID,
+		Name:                   price.Name,
+		Description:            price.Description,
+		PriceAmount:            price.PriceAmount,
+		PriceCurrency:          price.PriceCurrency,
+		IsRecurring:            price.IsRecurring,
+		RecurringInterval:      price.RecurringInterval,
+		RecurringIntervalCount: price.RecurringIntervalCount,
+		IsActive:               price.IsActive,
+	})
+	if errors.Is(err, payment.ErrPriceNotUpdatable) {
+		return err
+	}
+	if err != nil {
+		return newPaymentProviderError(err, "failed to update price in payment provider")
+	}
+
+	return nil
+}
+
+// completePaymentOutbox marks the payment outbox with the status, and updates
+// the sync status of its user or price, which stays pending while other
+// changes are pending.
+func completePaymentOutbox(ctx context.Context, queries *repository.Queries, outbox repository.PaymentOutbox, status string, message *string) error {
+	err := queries.UpdatePaymentOutboxStatusByID(ctx, repository.UpdatePaymentOutboxStatusByIDParams{
+		ID:        outbox.ID,
+		Status:    status,
+		Error:     message,
+		UpdatedAt: generator.NowISO8601(),
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to update payment outbox status: %v", err)
+	}
+
+	syncStatus := env.PaymentSyncStatusSynced
+	if status == env.PaymentOutboxStatusFailed {
+		syncStatus = env.PaymentSyncStatusFailed
+	}
+
+	pendingCount := 0
+	if outbox.UserID != nil {
+		pendingCount, err = queries.GetPaymentOutboxCountByUserIDAndStatus(ctx, repository.GetPaymentOutboxCountByUserIDAndStatusParams{
+			UserID: *outbox.UserID,
+			Status: env.PaymentOutboxStatusPending,
+		})
+	}
+	if outbox.PriceID != nil {
+		pendingCount, err = queries.GetPaymentOutboxCountByPriceIDAndStatus(ctx, repository.GetPaymentOutboxCountByPriceIDAndStatusParams{
+			PriceID: *outbox.PriceID,
+			Status:  env.PaymentOutboxStatusPending,
+		})
+	}
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get pending payment outbox count: %v", err)
+	}
+
+	if syncStatus == env.PaymentSyncStatusSynced && pendingCount > 0 {
+		syncStatus = env.PaymentSyncStatusPending
+	}
+
+	return updatePaymentSyncStatus(ctx, queries, outbox.UserID, outbox.PriceID, syncStatus)
+}
+
+func updatePaymentSyncStatus(ctx context.Context, queries *repository.Queries, userID, priceID *string, status string) error {
+	if userID != nil {
+		err := queries.UpdateUserPaymentSyncStatus(ctx, repository.UpdateUserPaymentSyncStatusParams{
+			ID:                *userID,
+			PaymentSyncStatus: status,
+		})
+		if err != nil {
+			return NewServiceErrorf(ErrCodeInternal, "failed to update user payment sync status: %v", err)
+		}
+	}
+
+	if priceID != nil {
+		err := queries.UpdatePricePaymentSyncStatus(ctx, repository.UpdatePricePaymentSyncStatusParams{
+			ID:                *priceID,
+			PaymentSyncStatus: status,
+		})
+		if err != nil {
+			return NewServiceErrorf(ErrCodeInternal, "failed to update price payment sync status: %v", err)
+		}
+	}
+
+	return nil
+}
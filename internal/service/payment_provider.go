@@ -0,0 +1,32 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/jljl1337/issho/internal/payment"
+)
+
+// newPaymentProviderError returns the service error of a failed request to the
+// payment provider, with a code for whether the provider is unavailable, has
+// rejected the request, or has failed otherwise.
+func newPaymentProviderError(err error, message string) *ServiceError {
+	code := ErrCodeInternal
+
+	var providerErr *payment.ProviderError
+	switch {
+	case errors.Is(err, payment.ErrProviderUnavailable):
+		code = ErrCodePaymentProviderUnavailable
+	case errors.As(err, &providerErr):
+		switch providerErr.StatusCode {
+		case http.StatusBadRequest, http.StatusPaymentRequired, http.StatusUnprocessableEntity:
+			code = ErrCodePaymentProviderRejected
+		case http.StatusNotFound:
+			code = ErrCodeNotFound
+		case http.StatusConflict:
+			code = ErrCodeConflict
+		}
+	}
+
+	return NewServiceErrorf(code, "%s: %v", message, err)
+}
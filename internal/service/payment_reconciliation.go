@@ -0,0 +1,592 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/generator"
+	"github.com/jljl1337/issho/internal/payment"
+	"github.com/jljl1337/issho/internal/repository"
+)
+
+// paymentReconciliation collects the issues found by comparing the payment
+// provider with the database.
+type paymentReconciliation struct {
+	issues []repository.PaymentReconciliationIssue
+}
+
+func (r *paymentReconciliation) add(objectType, code string, localID, externalID *string, isFixed bool, format string, args ...any) {
+	r.issues = append(r.issues, repository.PaymentReconciliationIssue{
+		ID:         generator.NewULID(),
+		ObjectType: objectType,
+		Code:       code,
+		LocalID:    localID,
+		ExternalID: externalID,
+		Message:    fmt.Sprintf(format, args...),
+		IsFixed:    isFixed,
+		CreatedAt:  generator.NowISO8601(),
+	})
+}
+
+// ReconcilePayments compares the customers, products, prices, discounts,
+// subscriptions and orders at the payment provider with the database, which diverge when a
+// provider call succeeds but the following database write fails. Drift that
+// is safe to fix is fixed, and every issue found replaces the ones of the
+// previous run. The fixes in the database are made in the same transaction as
+// the issues are written, so that no fix goes unreported. It returns the
+// number of issues found.
+func (s *EndpointService) ReconcilePayments(ctx context.Context) (int, error) {
+	// Get everything from the provider first, as the local provider reads the
+	// same database
+	customers, err := s.paymentProvider.GetCustomerList(ctx)
+	if err != nil {
+		return 0, newPaymentProviderError(err, "failed to get customers from payment provider")
+	}
+
+	products, err := s.paymentProvider.GetProductList(ctx)
+	if err != nil {
+		return 0, newPaymentProviderError(err, "failed to get products from payment provider")
+	}
+
+	prices, err := s.paymentProvider.GetPriceList(ctx)
+	if err != nil {
+		return 0, newPaymentProviderError(err, "failed to get prices from payment provider")
+	}
+
+	discounts, err := s.paymentProvider.GetDiscountList(ctx)
+	if err != nil {
+		return 0, newPaymentProviderError(err, "failed to get discounts from payment provider")
+	}
+
+	subscriptions, orders, err := s.getPurchaseList(ctx, customers)
+	if err != nil {
+		return 0, err
+	}
+
+	reconciliation := &paymentReconciliation{}
+
+	// Fixes at the provider cannot be made in the transaction, so the issues
+	// found so far are written if one of them fails
+	err = s.attachPrices(ctx, reconciliation, prices)
+	if err != nil {
+		if saveErr := s.savePaymentReconciliation(ctx, reconciliation, func(queries *repository.Queries) error { return nil }); saveErr != nil {
+			return 0, saveErr
+		}
+
+		return 0, err
+	}
+
+	err = s.savePaymentReconciliation(ctx, reconciliation, func(queries *repository.Queries) error {
+		err := reconcileCustomers(ctx, queries, reconciliation, customers)
+		if err != nil {
+			return err
+		}
+
+		err = reconcileProducts(ctx, queries, reconciliation, products)
+		if err != nil {
+			return err
+		}
+
+		err = reconcilePrices(ctx, queries, reconciliation, prices)
+		if err != nil {
+			return err
+		}
+
+		err = reconcileDiscounts(ctx, queries, reconciliation, discounts)
+		if err != nil {
+			return err
+		}
+
+		return reconcilePurchases(ctx, queries, reconciliation, subscriptions, orders)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(reconciliation.issues), nil
+}
+
+// savePaymentReconciliation makes the fixes in the database and replaces the
+// issues of the previous run with the ones found, in one transaction.
+func (s *EndpointService) savePaymentReconciliation(ctx context.Context, reconciliation *paymentReconciliation, fix func(queries *repository.Queries) error) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to begin transaction: %v", err)
+	}
+
+	defer tx.Rollback()
+
+	queries := repository.New(tx)
+
+	err = fix(queries)
+	if err != nil {
+		return err
+	}
+
+	_, err = queries.DeletePaymentReconciliationIssue(ctx)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to delete payment reconciliation issues: %v", err)
+	}
+
+	for _, issue := range reconciliation.issues {
+		err = queries.CreatePaymentReconciliationIssue(ctx, issue)
+		if err != nil {
+			return NewServiceErrorf(ErrCodeInternal, "failed to create payment reconciliation issue: %v", err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+// reconcileCustomers links the customers created for users whose verification
+// failed afterwards, which are reused when the users verify again.
+func reconcileCustomers(ctx context.Context, queries *repository.Queries, reconciliation *paymentReconciliation, customers []payment.Customer) error {
+	userList, err := queries.GetUserListWithExternalID(ctx)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get user list with external ID: %v", err)
+	}
+
+	customerMap := map[string]payment.Customer{}
+	for _, customer := range customers {
+		customerMap[customer.ExternalID] = customer
+	}
+
+	linkedCustomerIDs := map[string]bool{}
+	for _, user := range userList {
+		linkedCustomerIDs[*user.ExternalID] = true
+
+		if _, ok := customerMap[*user.ExternalID]; !ok {
+			reconciliation.add(env.PaymentReconciliationObjectTypeCustomer, env.PaymentReconciliationCodeMissingAtProvider, &user.ID, user.ExternalID, false,
+				"customer of user %s not found at the payment provider", user.Username)
+		}
+	}
+
+	userIDs := []string{}
+	for _, customer := range customers {
+		if !linkedCustomerIDs[customer.ExternalID] && customer.UserID != nil {
+			userIDs = append(userIDs, *customer.UserID)
+		}
+	}
+
+	userMap := map[string]repository.User{}
+	if len(userIDs) > 0 {
+		userList, err = queries.GetUserListByIDs(ctx, userIDs)
+		if err != nil {
+			return NewServiceErrorf(ErrCodeInternal, "failed to get user list by IDs: %v", err)
+		}
+
+		for _, user := range userList {
+			userMap[user.ID] = user
+		}
+	}
+
+	for _, customer := range customers {
+		if linkedCustomerIDs[customer.ExternalID] {
+			continue
+		}
+
+		if customer.UserID == nil {
+			reconciliation.add(env.PaymentReconciliationObjectTypeCustomer, env.PaymentReconciliationCodeMissingLocally, nil, &customer.ExternalID, false,
+				"customer %s was not created for a user", customer.Email)
+			continue
+		}
+
+		user, ok := userMap[*customer.UserID]
+		if !ok {
+			reconciliation.add(env.PaymentReconciliationObjectTypeCustomer, env.PaymentReconciliationCodeMissingLocally, customer.UserID, &customer.ExternalID, false,
+				"customer %s was created for a user that no longer exists", customer.Email)
+			continue
+		}
+
+		if user.ExternalID != nil {
+			reconciliation.add(env.PaymentReconciliationObjectTypeCustomer, env.PaymentReconciliationCodeNotLinked, &user.ID, &customer.ExternalID, false,
+				"user %s is linked to another customer", user.Username)
+			continue
+		}
+
+		err = queries.UpdateUserExternalID(ctx, repository.UpdateUserExternalIDParams{
+			ID:         user.ID,
+			ExternalID: customer.ExternalID,
+			UpdatedAt:  generator.NowISO8601(),
+		})
+		if err != nil {
+			return NewServiceErrorf(ErrCodeInternal, "failed to update user external ID: %v", err)
+		}
+
+		// Later customers of the same user are reported as linked to another
+		user.ExternalID = &customer.ExternalID
+		userMap[user.ID] = user
+
+		reconciliation.add(env.PaymentReconciliationObjectTypeCustomer, env.PaymentReconciliationCodeNotLinked, &user.ID, &customer.ExternalID, true,
+			"customer linked to user %s", user.Username)
+	}
+
+	return nil
+}
+
+// reconcileProducts only reports drift, as products at the provider may be
+// unrelated to this application.
+func reconcileProducts(ctx context.Context, queries *repository.Queries, reconciliation *paymentReconciliation, products []repository.Product) error {
+	productList, err := queries.GetProductListWithExternalID(ctx)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get product list with external ID: %v", err)
+	}
+
+	unlinkedProductList, err := queries.GetProductListWithoutExternalID(ctx)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get product list without external ID: %v", err)
+	}
+
+	for _, product := range unlinkedProductList {
+		reconciliation.add(env.PaymentReconciliationObjectTypeProduct, env.PaymentReconciliationCodeNotLinked, &product.ID, nil, false,
+			"product %s is not synced with the payment provider, run the sync-products command", product.Name)
+	}
+
+	productMap := map[string]repository.Product{}
+	for _, product := range products {
+		productMap[*product.ExternalID] = product
+	}
+
+	linkedProductIDs := map[string]bool{}
+	for _, product := range productList {
+		linkedProductIDs[*product.ExternalID] = true
+
+		if _, ok := productMap[*product.ExternalID]; !ok {
+			reconciliation.add(env.PaymentReconciliationObjectTypeProduct, env.PaymentReconciliationCodeMissingAtProvider, &product.ID, product.ExternalID, false,
+				"product %s not found at the payment provider", product.Name)
+		}
+	}
+
+	for _, product := range products {
+		if !linkedProductIDs[*product.ExternalID] {
+			reconciliation.add(env.PaymentReconciliationObjectTypeProduct, env.PaymentReconciliationCodeMissingLocally, nil, product.ExternalID, false,
+				"product %s at the payment provider is not linked to a product", product.Name)
+		}
+	}
+
+	return nil
+}
+
+// attachPrices attaches prices to their products at the provider, and marks
+// them as attached in the given prices.
+func (s *EndpointService) attachPrices(ctx context.Context, reconciliation *paymentReconciliation, prices []payment.CatalogPrice) error {
+	queries := repository.New(s.db)
+
+	priceList, err := queries.GetAllPriceList(ctx)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get price list: %v", err)
+	}
+
+	productList, err := queries.GetProductListWithExternalID(ctx)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get product list with external ID: %v", err)
+	}
+
+	productExternalIDs := map[string]string{}
+	for _, product := range productList {
+		productExternalIDs[product.ID] = *product.ExternalID
+	}
+
+	catalogPriceIndexes := map[string]int{}
+	for i, catalogPrice := range prices {
+		catalogPriceIndexes[catalogPrice.ExternalID] = i
+	}
+
+	for _, price := range priceList {
+		i, ok := catalogPriceIndexes[price.ExternalID]
+		if !ok || prices[i].ProductExternalID != nil {
+			continue
+		}
+
+		productExternalID, ok := productExternalIDs[price.ProductID]
+		if !ok {
+			continue
+		}
+
+		err = s.paymentProvider.AttachPrice(ctx, productExternalID, price.ExternalID)
+		if err != nil {
+			return newPaymentProviderError(err, "failed to attach price in payment provider")
+		}
+
+		prices[i].ProductExternalID = &productExternalID
+
+		reconciliation.add(env.PaymentReconciliationObjectTypePrice, env.PaymentReconciliationCodeNotLinked, &price.ID, &price.ExternalID, true,
+			"price %s attached to its product at the payment provider", price.Name)
+	}
+
+	return nil
+}
+
+// reconcilePrices creates the prices of which the creation failed after the
+// provider call. Prices are attached to their products by attachPrices before.
+func reconcilePrices(ctx context.Context, queries *repository.Queries, reconciliation *paymentReconciliation, prices []payment.CatalogPrice) error {
+	priceList, err := queries.GetAllPriceList(ctx)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get price list: %v", err)
+	}
+
+	productList, err := queries.GetProductListWithExternalID(ctx)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get product list with external ID: %v", err)
+	}
+
+	productMap := map[string]repository.Product{}
+	productExternalIDs := map[string]string{}
+	archivedProductIDs := map[string]bool{}
+	for _, product := range productList {
+		productMap[*product.ExternalID] = product
+		productExternalIDs[product.ID] = *product.ExternalID
+		archivedProductIDs[product.ID] = !product.IsActive
+	}
+
+	catalogPriceMap := map[string]payment.CatalogPrice{}
+	for _, catalogPrice := range prices {
+		catalogPriceMap[catalogPrice.ExternalID] = catalogPrice
+	}
+
+	localPriceIDs := map[string]bool{}
+	for _, price := range priceList {
+		localPriceIDs[price.ExternalID] = true
+
+		catalogPrice, ok := catalogPriceMap[price.ExternalID]
+		if !ok {
+			reconciliation.add(env.PaymentReconciliationObjectTypePrice, env.PaymentReconciliationCodeMissingAtProvider, &price.ID, &price.ExternalID, false,
+				"price %s not found at the payment provider", price.Name)
+			continue
+		}
+
+		// Some providers archive the prices of archived products
+		if price.IsActive && !catalogPrice.IsActive && !archivedProductIDs[price.ProductID] {
+			reconciliation.add(env.PaymentReconciliationObjectTypePrice, env.PaymentReconciliationCodeMismatch, &price.ID, &price.ExternalID, false,
+				"price %s is archived at the payment provider", price.Name)
+		}
+
+		if !price.IsActive && catalogPrice.IsActive {
+			reconciliation.add(env.PaymentReconciliationObjectTypePrice, env.PaymentReconciliationCodeMismatch, &price.ID, &price.ExternalID, false,
+				"price %s is archived but active at the payment provider", price.Name)
+		}
+
+		productExternalID, ok := productExternalIDs[price.ProductID]
+		if !ok || catalogPrice.ProductExternalID == nil {
+			continue
+		}
+
+		if *catalogPrice.ProductExternalID != productExternalID {
+			reconciliation.add(env.PaymentReconciliationObjectTypePrice, env.PaymentReconciliationCodeMismatch, &price.ID, &price.ExternalID, false,
+				"price %s belongs to another product at the payment provider", price.Name)
+		}
+	}
+
+	for _, catalogPrice := range prices {
+		if localPriceIDs[catalogPrice.ExternalID] {
+			continue
+		}
+
+		productExternalID := ""
+		if catalogPrice.ProductExternalID != nil {
+			productExternalID = *catalogPrice.ProductExternalID
+		}
+
+		product, ok := productMap[productExternalID]
+
+		if !ok {
+			reconciliation.add(env.PaymentReconciliationObjectTypePrice, env.PaymentReconciliationCodeMissingLocally, nil, &catalogPrice.ExternalID, false,
+				"price %s at the payment provider is not linked to a price", catalogPrice.Name)
+			continue
+		}
+
+		now := generator.NowISO8601()
+
+		price := catalogPrice.Price
+		price.ID = generator.NewULID()
+		price.ProductID = product.ID
+		price.CreatedAt = now
+		price.UpdatedAt = now
+
+		err = queries.CreatePrice(ctx, price)
+		if err != nil {
+			return NewServiceErrorf(ErrCodeInternal, "failed to create price: %v", err)
+		}
+
+		reconciliation.add(env.PaymentReconciliationObjectTypePrice, env.PaymentReconciliationCodeMissingLocally, &price.ID, &price.ExternalID, true,
+			"price %s of product %s created from the payment provider", price.Name, product.Name)
+	}
+
+	return nil
+}
+
+// reconcileDiscounts deactivates the discounts deleted at the provider, of
+// which the deletion failed afterwards, as checkouts cannot apply them. Other
+// drift is only reported, as the limits and prices of a discount are not kept
+// at the provider.
+func reconcileDiscounts(ctx context.Context, queries *repository.Queries, reconciliation *paymentReconciliation, discounts []payment.CatalogDiscount) error {
+	discountList, err := queries.GetAllDiscountList(ctx)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get discount list: %v", err)
+	}
+
+	catalogDiscountMap := map[string]payment.CatalogDiscount{}
+	for _, catalogDiscount := range discounts {
+		catalogDiscountMap[catalogDiscount.ExternalID] = catalogDiscount
+	}
+
+	localDiscountIDs := map[string]bool{}
+	for _, discount := range discountList {
+		localDiscountIDs[discount.ExternalID] = true
+
+		catalogDiscount, ok := catalogDiscountMap[discount.ExternalID]
+		if ok {
+			if catalogDiscount.Name != discount.Name {
+				reconciliation.add(env.PaymentReconciliationObjectTypeDiscount, env.PaymentReconciliationCodeMismatch, &discount.ID, &discount.ExternalID, false,
+					"discount %s has another name at the payment provider", discount.Code)
+			}
+			continue
+		}
+
+		if !discount.IsActive {
+			reconciliation.add(env.PaymentReconciliationObjectTypeDiscount, env.PaymentReconciliationCodeMissingAtProvider, &discount.ID, &discount.ExternalID, false,
+				"discount %s not found at the payment provider", discount.Code)
+			continue
+		}
+
+		_, err = queries.UpdateDiscount(ctx, repository.UpdateDiscountParams{
+			Name:          discount.Name,
+			RedemptionMax: discount.RedemptionMax,
+			ExpiresAt:     discount.ExpiresAt,
+			IsActive:      false,
+			UpdatedAt:     generator.NowISO8601(),
+			ID:            discount.ID,
+			Version:       nil,
+		})
+		if err != nil {
+			return NewServiceErrorf(ErrCodeInternal, "failed to update discount: %v", err)
+		}
+
+		reconciliation.add(env.PaymentReconciliationObjectTypeDiscount, env.PaymentReconciliationCodeMissingAtProvider, &discount.ID, &discount.ExternalID, true,
+			"discount %s not found at the payment provider, deactivated", discount.Code)
+	}
+
+	for _, catalogDiscount := range discounts {
+		if localDiscountIDs[catalogDiscount.ExternalID] {
+			continue
+		}
+
+		if catalogDiscount.Code == nil {
+			reconciliation.add(env.PaymentReconciliationObjectTypeDiscount, env.PaymentReconciliationCodeMissingLocally, nil, &catalogDiscount.ExternalID, false,
+				"discount %s was not created for a discount", catalogDiscount.Name)
+			continue
+		}
+
+		reconciliation.add(env.PaymentReconciliationObjectTypeDiscount, env.PaymentReconciliationCodeMissingLocally, nil, &catalogDiscount.ExternalID, false,
+			"discount %s at the payment provider is not linked to a discount", *catalogDiscount.Code)
+	}
+
+	return nil
+}
+
+// getPurchaseList gets the subscriptions and orders of every linked customer
+// from the provider.
+func (s *EndpointService) getPurchaseList(ctx context.Context, customers []payment.Customer) ([]payment.Subscription, []payment.Order, error) {
+	userList, err := repository.New(s.db).GetUserListWithExternalID(ctx)
+	if err != nil {
+		return nil, nil, NewServiceErrorf(ErrCodeInternal, "failed to get user list with external ID: %v", err)
+	}
+
+	customerIDs := map[string]bool{}
+	for _, customer := range customers {
+		customerIDs[customer.ExternalID] = true
+	}
+
+	subscriptions := []payment.Subscription{}
+	orders := []payment.Order{}
+	for _, user := range userList {
+		// Customers missing at the provider are reported already
+		if !customerIDs[*user.ExternalID] {
+			continue
+		}
+
+		userSubscriptions, err := s.paymentProvider.GetSubscriptionList(ctx, *user.ExternalID)
+		if err != nil {
+			return nil, nil, newPaymentProviderError(err, "failed to get subscriptions from payment provider")
+		}
+
+		userOrders, err := s.paymentProvider.GetOrderList(ctx, *user.ExternalID)
+		if err != nil {
+			return nil, nil, newPaymentProviderError(err, "failed to get orders from payment provider")
+		}
+
+		subscriptions = append(subscriptions, userSubscriptions...)
+		orders = append(orders, userOrders...)
+	}
+
+	return subscriptions, orders, nil
+}
+
+// reconcilePurchases syncs the subscriptions and orders of every linked
+// customer, as webhook events may have been missed.
+func reconcilePurchases(ctx context.Context, queries *repository.Queries, reconciliation *paymentReconciliation, subscriptions []payment.Subscription, orders []payment.Order) error {
+	for _, subscription := range subscriptions {
+		subscriptionList, err := queries.GetSubscriptionByExternalID(ctx, subscription.ExternalID)
+		if err != nil {
+			return NewServiceErrorf(ErrCodeInternal, "failed to get subscription by external ID: %v", err)
+		}
+
+		isSynced, err := syncSubscription(ctx, queries, subscription)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case !isSynced:
+			reconciliation.add(env.PaymentReconciliationObjectTypeSubscription, env.PaymentReconciliationCodeMissingLocally, nil, &subscription.ExternalID, false,
+				"subscription to unknown price %s", subscription.PriceExternalID)
+		case len(subscriptionList) == 0:
+			reconciliation.add(env.PaymentReconciliationObjectTypeSubscription, env.PaymentReconciliationCodeMissingLocally, nil, &subscription.ExternalID, true,
+				"subscription created from the payment provider")
+		case subscriptionList[0].Status != subscription.Status ||
+			!equalOptionalString(subscriptionList[0].CurrentPeriodEnd, subscription.CurrentPeriodEnd):
+			reconciliation.add(env.PaymentReconciliationObjectTypeSubscription, env.PaymentReconciliationCodeMismatch, &subscriptionList[0].ID, &subscription.ExternalID, true,
+				"subscription updated from the payment provider")
+		}
+	}
+
+	for _, order := range orders {
+		orderList, err := queries.GetOrderByExternalID(ctx, order.ExternalID)
+		if err != nil {
+			return NewServiceErrorf(ErrCodeInternal, "failed to get order by external ID: %v", err)
+		}
+
+		isSynced, err := syncOrder(ctx, queries, order)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case !isSynced:
+			reconciliation.add(env.PaymentReconciliationObjectTypeOrder, env.PaymentReconciliationCodeMissingLocally, nil, &order.ExternalID, false,
+				"order of unknown price %s", order.PriceExternalID)
+		case len(orderList) == 0:
+			reconciliation.add(env.PaymentReconciliationObjectTypeOrder, env.PaymentReconciliationCodeMissingLocally, nil, &order.ExternalID, true,
+				"order created from the payment provider")
+		case orderList[0].Status != order.Status:
+			reconciliation.add(env.PaymentReconciliationObjectTypeOrder, env.PaymentReconciliationCodeMismatch, &orderList[0].ID, &order.ExternalID, true,
+				"order updated from the payment provider")
+		}
+	}
+
+	return nil
+}
+
+func equalOptionalString(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/generator"
+	"github.com/jljl1337/issho/internal/repository"
+)
+
+var mapPostVisibilityAllowed = map[string]bool{
+	env.PostVisibilityPublic:   true,
+	env.PostVisibilitySignedIn: true,
+	env.PostVisibilityPaid:     true,
+}
+
+// postAccess decides whether a viewer can read the full content of posts. The
+// viewer is nil for anonymous requests, e.g. feeds.
+type postAccess struct {
+	viewer *repository.User
+	// IDs of the posts the viewer collaborates on, loaded on first use
+	collaboratorPostIDs map[string]bool
+	// Product IDs of the active entitlements, loaded on first use
+	activeProductIDs map[string]bool
+}
+
+func newPostAccess(viewer *repository.User) *postAccess {
+	return &postAccess{
+		viewer: viewer,
+	}
+}
+
+// canReadContent reports whether the viewer can read the full content of the
+// post, given the IDs of the products required by the post. A paid post
+// without required products can be read with any active entitlement, and
+// collaborators can always read the post.
+func (a *postAccess) canReadContent(ctx context.Context, queries *repository.Queries, post repository.Post, productIDs []string) (bool, error) {
+	if a.viewer != nil && a.viewer.Role != env.UserRole {
+		return true, nil
+	}
+
+	switch post.Visibility {
+	case env.PostVisibilityPublic:
+		return true, nil
+	case env.PostVisibilitySignedIn:
+		return a.viewer != nil, nil
+	}
+
+	if a.viewer == nil {
+		return false, nil
+	}
+
+	if a.collaboratorPostIDs == nil {
+		collaborators, err := queries.GetPostCollaboratorListByUserID(ctx, a.viewer.ID)
+		if err != nil {
+			return false, NewServiceErrorf(ErrCodeInternal, "failed to get post collaborators: %v", err)
+		}
+
+		a.collaboratorPostIDs = make(map[string]bool)
+		for _, collaborator := range collaborators {
+			a.collaboratorPostIDs[collaborator.PostID] = true
+		}
+	}
+
+	if a.collaboratorPostIDs[post.ID] {
+		return true, nil
+	}
+
+	if a.activeProductIDs == nil {
+		entitlements, err := queries.GetActiveEntitlementListByUserID(ctx, repository.GetActiveEntitlementListByUserIDParams{
+			UserID: a.viewer.ID,
+			Now:    generator.NowISO8601(),
+		})
+		if err != nil {
+			return false, NewServiceErrorf(ErrCodeInternal, "failed to get active entitlements: %v", err)
+		}
+
+		a.activeProductIDs = make(map[string]bool)
+		for _, entitlement := range entitlements {
+			a.activeProductIDs[entitlement.ProductID] = true
+		}
+	}
+
+	if len(productIDs) == 0 {
+		return len(a.activeProductIDs) > 0, nil
+	}
+
+	for _, productID := range productIDs {
+		if a.activeProductIDs[productID] {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// canViewPost reports whether the viewer can see the post at all. Regular
+// users can only see published posts, unless they collaborate on the post.
+func canViewPost(ctx context.Context, queries *repository.Queries, viewer *repository.User, post repository.Post) (bool, error) {
+	if viewer != nil && viewer.Role != env.UserRole {
+		return true, nil
+	}
+
+	if isPostPublished(post, generator.NowISO8601()) {
+		return true, nil
+	}
+
+	if viewer == nil {
+		return false, nil
+	}
+
+	return hasPostRole(ctx, queries, post.ID, viewer.ID, env.PostCollaboratorRoleViewer)
+}
+
+// canReadPostContent is canReadContent for a single post, loading the
+// products required by the post.
+func canReadPostContent(ctx context.Context, queries *repository.Queries, viewer *repository.User, post repository.Post) (bool, error) {
+	postProducts, err := queries.GetPostProductListByPostIDs(ctx, []string{post.ID})
+	if err != nil {
+		return false, NewServiceErrorf(ErrCodeInternal, "failed to get post products: %v", err)
+	}
+
+	productIDs := make([]string, len(postProducts))
+	for i, postProduct := range postProducts {
+		productIDs[i] = postProduct.ProductID
+	}
+
+	return newPostAccess(viewer).canReadContent(ctx, queries, post, productIDs)
+}
+
+// postTeaser returns the beginning of the content for viewers who cannot read
+// the full post.
+func postTeaser(content string) string {
+	runes := []rune(content)
+	if len(runes) <= env.PostTeaserLength {
+		return content
+	}
+
+	return strings.TrimSpace(string(runes[:env.PostTeaserLength]))
+}
+
+// checkPostProducts validates the visibility and the required products of a
+// post, returning the product IDs without duplicates.
+func checkPostProducts(ctx context.Context, queries *repository.Queries, visibility string, productIDs []string) ([]string, error) {
+	if !mapPostVisibilityAllowed[visibility] {
+		return nil, NewServiceError(ErrCodeUnprocessable, "invalid post visibility")
+	}
+
+	uniqueProductIDs := []string{}
+	seen := make(map[string]bool)
+	for _, productID := range productIDs {
+		if seen[productID] {
+			continue
+		}
+		seen[productID] = true
+		uniqueProductIDs = append(uniqueProductIDs, productID)
+	}
+
+	if len(uniqueProductIDs) == 0 {
+		return uniqueProductIDs, nil
+	}
+
+	if visibility != env.PostVisibilityPaid {
+		return nil, NewServiceError(ErrCodeUnprocessable, "only paid posts can require products")
+	}
+
+	products, err := queries.GetProductListByIDs(ctx, uniqueProductIDs)
+	if err != nil {
+		return nil, NewServiceErrorf(ErrCodeInternal, "failed to get products: %v", err)
+	}
+
+	if len(products) != len(uniqueProductIDs) {
+		return nil, NewServiceError(ErrCodeUnprocessable, "product not found")
+	}
+
+	return uniqueProductIDs, nil
+}
+
+// setPostProducts replaces the products required by a post.
+func setPostProducts(ctx context.Context, queries *repository.Queries, postID string, productIDs []string) error {
+	_, err := queries.DeletePostProductByPostID(ctx, postID)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to delete post products: %v", err)
+	}
+
+	now := generator.NowISO8601()
+
+	for _, productID := range productIDs {
+		err = queries.CreatePostProduct(ctx, repository.PostProduct{
+			PostID:    postID,
+			ProductID: productID,
+			CreatedAt: now,
+		})
+		if err != nil {
+			return NewServiceErrorf(ErrCodeInternal, "failed to create post product: %v", err)
+		}
+	}
+
+	return nil
+}
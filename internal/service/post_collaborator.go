@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/repository"
+)
+
+// mapPostCollaboratorRoleRank orders the roles of post collaborators, where a
+// role has all the permissions of the roles ranked below it. Viewers can read
+// the post before it is published and regardless of its visibility, editors
+// can also update the post, and authors can also delete the post and manage
+// its collaborators.
+var mapPostCollaboratorRoleRank = map[string]int{
+	env.PostCollaboratorRoleViewer: 1,
+	env.PostCollaboratorRoleEditor: 2,
+	env.PostCollaboratorRoleAuthor: 3,
+}
+
+// getPostCollaboratorRole returns the role of the user on the post, or an
+// empty string if the user is not a collaborator.
+func getPostCollaboratorRole(ctx context.Context, queries *repository.Queries, postID, userID string) (string, error) {
+	collaboratorList, err := queries.GetPostCollaborator(ctx, repository.GetPostCollaboratorParams{
+		PostID: postID,
+		UserID: userID,
+	})
+	if err != nil {
+		return "", NewServiceErrorf(ErrCodeInternal, "failed to get post collaborator: %v", err)
+	}
+
+	if len(collaboratorList) == 0 {
+		return "", nil
+	}
+
+	return collaboratorList[0].Role, nil
+}
+
+// hasPostRole reports whether the user collaborates on the post with at least
+// the role.
+func hasPostRole(ctx context.Context, queries *repository.Queries, postID, userID, role string) (bool, error) {
+	collaboratorRole, err := getPostCollaboratorRole(ctx, queries, postID, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return mapPostCollaboratorRoleRank[collaboratorRole] >= mapPostCollaboratorRoleRank[role], nil
+}
+
+// checkPostRole returns a forbidden error for the action unless the user
+// collaborates on the post with at least the role.
+func checkPostRole(ctx context.Context, queries *repository.Queries, user repository.User, postID, role, action string) error {
+	ok, err := hasPostRole(ctx, queries, postID, user.ID, role)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return NewServiceErrorf(ErrCodeForbidden, "insufficient permissions to %s", action)
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jljl1337/issho/internal/repository"
+)
+
+// supportedLanguageCodes returns the allowed language codes in a stable order.
+func supportedLanguageCodes() []string {
+	languageCodes := make([]string, 0, len(mapLanguageCodeAllowed))
+	for languageCode := range mapLanguageCodeAllowed {
+		languageCodes = append(languageCodes, languageCode)
+	}
+	sort.Strings(languageCodes)
+	return languageCodes
+}
+
+// preferredLanguageCodes returns the supported language codes that the viewer
+// prefers, most preferred first. The language of a signed in user comes first,
+// followed by the languages of the Accept-Language header.
+func preferredLanguageCodes(viewer *repository.User, acceptLanguage string) []string {
+	languageCodes := []string{}
+
+	if viewer != nil && checkLanguageCode(viewer.LanguageCode) {
+		languageCodes = append(languageCodes, viewer.LanguageCode)
+	}
+
+	for _, languageCode := range parseAcceptLanguage(acceptLanguage) {
+		if !slices.Contains(languageCodes, languageCode) {
+			languageCodes = append(languageCodes, languageCode)
+		}
+	}
+
+	return languageCodes
+}
+
+// parseAcceptLanguage maps the language ranges of an Accept-Language header to
+// the supported language codes, ordered by quality. A range matches a language
+// code exactly, or by its primary subtag, e.g. "zh" and "zh-TW" match "zh-HK".
+func parseAcceptLanguage(header string) []string {
+	type languageRange struct {
+		tag     string
+		quality float64
+	}
+
+	ranges := []languageRange{}
+	for part := range strings.SplitSeq(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+
+		if quality <= 0 {
+			continue
+		}
+
+		ranges = append(ranges, languageRange{tag: tag, quality: quality})
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].quality > ranges[j].quality
+	})
+
+	supported := supportedLanguageCodes()
+	languageCodes := []string{}
+
+	for _, r := range ranges {
+		primary, _, _ := strings.Cut(r.tag, "-")
+
+		for _, languageCode := range supported {
+			supportedPrimary, _, _ := strings.Cut(languageCode, "-")
+
+			isMatch := strings.EqualFold(r.tag, languageCode) || strings.EqualFold(primary, supportedPrimary)
+			if isMatch && !slices.Contains(languageCodes, languageCode) {
+				languageCodes = append(languageCodes, languageCode)
+			}
+		}
+	}
+
+	return languageCodes
+}
+
+// translatePost replaces the text of the post with the translation in the
+// most preferred language, falling back to the language the post is written
+// in. It returns the language code of the text.
+func translatePost(post *repository.Post, translations []repository.PostTranslation, languageCodes []string) string {
+	for _, languageCode := range languageCodes {
+		if languageCode == post.LanguageCode {
+			return post.LanguageCode
+		}
+
+		for _, translation := range translations {
+			if translation.LanguageCode == languageCode {
+				post.Title = translation.Title
+				post.Description = translation.Description
+				post.Content = translation.Content
+				return languageCode
+			}
+		}
+	}
+
+	return post.LanguageCode
+}
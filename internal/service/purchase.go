@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+
+	"github.com/jljl1337/issho/internal/env"
+	"github.com/jljl1337/issho/internal/generator"
+	"github.com/jljl1337/issho/internal/payment"
+	"github.com/jljl1337/issho/internal/repository"
+)
+
+// getPurchaseUserAndPrice returns the user and the price of a purchase at the
+// payment provider, or nil if either is not known to this application, e.g.
+// for products created outside of it.
+func getPurchaseUserAndPrice(ctx context.Context, queries *repository.Queries, customerExternalID, priceExternalID string) (*repository.User, *repository.Price, error) {
+	userList, err := queries.GetUserByExternalID(ctx, customerExternalID)
+	if err != nil {
+		return nil, nil, NewServiceErrorf(ErrCodeInternal, "failed to get user by external ID: %v", err)
+	}
+
+	priceList, err := queries.GetPriceByExternalID(ctx, priceExternalID)
+	if err != nil {
+		return nil, nil, NewServiceErrorf(ErrCodeInternal, "failed to get price by external ID: %v", err)
+	}
+
+	if len(userList) == 0 || len(priceList) == 0 {
+		return nil, nil, nil
+	}
+
+	return &userList[0], &priceList[0], nil
+}
+
+// syncSubscription stores the state of the subscription from the payment
+// provider. An active subscription grants an entitlement to the product of its
+// price until the end of the current period, which is extended on renewal, and
+// redeems the discount applied by its checkout. It returns false if the
+// customer or the price is unknown.
+func syncSubscription(ctx context.Context, queries *repository.Queries, subscription payment.Subscription) (bool, error) {
+	user, price, err := getPurchaseUserAndPrice(ctx, queries, subscription.CustomerExternalID, subscription.PriceExternalID)
+	if err != nil {
+		return false, err
+	}
+
+	if user == nil {
+		return false, nil
+	}
+
+	now := generator.NowISO8601()
+
+	err = queries.UpsertSubscription(ctx, repository.Subscription{
+		ID:                 generator.NewULID(),
+		ExternalID:         subscription.ExternalID,
+		UserID:             user.ID,
+		PriceID:            price.ID,
+		Status:             subscription.Status,
+		StartedAt:          subscription.StartedAt,
+		CurrentPeriodStart: subscription.CurrentPeriodStart,
+		CurrentPeriodEnd:   subscription.CurrentPeriodEnd,
+		CancelAtPeriodEnd:  subscription.CancelAtPeriodEnd,
+		CanceledAt:         subscription.CanceledAt,
+		EndedAt:            subscription.EndedAt,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	})
+	if err != nil {
+		return false, NewServiceErrorf(ErrCodeInternal, "failed to upsert subscription: %v", err)
+	}
+
+	subscriptionList, err := queries.GetSubscriptionByExternalID(ctx, subscription.ExternalID)
+	if err != nil {
+		return false, NewServiceErrorf(ErrCodeInternal, "failed to get subscription by external ID: %v", err)
+	}
+
+	if len(subscriptionList) == 0 {
+		return false, NewServiceError(ErrCodeInternal, "subscription not found after upsert")
+	}
+
+	isActive := subscription.Status == env.SubscriptionStatusActive || subscription.Status == env.SubscriptionStatusTrialing
+	if isActive {
+		err = queries.UpsertEntitlementBySource(ctx, repository.Entitlement{
+			ID:        generator.NewULID(),
+			UserID:    user.ID,
+			ProductID: price.ProductID,
+			Source:    env.EntitlementSourceSubscription,
+			SourceID:  &subscriptionList[0].ID,
+			StartsAt:  subscription.StartedAt,
+			EndsAt:    subscription.CurrentPeriodEnd,
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+		if err != nil {
+			return false, NewServiceErrorf(ErrCodeInternal, "failed to upsert entitlement: %v", err)
+		}
+
+		err = redeemPurchaseDiscount(ctx, queries, user.ID, subscription.DiscountExternalID, env.DiscountRedemptionSourceSubscription, subscriptionList[0].ID)
+		if err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+
+	endsAt := now
+	if subscription.EndedAt != nil && *subscription.EndedAt < now {
+		endsAt = *subscription.EndedAt
+	}
+
+	err = queries.EndEntitlementBySource(ctx, repository.EndEntitlementBySourceParams{
+		Source:    env.EntitlementSourceSubscription,
+		SourceID:  subscriptionList[0].ID,
+		EndsAt:    endsAt,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return false, NewServiceErrorf(ErrCodeInternal, "failed to end entitlement: %v", err)
+	}
+
+	return true, nil
+}
+
+// syncOrder stores the state of the order from the payment provider. A paid
+// order of a one-time price grants a lifetime entitlement to the product of
+// the price, which ends when the order is refunded, and redeems the discount
+// applied by its checkout. Orders of subscriptions grant nothing by
+// themselves. It returns false if the customer or the price is unknown.
+func syncOrder(ctx context.Context, queries *repository.Queries, order payment.Order) (bool, error) {
+	user, price, err := getPurchaseUserAndPrice(ctx, queries, order.CustomerExternalID, order.PriceExternalID)
+	if err != nil {
+		return false, err
+	}
+
+	if user == nil {
+		return false, nil
+	}
+
+	now := generator.NowISO8601()
+
+	err = queries.UpsertOrder(ctx, repository.Order{
+		ID:                     generator.NewULID(),
+		ExternalID:             order.ExternalID,
+		UserID:                 user.ID,
+		PriceID:                price.ID,
+		SubscriptionExternalID: order.SubscriptionExternalID,
+		Status:                 order.Status,
+		Amount:                 order.Amount,
+		Currency:               order.Currency,
+		OrderedAt:              order.OrderedAt,
+		CreatedAt:              now,
+		UpdatedAt:              now,
+	})
+	if err != nil {
+		return false, NewServiceErrorf(ErrCodeInternal, "failed to upsert order: %v", err)
+	}
+
+	if order.SubscriptionExternalID != nil || price.IsRecurring {
+		return true, nil
+	}
+
+	orderList, err := queries.GetOrderByExternalID(ctx, order.ExternalID)
+	if err != nil {
+		return false, NewServiceErrorf(ErrCodeInternal, "failed to get order by external ID: %v", err)
+	}
+
+	if len(orderList) == 0 {
+		return false, NewServiceError(ErrCodeInternal, "order not found after upsert")
+	}
+
+	switch order.Status {
+	case env.OrderStatusPaid, env.OrderStatusPartiallyRefunded:
+		err = queries.UpsertEntitlementBySource(ctx, repository.Entitlement{
+			ID:        generator.NewULID(),
+			UserID:    user.ID,
+			ProductID: price.ProductID,
+			Source:    env.EntitlementSourceOrder,
+			SourceID:  &orderList[0].ID,
+			StartsAt:  order.OrderedAt,
+			EndsAt:    nil,
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+		if err != nil {
+			return false, NewServiceErrorf(ErrCodeInternal, "failed to upsert entitlement: %v", err)
+		}
+
+		err = redeemPurchaseDiscount(ctx, queries, user.ID, order.DiscountExternalID, env.DiscountRedemptionSourceOrder, orderList[0].ID)
+		if err != nil {
+			return false, err
+		}
+	case env.OrderStatusRefunded:
+		err = queries.EndEntitlementBySource(ctx, repository.EndEntitlementBySourceParams{
+			Source:    env.EntitlementSourceOrder,
+			SourceID:  orderList[0].ID,
+			EndsAt:    now,
+			UpdatedAt: now,
+		})
+		if err != nil {
+			return false, NewServiceErrorf(ErrCodeInternal, "failed to end entitlement: %v", err)
+		}
+	}
+
+	return true, nil
+}
+
+// redeemPurchaseDiscount counts a redemption of the discount of a paid
+// purchase, once per purchase however many times it is synced, which redeems
+// the oldest reservation of the discount by the user. It is counted even
+// without a reservation, e.g. if the reservation has expired, as the purchase
+// is already paid. Discounts deleted or unknown to this application are
+// skipped.
+func redeemPurchaseDiscount(ctx context.Context, queries *repository.Queries, userID string, discountExternalID *string, source, sourceID string) error {
+	if discountExternalID == nil {
+		return nil
+	}
+
+	discountList, err := queries.GetDiscountByExternalID(ctx, *discountExternalID)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get discount by external ID: %v", err)
+	}
+
+	if len(discountList) == 0 {
+		return nil
+	}
+
+	created, err := queries.CreateDiscountRedemption(ctx, repository.DiscountRedemption{
+		DiscountID: discountList[0].ID,
+		Source:     source,
+		SourceID:   sourceID,
+		CreatedAt:  generator.NowISO8601(),
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to create discount redemption: %v", err)
+	}
+
+	if !created {
+		return nil
+	}
+
+	reservations, err := queries.GetDiscountReservationListByDiscountIDAndUserID(ctx, repository.GetDiscountReservationListByDiscountIDAndUserIDParams{
+		DiscountID: discountList[0].ID,
+		UserID:     userID,
+	})
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to get discount reservations: %v", err)
+	}
+
+	if len(reservations) > 0 {
+		rowsAffected, err := queries.DeleteDiscountReservationByID(ctx, reservations[0].ID)
+		if err != nil {
+			return NewServiceErrorf(ErrCodeInternal, "failed to delete discount reservation: %v", err)
+		}
+
+		// The reservation may have been released meanwhile
+		if rowsAffected > 0 {
+			err = queries.RedeemDiscountReservation(ctx, discountList[0].ID)
+			if err != nil {
+				return NewServiceErrorf(ErrCodeInternal, "failed to redeem discount reservation: %v", err)
+			}
+
+			return nil
+		}
+	}
+
+	err = queries.RedeemDiscount(ctx, discountList[0].ID)
+	if err != nil {
+		return NewServiceErrorf(ErrCodeInternal, "failed to redeem discount: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,35 @@
+package service
+
+import "slices"
+
+// checkVersion returns an error if the resource has been updated since the
+// client last read it. The version of a resource is the time it was last
+// updated, and nil versions match any version.
+func checkVersion(resourceName, updatedAt string, versions []string) error {
+	if versions == nil || slices.Contains(versions, updatedAt) {
+		return nil
+	}
+
+	return NewServiceErrorf(ErrCodePreconditionFailed, "%s has been modified", resourceName)
+}
+
+// versionCondition returns the version that the update of a resource is made
+// conditional on, so that an update made after checkVersion by a concurrent
+// writer is not overwritten. It is nil when no versions were given.
+func versionCondition(updatedAt string, versions []string) *string {
+	if versions == nil {
+		return nil
+	}
+
+	return &updatedAt
+}
+
+// checkVersionUpdated returns an error if a conditional update affected no
+// rows, i.e. the resource was modified after its version had been checked.
+func checkVersionUpdated(resourceName string, rowsAffected int64) error {
+	if rowsAffected == 0 {
+		return NewServiceErrorf(ErrCodePreconditionFailed, "%s has been modified", resourceName)
+	}
+
+	return nil
+}
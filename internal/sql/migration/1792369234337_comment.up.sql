@@ -0,0 +1,20 @@
+CREATE TABLE comment (
+    id TEXT NOT NULL,
+    post_id TEXT NOT NULL,
+    user_id TEXT,
+    parent_id TEXT,
+    content TEXT NOT NULL,
+    status TEXT NOT NULL,
+    deleted_at TEXT,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+
+    PRIMARY KEY (id),
+    FOREIGN KEY (post_id) REFERENCES post(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE SET NULL,
+    FOREIGN KEY (parent_id) REFERENCES comment(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_comment_post_id ON comment(post_id);
+CREATE INDEX idx_comment_parent_id ON comment(parent_id);
+CREATE INDEX idx_comment_status ON comment(status);
\ No newline at end of file
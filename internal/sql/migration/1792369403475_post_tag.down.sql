@@ -0,0 +1 @@
+DROP TABLE IF EXISTS post_tag;
\ No newline at end of file
@@ -0,0 +1,10 @@
+CREATE TABLE post_tag (
+    post_id TEXT NOT NULL,
+    tag TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+
+    PRIMARY KEY (post_id, tag),
+    FOREIGN KEY (post_id) REFERENCES post(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_post_tag_tag ON post_tag(tag);
\ No newline at end of file
@@ -0,0 +1,20 @@
+CREATE TABLE attachment (
+    id TEXT NOT NULL,
+    post_id TEXT NOT NULL,
+    user_id TEXT,
+    file_name TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    size_bytes INTEGER NOT NULL,
+    width INTEGER,
+    height INTEGER,
+    storage_key TEXT NOT NULL,
+    thumbnail_storage_key TEXT,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+
+    PRIMARY KEY (id),
+    FOREIGN KEY (post_id) REFERENCES post(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_attachment_post_id ON attachment(post_id);
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS post_product;
+
+ALTER TABLE post DROP COLUMN visibility;
\ No newline at end of file
@@ -0,0 +1,13 @@
+ALTER TABLE post ADD COLUMN visibility TEXT NOT NULL DEFAULT 'public';
+
+CREATE TABLE post_product (
+    post_id TEXT NOT NULL,
+    product_id TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+
+    PRIMARY KEY (post_id, product_id),
+    FOREIGN KEY (post_id) REFERENCES post(id) ON DELETE CASCADE,
+    FOREIGN KEY (product_id) REFERENCES product(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_post_product_product_id ON post_product(product_id);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS entitlement;
\ No newline at end of file
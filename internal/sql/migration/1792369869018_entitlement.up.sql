@@ -0,0 +1,17 @@
+CREATE TABLE entitlement (
+    id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    product_id TEXT NOT NULL,
+    source TEXT NOT NULL,
+    starts_at TEXT NOT NULL,
+    ends_at TEXT,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+
+    PRIMARY KEY (id),
+    FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE,
+    FOREIGN KEY (product_id) REFERENCES product(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_entitlement_user_id ON entitlement(user_id);
+CREATE INDEX idx_entitlement_product_id ON entitlement(product_id);
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS post_translation;
+
+ALTER TABLE post DROP COLUMN language_code;
\ No newline at end of file
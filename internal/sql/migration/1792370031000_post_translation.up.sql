@@ -0,0 +1,14 @@
+ALTER TABLE post ADD COLUMN language_code TEXT NOT NULL DEFAULT 'en-US';
+
+CREATE TABLE post_translation (
+    post_id TEXT NOT NULL,
+    language_code TEXT NOT NULL,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL,
+    content TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+
+    PRIMARY KEY (post_id, language_code),
+    FOREIGN KEY (post_id) REFERENCES post(id) ON DELETE CASCADE
+);
\ No newline at end of file
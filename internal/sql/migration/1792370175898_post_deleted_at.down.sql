@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_post_deleted_at;
+
+ALTER TABLE post DROP COLUMN deleted_at;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE post ADD COLUMN deleted_at TEXT;
+
+CREATE INDEX idx_post_deleted_at ON post(deleted_at);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS post_collaborator;
\ No newline at end of file
@@ -0,0 +1,16 @@
+CREATE TABLE post_collaborator (
+    post_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    role TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+
+    PRIMARY KEY (post_id, user_id),
+    FOREIGN KEY (post_id) REFERENCES post(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_post_collaborator_user_id ON post_collaborator(user_id);
+
+INSERT INTO post_collaborator (post_id, user_id, role, created_at, updated_at)
+SELECT id, user_id, 'author', created_at, updated_at FROM post WHERE user_id IS NOT NULL;
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS series_post;
+
+DROP TABLE IF EXISTS series;
\ No newline at end of file
@@ -0,0 +1,25 @@
+CREATE TABLE series (
+    id TEXT NOT NULL,
+    user_id TEXT,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL,
+    slug TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+
+    PRIMARY KEY (id),
+    UNIQUE (slug),
+    FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE SET NULL
+);
+
+CREATE TABLE series_post (
+    series_id TEXT NOT NULL,
+    post_id TEXT NOT NULL,
+    position INTEGER NOT NULL,
+    created_at TEXT NOT NULL,
+
+    PRIMARY KEY (series_id, post_id),
+    UNIQUE (post_id),
+    FOREIGN KEY (series_id) REFERENCES series(id) ON DELETE CASCADE,
+    FOREIGN KEY (post_id) REFERENCES post(id) ON DELETE CASCADE
+);
\ No newline at end of file
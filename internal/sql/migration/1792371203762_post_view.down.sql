@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS post_view_daily;
+
+DROP TABLE IF EXISTS post_view;
\ No newline at end of file
@@ -0,0 +1,24 @@
+CREATE TABLE post_view (
+    post_id TEXT NOT NULL,
+    view_date TEXT NOT NULL,
+    visitor_hash TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+
+    PRIMARY KEY (post_id, view_date, visitor_hash),
+    FOREIGN KEY (post_id) REFERENCES post(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_post_view_view_date ON post_view(view_date);
+
+CREATE TABLE post_view_daily (
+    post_id TEXT NOT NULL,
+    view_date TEXT NOT NULL,
+    view_count INTEGER NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+
+    PRIMARY KEY (post_id, view_date),
+    FOREIGN KEY (post_id) REFERENCES post(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_post_view_daily_view_date ON post_view_daily(view_date);
\ No newline at end of file
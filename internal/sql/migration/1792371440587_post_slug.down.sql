@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_post_slug;
+
+ALTER TABLE post DROP COLUMN slug;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE post ADD COLUMN slug TEXT;
+
+CREATE UNIQUE INDEX idx_post_slug ON post(slug);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS bookmark;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS post_reaction;
\ No newline at end of file
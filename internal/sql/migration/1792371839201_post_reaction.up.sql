@@ -0,0 +1,12 @@
+CREATE TABLE post_reaction (
+    post_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    type TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+
+    PRIMARY KEY (post_id, user_id, type),
+    FOREIGN KEY (post_id) REFERENCES post(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_post_reaction_user_id ON post_reaction(user_id);
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE email DROP COLUMN list_unsubscribe_url;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE email ADD COLUMN list_unsubscribe_url TEXT;
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS newsletter_issue;
+
+DROP TABLE IF EXISTS newsletter_subscription;
\ No newline at end of file
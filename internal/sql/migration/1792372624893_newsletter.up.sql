@@ -0,0 +1,24 @@
+CREATE TABLE newsletter_subscription (
+    id TEXT NOT NULL,
+    user_id TEXT,
+    email TEXT,
+    token TEXT NOT NULL,
+    status TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+
+    PRIMARY KEY (id),
+    UNIQUE (user_id),
+    UNIQUE (email),
+    UNIQUE (token),
+    FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE
+);
+
+CREATE TABLE newsletter_issue (
+    post_id TEXT NOT NULL,
+    recipient_count INTEGER NOT NULL,
+    created_at TEXT NOT NULL,
+
+    PRIMARY KEY (post_id),
+    FOREIGN KEY (post_id) REFERENCES post(id) ON DELETE CASCADE
+);
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE queue_task DROP COLUMN run_at;
+
+ALTER TABLE queue_task DROP COLUMN attempt_count;
\ No newline at end of file
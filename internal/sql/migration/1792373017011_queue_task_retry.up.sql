@@ -0,0 +1,3 @@
+ALTER TABLE queue_task ADD COLUMN attempt_count INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE queue_task ADD COLUMN run_at TEXT;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS payment_event;
\ No newline at end of file
@@ -0,0 +1,16 @@
+CREATE TABLE payment_event (
+    id TEXT NOT NULL,
+    provider TEXT NOT NULL,
+    event_id TEXT NOT NULL,
+    type TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    status TEXT NOT NULL,
+    error TEXT,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+
+    PRIMARY KEY (id),
+    UNIQUE (provider, event_id)
+);
+
+CREATE INDEX idx_payment_event_type ON payment_event(type);
\ No newline at end of file
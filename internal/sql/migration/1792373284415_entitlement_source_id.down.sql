@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_entitlement_source_source_id;
+
+ALTER TABLE entitlement DROP COLUMN source_id;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE entitlement ADD COLUMN source_id TEXT;
+
+CREATE UNIQUE INDEX idx_entitlement_source_source_id ON entitlement(source, source_id);
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "order";
+DROP TABLE IF EXISTS subscription;
\ No newline at end of file
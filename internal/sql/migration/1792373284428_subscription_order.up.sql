@@ -0,0 +1,43 @@
+CREATE TABLE subscription (
+    id TEXT NOT NULL,
+    external_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    price_id TEXT NOT NULL,
+    status TEXT NOT NULL,
+    started_at TEXT NOT NULL,
+    current_period_start TEXT NOT NULL,
+    current_period_end TEXT,
+    cancel_at_period_end BOOLEAN NOT NULL,
+    canceled_at TEXT,
+    ended_at TEXT,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+
+    PRIMARY KEY (id),
+    UNIQUE (external_id),
+    FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE,
+    FOREIGN KEY (price_id) REFERENCES price(id)
+);
+
+CREATE INDEX idx_subscription_user_id ON subscription(user_id);
+
+CREATE TABLE "order" (
+    id TEXT NOT NULL,
+    external_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    price_id TEXT NOT NULL,
+    subscription_external_id TEXT,
+    status TEXT NOT NULL,
+    amount INTEGER NOT NULL,
+    currency TEXT NOT NULL,
+    ordered_at TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+
+    PRIMARY KEY (id),
+    UNIQUE (external_id),
+    FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE,
+    FOREIGN KEY (price_id) REFERENCES price(id)
+);
+
+CREATE INDEX idx_order_user_id ON "order"(user_id);
\ No newline at end of file
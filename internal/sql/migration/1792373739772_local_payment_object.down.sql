@@ -0,0 +1 @@
+DROP TABLE IF EXISTS local_payment_object;
\ No newline at end of file
@@ -0,0 +1,12 @@
+CREATE TABLE local_payment_object (
+    id TEXT NOT NULL,
+    type TEXT NOT NULL,
+    customer_id TEXT,
+    data TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_local_payment_object_type_customer_id ON local_payment_object(type, customer_id);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS payment_reconciliation_issue;
\ No newline at end of file
@@ -0,0 +1,12 @@
+CREATE TABLE payment_reconciliation_issue (
+    id TEXT NOT NULL,
+    object_type TEXT NOT NULL,
+    code TEXT NOT NULL,
+    local_id TEXT,
+    external_id TEXT,
+    message TEXT NOT NULL,
+    is_fixed BOOLEAN NOT NULL,
+    created_at TEXT NOT NULL,
+
+    PRIMARY KEY (id)
+);
\ No newline at end of file
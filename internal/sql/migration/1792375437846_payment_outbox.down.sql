@@ -0,0 +1,5 @@
+ALTER TABLE price DROP COLUMN payment_sync_status;
+
+ALTER TABLE "user" DROP COLUMN payment_sync_status;
+
+DROP TABLE IF EXISTS payment_outbox;
\ No newline at end of file
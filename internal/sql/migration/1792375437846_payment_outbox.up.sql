@@ -0,0 +1,25 @@
+CREATE TABLE payment_outbox (
+    id TEXT NOT NULL,
+    type TEXT NOT NULL,
+    user_id TEXT,
+    price_id TEXT,
+    customer_external_id TEXT,
+    idempotency_key TEXT NOT NULL,
+    status TEXT NOT NULL,
+    error TEXT,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+
+    PRIMARY KEY (id),
+    UNIQUE (idempotency_key),
+    FOREIGN KEY (user_id) REFERENCES "user"(id) ON DELETE CASCADE,
+    FOREIGN KEY (price_id) REFERENCES price(id)
+);
+
+CREATE INDEX idx_payment_outbox_user_id ON payment_outbox(user_id);
+
+CREATE INDEX idx_payment_outbox_price_id ON payment_outbox(price_id);
+
+ALTER TABLE "user" ADD COLUMN payment_sync_status TEXT NOT NULL DEFAULT 'synced';
+
+ALTER TABLE price ADD COLUMN payment_sync_status TEXT NOT NULL DEFAULT 'synced';
\ No newline at end of file
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS discount_reservation;
+
+DROP TABLE IF EXISTS discount_redemption;
+
+DROP TABLE IF EXISTS discount_price;
+
+DROP TABLE IF EXISTS discount;
\ No newline at end of file
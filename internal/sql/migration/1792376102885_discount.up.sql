@@ -0,0 +1,60 @@
+CREATE TABLE discount (
+    id TEXT NOT NULL,
+    external_id TEXT NOT NULL,
+    code TEXT NOT NULL,
+    name TEXT NOT NULL,
+    type TEXT NOT NULL,
+    amount INTEGER NOT NULL,
+    currency TEXT,
+    duration TEXT NOT NULL,
+    duration_in_months INTEGER,
+    redemption_max INTEGER,
+    redemption_count INTEGER NOT NULL,
+    reserved_count INTEGER NOT NULL,
+    expires_at TEXT,
+    is_active BOOLEAN NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+
+    PRIMARY KEY (id),
+    UNIQUE (external_id),
+    UNIQUE (code)
+);
+
+CREATE TABLE discount_price (
+    discount_id TEXT NOT NULL,
+    price_id TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+
+    PRIMARY KEY (discount_id, price_id),
+    FOREIGN KEY (discount_id) REFERENCES discount(id) ON DELETE CASCADE,
+    FOREIGN KEY (price_id) REFERENCES price(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_discount_price_price_id ON discount_price(price_id);
+
+CREATE TABLE discount_redemption (
+    discount_id TEXT NOT NULL,
+    source TEXT NOT NULL,
+    source_id TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+
+    PRIMARY KEY (discount_id, source, source_id),
+    FOREIGN KEY (discount_id) REFERENCES discount(id) ON DELETE CASCADE
+);
+
+CREATE TABLE discount_reservation (
+    id TEXT NOT NULL,
+    discount_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    checkout_external_id TEXT,
+    expires_at TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+
+    PRIMARY KEY (id),
+    FOREIGN KEY (discount_id) REFERENCES discount(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_discount_reservation_discount_id_user_id ON discount_reservation(discount_id, user_id);
+CREATE INDEX idx_discount_reservation_checkout_external_id ON discount_reservation(checkout_external_id);
+CREATE INDEX idx_discount_reservation_expires_at ON discount_reservation(expires_at);
\ No newline at end of file
@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/jljl1337/issho/internal/env"
+)
+
+// ErrObjectNotFound is returned by Get when no object is stored under the key.
+var ErrObjectNotFound = errors.New("object not found")
+
+type Storage interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// The caller must close the returned reader
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Deleting a key that does not exist is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+func NewStorage(providerName string) Storage {
+	switch providerName {
+	case "local":
+		return NewLocalStorage(env.StorageLocalPath)
+	case "s3":
+		return NewS3Storage(S3StorageParams{
+			Endpoint:        env.S3Endpoint,
+			Region:          env.S3Region,
+			Bucket:          env.S3Bucket,
+			AccessKeyID:     env.S3AccessKeyID,
+			SecretAccessKey: env.S3SecretAccessKey,
+			UsePathStyle:    env.S3UsePathStyle,
+		})
+	default:
+		return nil
+	}
+}
@@ -63,12 +63,14 @@ export function useUpdatePost() {
     mutationFn: ({
       id,
       params,
+      version,
       csrfToken,
     }: {
       id: string;
       params: UpdatePostParams;
+      version: string;
       csrfToken: string;
-    }) => updatePostApi(id, params, csrfToken),
+    }) => updatePostApi(id, params, version, csrfToken),
     onSuccess: () => {
       queryClient.invalidateQueries({ queryKey: ["posts"] });
     },
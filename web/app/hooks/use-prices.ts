@@ -62,12 +62,14 @@ export function useUpdatePrice() {
     mutationFn: ({
       id,
       params,
+      version,
       csrfToken,
     }: {
       id: string;
       params: UpdatePriceParams;
+      version: string;
       csrfToken: string;
-    }) => updatePriceApi(id, params, csrfToken),
+    }) => updatePriceApi(id, params, version, csrfToken),
     onSuccess: () => {
       queryClient.invalidateQueries({ queryKey: ["prices"] });
     },
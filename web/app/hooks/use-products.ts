@@ -62,12 +62,14 @@ export function useUpdateProduct() {
     mutationFn: ({
       id,
       params,
+      version,
       csrfToken,
     }: {
       id: string;
       params: UpdateProductParams;
+      version: string;
       csrfToken: string;
-    }) => updateProductApi(id, params, csrfToken),
+    }) => updateProductApi(id, params, version, csrfToken),
     onSuccess: () => {
       queryClient.invalidateQueries({ queryKey: ["products"] });
     },
@@ -5,6 +5,7 @@
  * @param method The HTTP method (GET, POST, etc.)
  * @param body Optional request body (will be `JSON.stringify`-ed)
  * @param csrfToken Optional CSRF token to include in the headers
+ * @param headers Optional extra headers, e.g. `If-Match`
  * @returns The fetch response
  */
 export function customFetch(
@@ -12,6 +13,7 @@ export function customFetch(
   method: string,
   body?: any,
   csrfToken?: string,
+  headers?: Record<string, string>,
 ) {
   let options: RequestInit = {
     method,
@@ -29,6 +31,12 @@ export function customFetch(
       "X-CSRF-Token": csrfToken,
     };
   }
+  if (headers) {
+    options.headers = {
+      ...options.headers,
+      ...headers,
+    };
+  }
   return fetch(getUrl(path), options);
 }
 
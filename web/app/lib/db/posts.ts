@@ -94,9 +94,14 @@ export type UpdatePostParams = {
   publishedAt?: string | null;
 };
 
+/**
+ * Updates the post if it has not been modified since it was read.
+ * @param version The `updatedAt` of the post when it was read
+ */
 export async function updatePost(
   id: string,
   params: UpdatePostParams,
+  version: string,
   csrfToken: string,
 ): Promise<void> {
   const response = await customFetch(
@@ -104,6 +109,7 @@ export async function updatePost(
     "PUT",
     params,
     csrfToken,
+    { "If-Match": `"${version}"` },
   );
 
   await throwIfError(response);
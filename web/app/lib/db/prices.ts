@@ -88,9 +88,14 @@ export type UpdatePriceParams = {
   isActive: boolean;
 };
 
+/**
+ * Updates the price if it has not been modified since it was read.
+ * @param version The `updatedAt` of the price when it was read
+ */
 export async function updatePrice(
   id: string,
   params: UpdatePriceParams,
+  version: string,
   csrfToken: string,
 ): Promise<void> {
   const response = await customFetch(
@@ -98,6 +103,7 @@ export async function updatePrice(
     "PUT",
     params,
     csrfToken,
+    { "If-Match": `"${version}"` },
   );
 
   await throwIfError(response);
@@ -77,9 +77,14 @@ export type UpdateProductParams = {
   isActive: boolean;
 };
 
+/**
+ * Updates the product if it has not been modified since it was read.
+ * @param version The `updatedAt` of the product when it was read
+ */
 export async function updateProduct(
   id: string,
   params: UpdateProductParams,
+  version: string,
   csrfToken: string,
 ): Promise<void> {
   const response = await customFetch(
@@ -87,6 +92,7 @@ export async function updateProduct(
     "PUT",
     params,
     csrfToken,
+    { "If-Match": `"${version}"` },
   );
 
   await throwIfError(response);
@@ -58,7 +58,7 @@ export default function Page() {
     content: string;
     publishedAt: string | null;
   }) => {
-    if (!csrfToken || !id) {
+    if (!csrfToken || !id || !post) {
       setErrorMessage("No CSRF token or post ID available");
       return;
     }
@@ -69,6 +69,7 @@ export default function Page() {
       await updatePost.mutateAsync({
         id,
         params: data,
+        version: post.updatedAt,
         csrfToken,
       });
       navigate("/admin/posts");
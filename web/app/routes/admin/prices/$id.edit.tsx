@@ -58,7 +58,7 @@ export default function Page() {
     recurringIntervalCount: number | null;
     isActive: boolean;
   }) => {
-    if (!csrfToken || !id) {
+    if (!csrfToken || !id || !price) {
       setErrorMessage(t("noCsrfToken"));
       return;
     }
@@ -69,6 +69,7 @@ export default function Page() {
       await updatePrice.mutateAsync({
         id,
         params: data,
+        version: price.updatedAt,
         csrfToken,
       });
       navigate("/admin/prices");
@@ -52,7 +52,7 @@ export default function Page() {
     description: string;
     isActive: boolean;
   }) => {
-    if (!csrfToken || !id) {
+    if (!csrfToken || !id || !product) {
       setErrorMessage(t("noCsrfToken"));
       return;
     }
@@ -63,6 +63,7 @@ export default function Page() {
       await updateProduct.mutateAsync({
         id,
         params: data,
+        version: product.updatedAt,
         csrfToken,
       });
       navigate("/admin/products");